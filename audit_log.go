@@ -0,0 +1,343 @@
+package stonesthrow
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	kAuditLogFile           = "audit.log"
+	kDefaultMaxAuditLogSize = 32 * 1024 * 1024
+)
+
+// AuditLog records who made each RPC to the server and what came of it. The
+// records are appended to a file as JSON lines and are never modified. Once
+// the file grows beyond MaxSize bytes, it's moved to Path + ".1", replacing
+// the records that were moved there before. Hence the log takes up at most
+// twice MaxSize.
+type AuditLog struct {
+	Path    string
+	MaxSize int64
+
+	mutex     sync.Mutex
+	file      *os.File
+	size      int64
+	lastRpcId int64
+}
+
+// OpenAuditLog opens the audit log at |path| for appending, creating it if
+// necessary.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	l := &AuditLog{Path: path, MaxSize: kDefaultMaxAuditLogSize}
+	err := l.open()
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *AuditLog) rotatedPath() string {
+	return l.Path + ".1"
+}
+
+// open opens the file at |Path| for appending. Must be called with |mutex|
+// held once the log is in use.
+func (l *AuditLog) open() error {
+	file, err := os.OpenFile(l.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and starts a new one. Must be called
+// with |mutex| held.
+func (l *AuditLog) rotate() error {
+	l.file.Close()
+	l.file = nil
+	rename_err := os.Rename(l.Path, l.rotatedPath())
+	err := l.open()
+	if rename_err != nil {
+		return rename_err
+	}
+	return err
+}
+
+func (l *AuditLog) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Record appends |record| to the log.
+func (l *AuditLog) Record(record *AuditRecord) error {
+	if record.Time == nil {
+		record.Time = TimestampNow()
+	}
+	line, err := kJsonMarshaler.MarshalToString(record)
+	if err != nil {
+		return err
+	}
+	line += "\n"
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	if l.MaxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.MaxSize {
+		err = l.rotate()
+		if err != nil {
+			return err
+		}
+	}
+	count, err := l.file.WriteString(line)
+	l.size += int64(count)
+	return err
+}
+
+func (o *AuditLogOptions) matches(record *AuditRecord) bool {
+	switch {
+	case o.GetIdentity() != "" && record.GetIdentity() != o.GetIdentity():
+		return false
+	case o.GetMethod() != "" && !strings.Contains(record.GetMethod(), o.GetMethod()):
+		return false
+	case o.GetRepository() != "" && record.GetRepository() != o.GetRepository():
+		return false
+	case o.GetPlatform() != "" && record.GetPlatform() != o.GetPlatform():
+		return false
+	case o.GetSince() != nil && TimeFromTimestamp(record.GetTime()).Before(TimeFromTimestamp(o.GetSince())):
+		return false
+	case o.GetFailedOnly() && record.GetError() == "":
+		return false
+	}
+	return true
+}
+
+// readRecords appends the records in the file at |path| that match |options|
+// to |matching|. A missing file doesn't have any records.
+func readRecords(path string, options *AuditLogOptions, matching []*AuditRecord) ([]*AuditRecord, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return matching, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			break
+		}
+		var record AuditRecord
+		if kJsonUnmarshaler.Unmarshal(bytes.NewReader(line), &record) != nil {
+			continue
+		}
+		if options.matches(&record) {
+			matching = append(matching, &record)
+		}
+	}
+	return matching, nil
+}
+
+// Query returns the records that match |options|, most recent first.
+func (l *AuditLog) Query(options *AuditLogOptions) (*AuditRecords, error) {
+	matching := []*AuditRecord{}
+	for _, path := range []string{l.rotatedPath(), l.Path} {
+		var err error
+		matching, err = readRecords(path, options, matching)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	records := &AuditRecords{}
+	for i := len(matching) - 1; i >= 0; i-- {
+		records.Record = append(records.Record, matching[i])
+		if options.GetMaxRecords() > 0 && len(records.Record) >= int(options.GetMaxRecords()) {
+			break
+		}
+	}
+	return records, nil
+}
+
+// newRecord starts the record for an RPC to |method| being served with |ctx|.
+// Clients that couldn't be identified are recorded by address.
+func (l *AuditLog) newRecord(ctx context.Context, method string) *AuditRecord {
+	record := &AuditRecord{Method: method, RpcId: atomic.AddInt64(&l.lastRpcId, 1)}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			record.Address = p.Addr.String()
+		}
+		record.Identity, _, _ = getPeerIdentity(p)
+	}
+	return record
+}
+
+func (r *AuditRecord) addJobId(id int32) {
+	if id == 0 {
+		return
+	}
+	for _, existing := range r.JobId {
+		if existing == id {
+			return
+		}
+	}
+	r.JobId = append(r.JobId, id)
+}
+
+// describeRequest fills in the details of the RPC from its request message.
+func (r *AuditRecord) describeRequest(request interface{}) {
+	if command_input, ok := request.(*CommandInput); ok {
+		request = command_input.GetOptions()
+	}
+	if m, ok := request.(interface{ GetRepository() string }); ok {
+		r.Repository = m.GetRepository()
+	}
+	if m, ok := request.(interface{ GetPlatform() string }); ok {
+		r.Platform = m.GetPlatform()
+	}
+	switch m := request.(type) {
+	case *RunOptions:
+		r.Command = m.GetCommand().GetCommand()
+		r.Shell = m.GetShell() || strings.Contains(r.Method, "Shell")
+	case *KillJobsOptions:
+		for _, id := range m.GetId() {
+			r.addJobId(id)
+		}
+	case *AttachJobOptions:
+		r.addJobId(m.GetId())
+	case *JobLogOptions:
+		r.addJobId(m.GetId())
+	}
+}
+
+// describeResponse records the jobs started by the RPC.
+func (r *AuditRecord) describeResponse(response interface{}) {
+	switch m := response.(type) {
+	case *JobEvent:
+		r.addJobId(m.GetBeginCommandEvent().GetJobId())
+	case *TaskEvent:
+		for _, task := range m.GetTask() {
+			r.addJobId(task.GetId())
+		}
+	}
+}
+
+// write appends |record| to |l| and logs failures. RPCs aren't failed on
+// account of the audit log.
+func (l *AuditLog) write(record *AuditRecord) {
+	if err := l.Record(record); err != nil {
+		log.Printf("Can't write to the audit log: %s", err.Error())
+	}
+}
+
+// start records that the RPC described by |record| has arrived.
+func (l *AuditLog) start(record *AuditRecord) {
+	started := proto.Clone(record).(*AuditRecord)
+	started.Time = TimestampNow()
+	started.Started = true
+	l.write(started)
+}
+
+// finish records the result of the RPC described by |record|.
+func (l *AuditLog) finish(record *AuditRecord, err error) {
+	s := status.Convert(err)
+	record.Time = TimestampNow()
+	record.Code = s.Code().String()
+	record.Error = s.Message()
+	l.write(record)
+}
+
+// unaryServerInterceptor records each RPC before and after passing it on to
+// |next|. A nil AuditLog doesn't record anything.
+func (l *AuditLog) unaryServerInterceptor(next grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if l == nil {
+			return next(ctx, req, info, handler)
+		}
+		record := l.newRecord(ctx, info.FullMethod)
+		record.describeRequest(req)
+		l.start(record)
+		resp, err := next(ctx, req, info, handler)
+		l.finish(record, err)
+		return resp, err
+	}
+}
+
+// auditedServerStream fills in |record| from the messages sent and received
+// via a stream. The request only arrives once the handler receives it, so
+// that's when the start of the RPC is recorded.
+type auditedServerStream struct {
+	grpc.ServerStream
+	log *AuditLog
+
+	mutex    sync.Mutex
+	record   *AuditRecord
+	received bool
+}
+
+func (s *auditedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err == nil && !s.received {
+		s.received = true
+		s.record.describeRequest(m)
+		s.log.start(s.record)
+	}
+	return err
+}
+
+func (s *auditedServerStream) SendMsg(m interface{}) error {
+	s.mutex.Lock()
+	s.record.describeResponse(m)
+	s.mutex.Unlock()
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *auditedServerStream) finish(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.log.finish(s.record, err)
+}
+
+func (l *AuditLog) streamServerInterceptor(next grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if l == nil {
+			return next(srv, ss, info, handler)
+		}
+		stream := &auditedServerStream{
+			ServerStream: ss, log: l, record: l.newRecord(ss.Context(), info.FullMethod)}
+		err := next(srv, stream, info, handler)
+		stream.finish(err)
+		return err
+	}
+}
@@ -0,0 +1,258 @@
+package stonesthrow
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLogQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	audit_log, err := OpenAuditLog(filepath.Join(dir, kAuditLogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i, identity := range []string{"alice", "bob", "alice"} {
+		err = audit_log.Record(&AuditRecord{
+			Time:     NewTimestampFromTime(start.Add(time.Duration(i) * time.Minute)),
+			Identity: identity,
+			Method:   "/stonesthrow.ServiceHost/Ping",
+			Command:  []string{fmt.Sprint(i)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	audit_log.Close()
+
+	// Records are appended to existing ones.
+	audit_log, err = OpenAuditLog(filepath.Join(dir, kAuditLogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audit_log.Close()
+	err = audit_log.Record(&AuditRecord{Identity: "bob", Method: "/stonesthrow.ServiceHost/Shutdown", Error: "denied"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		options  AuditLogOptions
+		expected []string
+	}{
+		{AuditLogOptions{}, []string{"", "2", "1", "0"}},
+		{AuditLogOptions{MaxRecords: 2}, []string{"", "2"}},
+		{AuditLogOptions{Identity: "alice"}, []string{"2", "0"}},
+		{AuditLogOptions{Method: "Ping", Since: NewTimestampFromTime(start.Add(time.Minute))}, []string{"2", "1"}},
+		{AuditLogOptions{FailedOnly: true}, []string{""}},
+	} {
+		records, err := audit_log.Query(&test.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commands := []string{}
+		for _, record := range records.GetRecord() {
+			commands = append(commands, strings.Join(record.GetCommand(), " "))
+		}
+		if fmt.Sprint(commands) != fmt.Sprint(test.expected) {
+			t.Fatalf("unexpected records for %v: %v", test.options, commands)
+		}
+	}
+}
+
+func TestAuditLogRecordsDeniedRpcs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	audit_log, err := OpenAuditLog(filepath.Join(dir, kAuditLogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audit_log.Close()
+
+	host := &HostConfig{Name: "a", Authorization: map[string]string{"bob": "read-only"}}
+	err = host.Normalize(&HostsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	interceptor := audit_log.unaryServerInterceptor(NewAuthorizer(host).unaryServerInterceptor)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "bob"}}}}}}})
+	_, err = interceptor(ctx, &ShutdownOptions{}, &grpc.UnaryServerInfo{FullMethod: "/stonesthrow.Host/Shutdown"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("unexpected call")
+			return nil, nil
+		})
+	if !IsPermissionDeniedError(FromRpcError(err)) {
+		t.Fatalf("expected PermissionDeniedError. got %v", err)
+	}
+
+	records, err := audit_log.Query(&AuditLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.GetRecord()) != 2 {
+		t.Fatalf("unexpected records %v", records)
+	}
+	record, started := records.GetRecord()[0], records.GetRecord()[1]
+	if record.GetIdentity() != "bob" || record.GetAddress() != "10.0.0.1:1234" ||
+		record.GetMethod() != "/stonesthrow.Host/Shutdown" || record.GetCode() != "PermissionDenied" ||
+		record.GetStarted() {
+		t.Fatalf("unexpected record %v", record)
+	}
+	// The RPC is recorded before it's authorized.
+	if !started.GetStarted() || started.GetRpcId() != record.GetRpcId() || started.GetIdentity() != "bob" ||
+		started.GetCode() != "" {
+		t.Fatalf("unexpected record %v", started)
+	}
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	audit_log, err := OpenAuditLog(filepath.Join(dir, kAuditLogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audit_log.Close()
+	audit_log.MaxSize = 1024
+
+	for i := 0; i < 100; i++ {
+		err = audit_log.Record(&AuditRecord{Identity: "alice", Command: []string{fmt.Sprint(i)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{audit_log.Path, audit_log.rotatedPath()} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > audit_log.MaxSize {
+			t.Fatalf("%s is %d bytes", path, info.Size())
+		}
+	}
+
+	// The most recent records are still there. The oldest are gone.
+	records, err := audit_log.Query(&AuditLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := len(records.GetRecord())
+	if count < 10 || count >= 100 || strings.Join(records.GetRecord()[0].GetCommand(), "") != "99" ||
+		strings.Join(records.GetRecord()[count-1].GetCommand(), "") != fmt.Sprint(100-count) {
+		t.Fatalf("unexpected records %v", records)
+	}
+}
+
+func TestAuditLogRpcs(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("peer credentials aren't supported")
+	}
+
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	audit_log, err := OpenAuditLog(filepath.Join(dir, kAuditLogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audit_log.Close()
+
+	endpoint := Endpoint{Network: "unix", Address: filepath.Join(dir, "st.sock")}
+	listener, err := listenOnEndpoint(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := &HostConfig{Name: "a"}
+	server := grpc.NewServer(append(RpcServerOptions(NewAuthorizer(host), audit_log),
+		grpc.Creds(NewLocalTransportCredentials(nil)))...)
+	RegisterServiceHostServer(server, &ServiceHostServerImpl{
+		Config: Config{Host: host}, Jobs: NewJobRegistry(), Audit: audit_log})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := connectToLocalEndpoint(context.Background(), Config{Host: host}, endpoint, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := NewServiceHostClient(conn)
+
+	_, err = client.GetJobHistory(context.Background(), &JobHistoryOptions{Repository: "chrome", Platform: "linux"})
+	if !IsNothingToDoError(err) {
+		t.Fatalf("expected NothingToDoError. got %v", err)
+	}
+	stream, err := client.KillJobs(context.Background(), &KillJobsOptions{Id: []int32{3, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if err != io.EOF {
+		t.Fatal(err)
+	}
+
+	records, err := client.GetAuditLog(context.Background(), &AuditLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each RPC is recorded when it starts and when it finishes, including the
+	// GetAuditLog RPC that's in progress.
+	if len(records.GetRecord()) != 5 {
+		t.Fatalf("unexpected records %v", records)
+	}
+	identity := fmt.Sprintf("uid:%d", os.Getuid())
+	audit, kill, kill_started, history, history_started := records.GetRecord()[0], records.GetRecord()[1],
+		records.GetRecord()[2], records.GetRecord()[3], records.GetRecord()[4]
+	if audit.GetMethod() != "/stonesthrow.ServiceHost/GetAuditLog" || !audit.GetStarted() {
+		t.Fatalf("unexpected record %v", audit)
+	}
+	if kill.GetIdentity() != identity || kill.GetMethod() != "/stonesthrow.ServiceHost/KillJobs" ||
+		fmt.Sprint(kill.GetJobId()) != "[3 4]" || kill.GetCode() != "OK" || kill.GetError() != "" {
+		t.Fatalf("unexpected record %v", kill)
+	}
+	if !kill_started.GetStarted() || kill_started.GetRpcId() != kill.GetRpcId() ||
+		fmt.Sprint(kill_started.GetJobId()) != "[3 4]" || kill_started.GetCode() != "" {
+		t.Fatalf("unexpected record %v", kill_started)
+	}
+	if history.GetRepository() != "chrome" || history.GetPlatform() != "linux" ||
+		history.GetCode() != "FailedPrecondition" || history.GetError() == "" {
+		t.Fatalf("unexpected record %v", history)
+	}
+	if !history_started.GetStarted() || history_started.GetRepository() != "chrome" ||
+		history_started.GetRpcId() == kill.GetRpcId() {
+		t.Fatalf("unexpected record %v", history_started)
+	}
+}
@@ -0,0 +1,230 @@
+package stonesthrow
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Role determines the RPCs that a client is allowed to make. Each role is
+// allowed to do everything that the roles before it can do.
+type Role int
+
+const (
+	RoleNone Role = iota
+
+	// Can query the server, e.g. list jobs and commands, but can't change
+	// anything.
+	RoleReadOnly
+
+	// Can run script commands and sync repositories.
+	RoleBuild
+
+	// Can run arbitrary shell commands, and shut down or update the server.
+	RoleAdmin
+)
+
+var kRoleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleReadOnly: "read-only",
+	RoleBuild:    "build",
+	RoleAdmin:    "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := kRoleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("role(%d)", int(r))
+}
+
+// ParseRole returns the role called |name|.
+func ParseRole(name string) (Role, error) {
+	for role, role_name := range kRoleNames {
+		if role_name == name {
+			return role, nil
+		}
+	}
+	return RoleNone, NewConfigurationError("unknown role \"%s\"", name)
+}
+
+// kMethodRoles are the roles required for each RPC. RPCs that aren't listed
+// require RoleAdmin.
+var kMethodRoles = map[string]Role{
+	"/stonesthrow.BuildHost/RunScriptCommand":               RoleBuild,
+	"/stonesthrow.BuildHost/ListScriptCommands":             RoleReadOnly,
+	"/stonesthrow.BuildHost/ListTargets":                    RoleReadOnly,
+	"/stonesthrow.BuildHost/RunShellCommand":                RoleAdmin,
+	"/stonesthrow.BuildHost/FetchFile":                      RoleReadOnly,
+	"/stonesthrow.BuildHost/RunScriptCommandWithInput":      RoleBuild,
+	"/stonesthrow.BuildHost/RunShellCommandWithInput":       RoleAdmin,
+	"/stonesthrow.RepositoryHost/RunScriptCommand":          RoleBuild,
+	"/stonesthrow.RepositoryHost/ListScriptCommands":        RoleReadOnly,
+	"/stonesthrow.RepositoryHost/RunShellCommand":           RoleAdmin,
+	"/stonesthrow.RepositoryHost/GetBranchConfig":           RoleReadOnly,
+	"/stonesthrow.RepositoryHost/SetBranchConfig":           RoleBuild,
+	"/stonesthrow.RepositoryHost/PullFromUpstream":          RoleBuild,
+	"/stonesthrow.RepositoryHost/PushToUpstream":            RoleBuild,
+	"/stonesthrow.RepositoryHost/Status":                    RoleReadOnly,
+	"/stonesthrow.RepositoryHost/SyncRemote":                RoleBuild,
+	"/stonesthrow.RepositoryHost/PrepareForReceive":         RoleBuild,
+	"/stonesthrow.RepositoryHost/FetchFile":                 RoleReadOnly,
+	"/stonesthrow.RepositoryHost/RunScriptCommandWithInput": RoleBuild,
+	"/stonesthrow.RepositoryHost/RunShellCommandWithInput":  RoleAdmin,
+	"/stonesthrow.ServiceHost/Hello":                        RoleReadOnly,
+	"/stonesthrow.ServiceHost/Ping":                         RoleReadOnly,
+	"/stonesthrow.ServiceHost/ListJobs":                     RoleReadOnly,
+	"/stonesthrow.ServiceHost/KillJobs":                     RoleBuild,
+	"/stonesthrow.ServiceHost/AttachJob":                    RoleReadOnly,
+	"/stonesthrow.ServiceHost/GetJobHistory":                RoleReadOnly,
+	"/stonesthrow.ServiceHost/GetJobLog":                    RoleReadOnly,
+	"/stonesthrow.ServiceHost/Shutdown":                     RoleAdmin,
+	"/stonesthrow.ServiceHost/SelfUpdate":                   RoleAdmin,
+	"/stonesthrow.ServiceHost/GetAuditLog":                  RoleAdmin,
+	"/stonesthrow.Host/Hello":                               RoleReadOnly,
+	"/stonesthrow.Host/Ping":                                RoleReadOnly,
+	"/stonesthrow.Host/ListJobs":                            RoleReadOnly,
+	"/stonesthrow.Host/KillJobs":                            RoleBuild,
+	"/stonesthrow.Host/Shutdown":                            RoleAdmin,
+	"/stonesthrow.Host/SelfUpdate":                          RoleAdmin,
+	"/stonesthrow.Host/RunCommand":                          RoleBuild, // Shell commands need RoleAdmin.
+	"/stonesthrow.Host/ListCommands":                        RoleReadOnly,
+	"/stonesthrow.Host/FetchFile":                           RoleReadOnly,
+	"/stonesthrow.Host/ExtractRefs":                         RoleReadOnly,
+	"/stonesthrow.Host/ApplyRefs":                           RoleBuild,
+	"/stonesthrow.Host/GetKnownRefs":                        RoleReadOnly,
+	"/stonesthrow.Host/GetAuditLog":                         RoleAdmin,
+}
+
+// Caller is the authenticated client of an RPC.
+type Caller struct {
+	Identity string
+	Role     Role
+}
+
+type callerContextKey struct{}
+
+// CallerFromContext returns the caller of the RPC being served with |ctx|.
+// Returns false if the server doesn't authorize RPCs.
+func CallerFromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(Caller)
+	return caller, ok
+}
+
+// RequireRole returns an error unless the caller of the RPC being served with
+// |ctx| has at least |role|. Used for RPCs where the required role depends on
+// the request.
+func RequireRole(ctx context.Context, role Role) error {
+	caller, ok := CallerFromContext(ctx)
+	if !ok || caller.Role >= role {
+		return nil
+	}
+	return NewPermissionDeniedError("%s is %s, but %s is required", caller.Identity, caller.Role, role)
+}
+
+// Authorizer decides what clients of a server are allowed to do. Clients that
+// connect via TLS are identified by the common name of their certificate, and
+// are assigned roles based on the authorization section of the host
+// configuration. Clients that connect via unix sockets are run by the same
+// user as the server and are admins.
+type Authorizer struct {
+	roles map[string]Role
+}
+
+// NewAuthorizer returns an Authorizer for the server on |host|. If the host
+// doesn't have an authorization section, every client with a valid
+// certificate is an admin.
+func NewAuthorizer(host *HostConfig) *Authorizer {
+	return &Authorizer{roles: host.Roles}
+}
+
+func (a *Authorizer) roleOf(identity string) Role {
+	if len(a.roles) == 0 {
+		return RoleAdmin
+	}
+	if role, ok := a.roles[identity]; ok {
+		return role
+	}
+	return a.roles["*"]
+}
+
+// getPeerIdentity returns the identity of |p| based on the credentials it
+// presented. |local| is true if |p| connected via a unix socket.
+func getPeerIdentity(p *peer.Peer) (identity string, local bool, err error) {
+	switch auth_info := p.AuthInfo.(type) {
+	case PeerCredentials:
+		return fmt.Sprintf("uid:%d", auth_info.Uid), true, nil
+
+	case credentials.TLSInfo:
+		chains := auth_info.State.VerifiedChains
+		if len(chains) == 0 || len(chains[0]) == 0 {
+			return "", false, NewPermissionDeniedError("%s didn't present a client certificate", p.Addr)
+		}
+		return chains[0][0].Subject.CommonName, false, nil
+	}
+	return "", false, NewPermissionDeniedError("%s isn't authenticated", p.Addr)
+}
+
+// GetCaller returns the caller of the RPC being served with |ctx| based on the
+// credentials presented by the peer.
+func (a *Authorizer) GetCaller(ctx context.Context) (Caller, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Caller{}, NewPermissionDeniedError("unknown peer")
+	}
+	identity, local, err := getPeerIdentity(p)
+	if err != nil {
+		return Caller{}, err
+	}
+	if local {
+		return Caller{Identity: identity, Role: RoleAdmin}, nil
+	}
+	return Caller{Identity: identity, Role: a.roleOf(identity)}, nil
+}
+
+// authorize returns a context that carries the caller if the caller is
+// allowed to call |method|. A nil Authorizer allows everything.
+func (a *Authorizer) authorize(ctx context.Context, method string) (context.Context, error) {
+	if a == nil {
+		return ctx, nil
+	}
+	caller, err := a.GetCaller(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	ctx = context.WithValue(ctx, callerContextKey{}, caller)
+	required_role, ok := kMethodRoles[method]
+	if !ok {
+		required_role = RoleAdmin
+	}
+	return ctx, RequireRole(ctx, required_role)
+}
+
+func (a *Authorizer) unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, ToRpcError(err, wantsErrorStacks(ctx))
+	}
+	return unaryServerErrorInterceptor(ctx, req, info, handler)
+}
+
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authorizedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (a *Authorizer) streamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return ToRpcError(err, wantsErrorStacks(ctx))
+	}
+	return streamServerErrorInterceptor(srv, authorizedServerStream{ss, ctx}, info, handler)
+}
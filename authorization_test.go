@@ -0,0 +1,198 @@
+package stonesthrow
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCertificate creates a certificate for |name| signed by |issuer|, or
+// a self-signed CA certificate if |issuer| is nil. The certificate and key are
+// written to |dir|.
+func newTestCertificate(t *testing.T, dir string, name string, issuer *testCertificate) (*testCertificate, *CertificateLocator) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}}
+	parent, parent_key := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, parent_key = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parent_key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key_der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locator := &CertificateLocator{
+		CertificateFile: filepath.Join(dir, name+".crt"),
+		KeyFile:         filepath.Join(dir, name+".key")}
+	err = ioutil.WriteFile(locator.CertificateFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(locator.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key_der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{cert: cert, key: key}, locator
+}
+
+func TestAuthorizerRoles(t *testing.T) {
+	host := &HostConfig{Name: "a", Authorization: map[string]string{"alice": "admin", "*": "read-only"}}
+	err := host.Normalize(&HostsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer := NewAuthorizer(host)
+	if role := authorizer.roleOf("alice"); role != RoleAdmin {
+		t.Fatalf("unexpected role %s", role)
+	}
+	if role := authorizer.roleOf("bob"); role != RoleReadOnly {
+		t.Fatalf("unexpected role %s", role)
+	}
+
+	// Without an authorization section, everyone is an admin.
+	if role := NewAuthorizer(&HostConfig{}).roleOf("bob"); role != RoleAdmin {
+		t.Fatalf("unexpected role %s", role)
+	}
+
+	host = &HostConfig{Name: "a", Authorization: map[string]string{"alice": "root"}}
+	if err = host.Normalize(&HostsConfig{}); !IsConfigurationError(err) {
+		t.Fatalf("expected ConfigurationError. got %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), callerContextKey{}, Caller{Identity: "bob", Role: RoleBuild})
+	if err = RequireRole(ctx, RoleBuild); err != nil {
+		t.Fatal(err)
+	}
+	if err = RequireRole(ctx, RoleAdmin); !IsPermissionDeniedError(err) {
+		t.Fatalf("expected PermissionDeniedError. got %v", err)
+	}
+	if err = RequireRole(context.Background(), RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutualTls(t *testing.T) {
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, ca_locator := newTestCertificate(t, dir, "ca", nil)
+	_, server_locator := newTestCertificate(t, dir, "server", ca)
+	_, alice_locator := newTestCertificate(t, dir, "alice", ca)
+	_, bob_locator := newTestCertificate(t, dir, "bob", ca)
+	other_ca, _ := newTestCertificate(t, dir, "other-ca", nil)
+	_, mallory_locator := newTestCertificate(t, dir, "mallory", other_ca)
+
+	server_host := &HostConfig{
+		Name:          "server",
+		Certificates:  &CertificateConfig{RootCert: ca_locator, ServerCert: server_locator},
+		Authorization: map[string]string{"alice": "admin", "bob": "read-only"}}
+	err = server_host.Normalize(&HostsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server_creds, err := getCredentialsForHost(server_host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(append(RpcServerOptions(NewAuthorizer(server_host), nil),
+		grpc.Creds(NewLocalTransportCredentials(server_creds)))...)
+	RegisterServiceHostServer(server, &ServiceHostServerImpl{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	endpoint := Endpoint{Network: "tcp", Address: listener.Addr().String()}
+	connect := func(client_cert *CertificateLocator) ServiceHostClient {
+		client_config := Config{Host: &HostConfig{
+			Certificates: &CertificateConfig{RootCert: ca_locator, ClientCert: client_cert}}}
+		client_creds, err := getCredentialsForClient(client_config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := connectToLocalEndpoint(context.Background(), Config{Host: server_host}, endpoint,
+			grpc.WithTransportCredentials(client_creds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewServiceHostClient(conn)
+	}
+
+	alice := connect(alice_locator)
+	if _, err = alice.Ping(context.Background(), &PingOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	bob := connect(bob_locator)
+	if _, err = bob.Ping(context.Background(), &PingOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := bob.SelfUpdate(context.Background(), &SelfUpdateOptions{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if !IsPermissionDeniedError(err) {
+		t.Fatalf("expected PermissionDeniedError. got %v", err)
+	}
+
+	// Certificates issued by other CAs aren't accepted.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	mallory := connect(mallory_locator)
+	if _, err = mallory.Ping(ctx, &PingOptions{}); err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	// Neither are clients without a certificate.
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	anonymous := connect(nil)
+	if _, err = anonymous.Ping(ctx, &PingOptions{}); err == nil {
+		t.Fatal("unexpected success")
+	}
+}
@@ -0,0 +1,314 @@
+package stonesthrow
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	kCaValidity          = 10 * 365 * 24 * time.Hour
+	kCertificateValidity = 365 * 24 * time.Hour
+
+	// Certificates that expire within this duration are reported, and are
+	// replaced by "certs rotate".
+	kCertificateExpiryWarning = 30 * 24 * time.Hour
+
+	kCaCertFile = "ca.crt"
+	kCaKeyFile  = "ca.key"
+)
+
+// CertificateAuthority issues the certificates used by the hosts in a
+// configuration. The CA's key and certificate, as well as the issued
+// certificates, are kept in |Dir|.
+type CertificateAuthority struct {
+	Dir string
+
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// GetDefaultCertificateDir returns the directory for the CA used by the hosts
+// in |config_file|. Like the hosts' state, it's under a "state" directory next
+// to the configuration file.
+func GetDefaultCertificateDir(config_file *ConfigurationFile) string {
+	return filepath.Join(filepath.Dir(config_file.FileName), "state", "certs")
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func writePem(filename string, block_type string, der []byte, mode os.FileMode) error {
+	return ioutil.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: block_type, Bytes: der}), mode)
+}
+
+// writeKeyPair writes |der| and |key| to the files in |locator|. The key is
+// only readable by the current user.
+func writeKeyPair(locator *CertificateLocator, der []byte, key *ecdsa.PrivateKey) error {
+	key_der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(locator.CertificateFile), 0700)
+	if err != nil {
+		return err
+	}
+	err = writePem(locator.KeyFile, "EC PRIVATE KEY", key_der, 0600)
+	if err != nil {
+		return err
+	}
+	return writePem(locator.CertificateFile, "CERTIFICATE", der, 0644)
+}
+
+// ReadCertificate returns the first certificate in |cert_file|.
+func ReadCertificate(cert_file string) (*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(cert_file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, NewConfigurationError("no certificate found in %s", cert_file)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func (a *CertificateAuthority) locator(name string) *CertificateLocator {
+	return &CertificateLocator{
+		CertificateFile: filepath.Join(a.Dir, name+".crt"),
+		KeyFile:         filepath.Join(a.Dir, name+".key")}
+}
+
+// InitCertificateAuthority creates a new CA in |dir| named after |name|. An
+// existing CA is only replaced if |force| is true. Replacing the CA
+// invalidates all the certificates that it issued.
+func InitCertificateAuthority(dir string, name string, force bool) (*CertificateAuthority, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	a := &CertificateAuthority{Dir: dir}
+	if _, err := os.Stat(filepath.Join(dir, kCaKeyFile)); err == nil && !force {
+		return nil, NewInvalidArgumentError("%s already contains a CA. Use -force to replace it", dir)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("Stonesthrow CA (%s)", name)},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(kCaValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		MaxPathLenZero:        true}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	err = writeKeyPair(&CertificateLocator{
+		CertificateFile: filepath.Join(dir, kCaCertFile),
+		KeyFile:         filepath.Join(dir, kCaKeyFile)}, der, key)
+	if err != nil {
+		return nil, err
+	}
+	a.cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	a.key = key
+	return a, nil
+}
+
+// OpenCertificateAuthority loads the CA in |dir| that was created by
+// InitCertificateAuthority.
+func OpenCertificateAuthority(dir string) (*CertificateAuthority, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	a := &CertificateAuthority{Dir: dir}
+	a.cert, err = ReadCertificate(filepath.Join(dir, kCaCertFile))
+	if os.IsNotExist(err) {
+		return nil, NewConfigIncompleteError("%s doesn't contain a CA. Run \"certs init\" first", dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key_pem, err := ioutil.ReadFile(filepath.Join(dir, kCaKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(key_pem)
+	if block == nil {
+		return nil, NewConfigurationError("no key found in %s", filepath.Join(dir, kCaKeyFile))
+	}
+	a.key, err = x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RootCert locates the CA's certificate. The CA's key isn't needed by the
+// hosts and isn't included.
+func (a *CertificateAuthority) RootCert() *CertificateLocator {
+	return &CertificateLocator{CertificateFile: filepath.Join(a.Dir, kCaCertFile)}
+}
+
+// Certificate returns the CA's certificate.
+func (a *CertificateAuthority) Certificate() *x509.Certificate {
+	return a.cert
+}
+
+// issue creates a certificate from |template| and writes it to |locator|.
+func (a *CertificateAuthority) issue(template *x509.Certificate, locator *CertificateLocator) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template.SerialNumber, err = newSerialNumber()
+	if err != nil {
+		return err
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(kCertificateValidity)
+	if template.NotAfter.After(a.cert.NotAfter) {
+		template.NotAfter = a.cert.NotAfter
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		return err
+	}
+	return writeKeyPair(locator, der, key)
+}
+
+// IssueHostCertificates issues a server and a client certificate for |host|.
+// The server certificate is valid for the host's name and nicknames. The
+// client certificate identifies the host by its name. Returns the
+// certificate configuration for the host.
+func (a *CertificateAuthority) IssueHostCertificates(host *HostConfig) (*CertificateConfig, error) {
+	server_template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: host.Name},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+	for _, name := range append([]string{host.Name}, host.Nickname...) {
+		if ip := net.ParseIP(name); ip != nil {
+			server_template.IPAddresses = append(server_template.IPAddresses, ip)
+		} else {
+			server_template.DNSNames = append(server_template.DNSNames, name)
+		}
+	}
+
+	certificates := &CertificateConfig{
+		RootCert:   a.RootCert(),
+		ServerCert: a.locator(filepath.Join(host.Name, "server")),
+		ClientCert: a.locator(filepath.Join(host.Name, "client"))}
+	err := a.issue(server_template, certificates.ServerCert)
+	if err != nil {
+		return nil, err
+	}
+	err = a.issue(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: host.Name},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, certificates.ClientCert)
+	if err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+// IssueClientCertificate issues a client certificate for |identity|, e.g. a
+// user. Use the identity in the authorization section of a host to grant it
+// a role.
+func (a *CertificateAuthority) IssueClientCertificate(identity string) (*CertificateLocator, error) {
+	locator := a.locator(filepath.Join("clients", identity))
+	err := a.issue(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: identity},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, locator)
+	if err != nil {
+		return nil, err
+	}
+	return locator, nil
+}
+
+// CertificateExpiry describes when a certificate used by a host expires.
+type CertificateExpiry struct {
+	Usage    string // "root", "server" or "client"
+	FileName string
+	NotAfter time.Time
+}
+
+func (e CertificateExpiry) String() string {
+	return fmt.Sprintf("%s certificate %s expires on %s", e.Usage, e.FileName, e.NotAfter.Format("2006-01-02"))
+}
+
+// GetExpiringCertificates returns the certificates in |certificates| that
+// expire before |deadline|. Certificates that can't be read are skipped.
+func GetExpiringCertificates(certificates *CertificateConfig, deadline time.Time) []CertificateExpiry {
+	if certificates == nil {
+		return nil
+	}
+	expiring := []CertificateExpiry{}
+	for _, usage := range []struct {
+		name    string
+		locator *CertificateLocator
+	}{{"root", certificates.RootCert}, {"server", certificates.ServerCert}, {"client", certificates.ClientCert}} {
+		if usage.locator == nil {
+			continue
+		}
+		cert, err := ReadCertificate(usage.locator.CertificateFile)
+		if err != nil || !cert.NotAfter.Before(deadline) {
+			continue
+		}
+		expiring = append(expiring, CertificateExpiry{
+			Usage: usage.name, FileName: usage.locator.CertificateFile, NotAfter: cert.NotAfter})
+	}
+	return expiring
+}
+
+// GetCertificateExpiryWarnings returns a warning for each certificate in
+// |certificates| that expires soon.
+func GetCertificateExpiryWarnings(certificates *CertificateConfig) []string {
+	warnings := []string{}
+	for _, expiry := range GetExpiringCertificates(certificates, time.Now().Add(kCertificateExpiryWarning)) {
+		warnings = append(warnings, expiry.String())
+	}
+	return warnings
+}
+
+// uniqueHosts returns the hosts in |hosts| sorted by name. Each host appears
+// once even though HostsConfig lists it under each of its nicknames.
+func uniqueHosts(hosts *HostsConfig) []*HostConfig {
+	names := []string{}
+	for name, host := range hosts.Hosts {
+		if name == host.Name && !host.IsWildcard() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	unique := []*HostConfig{}
+	for _, name := range names {
+		unique = append(unique, hosts.Hosts[name])
+	}
+	return unique
+}
@@ -0,0 +1,155 @@
+package stonesthrow
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCertificateAuthority(t *testing.T) {
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "config-basic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config_filename := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(config_filename, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config_file ConfigurationFile
+	err = config_file.ReadFrom(config_filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca_dir := GetDefaultCertificateDir(&config_file)
+	if _, err = OpenCertificateAuthority(ca_dir); !IsConfigIncompleteError(err) {
+		t.Fatalf("expected ConfigIncompleteError. got %v", err)
+	}
+	_, err = InitCertificateAuthority(ca_dir, "a", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = InitCertificateAuthority(ca_dir, "a", false); !IsInvalidArgumentError(err) {
+		t.Fatalf("expected InvalidArgumentError. got %v", err)
+	}
+	ca, err := OpenCertificateAuthority(ca_dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hosts := uniqueHosts(&config_file.HostsConfig)
+	if len(hosts) != 3 || hosts[0].Name != "a.foo.example.com" {
+		t.Fatalf("unexpected hosts %v", hosts)
+	}
+	certificates := make(map[string]*CertificateConfig)
+	for _, host := range hosts {
+		certificates[host.Name], err = ca.IssueHostCertificates(host)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = config_file.SetCertificates(certificates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The paths are written back without reformatting the rest of the file.
+	updated, err := ioutil.ReadFile(config_filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := "{\n\t\"a.foo.example.com\": {\n\t\t\"nickname\": [ \"a.foo\", \"a\" ],"
+	if !strings.HasPrefix(string(updated), prefix) ||
+		!strings.Contains(string(updated), "\"goma_path\": \"/home/user/goma\",\n\t\t\"certificates\": {\n\t\t\t\"root\": {") {
+		t.Fatalf("unexpected configuration file %s", updated)
+	}
+	// Existing certificates are replaced.
+	err = config_file.SetCertificates(certificates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = ioutil.ReadFile(config_filename); err != nil || string(data) != string(updated) {
+		t.Fatalf("unexpected configuration file %s", data)
+	}
+
+	var updated_config_file ConfigurationFile
+	err = updated_config_file.ReadFrom(config_filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := updated_config_file.HostsConfig.HostByName("b")
+	if host == nil || host.GomaPath != "/Users/user/goma" {
+		t.Fatalf("unexpected host %#v", host)
+	}
+	if *host.Certificates.ServerCert != *certificates["b.foo.example.com"].ServerCert ||
+		*host.Certificates.RootCert != *ca.RootCert() {
+		t.Fatalf("unexpected certificates %#v", host.Certificates)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate())
+	server_cert, err := ReadCertificate(host.Certificates.ServerCert.CertificateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.foo.example.com", "mac", "b"} {
+		_, err = server_cert.Verify(x509.VerifyOptions{DNSName: name, Roots: roots})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	client_cert, err := ReadCertificate(host.Certificates.ClientCert.CertificateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client_cert.Verify(x509.VerifyOptions{
+		Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client_cert.Subject.CommonName != "b.foo.example.com" {
+		t.Fatalf("unexpected subject %v", client_cert.Subject)
+	}
+
+	// The issued certificates can be used for mutual TLS.
+	if _, err = getCredentialsForHost(host); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getCredentialsForClient(Config{Host: host}); err != nil {
+		t.Fatal(err)
+	}
+
+	if needsRotation(host, time.Now()) {
+		t.Fatal("new certificates shouldn't need rotation")
+	}
+	deadline := time.Now().Add(2 * kCertificateValidity)
+	if !needsRotation(host, deadline) {
+		t.Fatal("expiring certificates should need rotation")
+	}
+	if expiring := GetExpiringCertificates(host.Certificates, deadline); len(expiring) != 2 ||
+		expiring[0].Usage != "server" || expiring[1].Usage != "client" {
+		t.Fatalf("unexpected expiring certificates %v", expiring)
+	}
+	if warnings := GetCertificateExpiryWarnings(host.Certificates); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+
+	locator, err := ca.IssueClientCertificate("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locator.CertificateFile != filepath.Join(ca.Dir, "clients", "alice.crt") {
+		t.Fatalf("unexpected locator %v", locator)
+	}
+}
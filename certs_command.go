@@ -0,0 +1,131 @@
+package stonesthrow
+
+import (
+	"fmt"
+	"time"
+)
+
+// selectHosts returns the hosts named in |names|, or all the hosts in
+// |hosts| if |names| is empty.
+func selectHosts(hosts *HostsConfig, names []string) ([]*HostConfig, error) {
+	if len(names) == 0 {
+		return uniqueHosts(hosts), nil
+	}
+	selected := []*HostConfig{}
+	for _, name := range names {
+		host := hosts.HostByName(name)
+		if host == nil {
+			return nil, NewInvalidArgumentError("unknown host %s", name)
+		}
+		selected = append(selected, host)
+	}
+	return selected, nil
+}
+
+// needsRotation returns true if the server or client certificate of |host| is
+// missing or expires before |deadline|.
+func needsRotation(host *HostConfig, deadline time.Time) bool {
+	certificates := host.Certificates
+	if certificates == nil || certificates.ServerCert == nil || certificates.ClientCert == nil {
+		return true
+	}
+	for _, locator := range []*CertificateLocator{certificates.ServerCert, certificates.ClientCert} {
+		cert, err := ReadCertificate(locator.CertificateFile)
+		if err != nil || cert.NotAfter.Before(deadline) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunCertsCommand runs the "certs" subcommand |args|. The CA is kept in
+// |dir|. Certificates that are issued for hosts are written back to the
+// configuration file of |conn|.
+func RunCertsCommand(conn *ClientConnection, dir string, client_identity string, force bool, args []string) error {
+	if len(args) == 0 {
+		return NewInvalidArgumentError("specify one of init, issue or rotate")
+	}
+	config_file := conn.ClientConfig.ConfigurationFile
+	if dir == "" {
+		dir = GetDefaultCertificateDir(config_file)
+	}
+
+	log := func(severity LogEvent_Severity, format string, v ...interface{}) {
+		conn.Sink.OnJobEvent(&JobEvent{
+			LogEvent: &LogEvent{
+				Host:     conn.ClientConfig.Host.Name,
+				Severity: severity,
+				Msg:      fmt.Sprintf(format, v...)}})
+	}
+
+	var ca *CertificateAuthority
+	var err error
+	if args[0] == "init" {
+		ca, err = InitCertificateAuthority(dir, conn.ClientConfig.Host.Name, force)
+		if err != nil {
+			return err
+		}
+		log(LogEvent_INFO, "created CA in %s. Use \"certs issue\" to issue certificates for the hosts.", ca.Dir)
+		return nil
+	}
+
+	ca, err = OpenCertificateAuthority(dir)
+	if err != nil {
+		return err
+	}
+	if ca.Certificate().NotAfter.Before(time.Now().Add(kCertificateExpiryWarning)) {
+		log(LogEvent_WARNING, "the CA in %s expires on %s. Use \"certs init -force\" to replace it and reissue all certificates.",
+			ca.Dir, ca.Certificate().NotAfter.Format("2006-01-02"))
+	}
+
+	switch args[0] {
+	case "issue":
+		if client_identity != "" {
+			locator, err := ca.IssueClientCertificate(client_identity)
+			if err != nil {
+				return err
+			}
+			log(LogEvent_INFO, "issued client certificate for %s: %s (key %s)",
+				client_identity, locator.CertificateFile, locator.KeyFile)
+			return nil
+		}
+
+	case "rotate":
+		if client_identity != "" {
+			return NewInvalidArgumentError("-client is only supported by \"certs issue\"")
+		}
+
+	default:
+		return NewInvalidArgumentError("unknown certs command %s", args[0])
+	}
+
+	hosts, err := selectHosts(&config_file.HostsConfig, args[1:])
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(kCertificateExpiryWarning)
+	certificates := make(map[string]*CertificateConfig)
+	for _, host := range hosts {
+		if args[0] == "rotate" && !force && !needsRotation(host, deadline) {
+			continue
+		}
+		host_certificates, err := ca.IssueHostCertificates(host)
+		if err != nil {
+			return err
+		}
+		certificates[host.Name] = host_certificates
+		log(LogEvent_INFO, "issued certificates for %s in %s", host.Name, ca.Dir)
+	}
+	if len(certificates) == 0 {
+		log(LogEvent_INFO, "no certificates expire before %s", deadline.Format("2006-01-02"))
+		return nil
+	}
+
+	err = config_file.SetCertificates(certificates)
+	if err != nil {
+		return err
+	}
+	log(LogEvent_INFO, "updated %s. Copy %s and the directory of each host in %s to the same location on the host, and restart the servers. The CA's key stays here.",
+		config_file.FileName, kCaCertFile, ca.Dir)
+	return nil
+}
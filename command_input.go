@@ -0,0 +1,146 @@
+package stonesthrow
+
+import (
+	"io"
+	"sync"
+)
+
+const kCommandInputChunkSize = 32 * 1024
+
+type CommandInputReceiver interface {
+	Recv() (*CommandInput, error)
+}
+
+type CommandInputSender interface {
+	Send(*CommandInput) error
+	CloseSend() error
+}
+
+// CommandInputReader yields the stdin data received for a command. Changes to
+// the size of the client's terminal are delivered via WindowSizeChanges().
+type CommandInputReader struct {
+	*io.PipeReader
+	windowSizeChanges chan *WindowSize
+}
+
+// WindowSizeChanges returns a channel that receives the new window size when
+// the client's terminal is resized. Changes are dropped if they aren't
+// consumed in time.
+func (c *CommandInputReader) WindowSizeChanges() <-chan *WindowSize {
+	return c.windowSizeChanges
+}
+
+// ReceiveCommandInput reads the RunOptions for a command from the first
+// message received via |r|. The returned reader yields the stdin data
+// carried by the remaining messages and reaches EOF when the client closes
+// stdin or stops sending.
+func ReceiveCommandInput(r CommandInputReceiver) (*RunOptions, *CommandInputReader, error) {
+	first, err := r.Recv()
+	if err != nil {
+		return nil, nil, err
+	}
+	if first.GetOptions() == nil {
+		return nil, nil, NewInvalidArgumentError("first message doesn't specify the command")
+	}
+
+	pipe_reader, pipe_writer := io.Pipe()
+	input_reader := &CommandInputReader{
+		PipeReader:        pipe_reader,
+		windowSizeChanges: make(chan *WindowSize, 1)}
+	go func() {
+		input := first
+		stdin_closed := false
+		for {
+			if input.GetWindowSize() != nil {
+				select {
+				case input_reader.windowSizeChanges <- input.GetWindowSize():
+				default:
+				}
+			}
+			if len(input.GetStdin()) != 0 && !stdin_closed {
+				_, err := pipe_writer.Write(input.GetStdin())
+				if err != nil {
+					stdin_closed = true
+				}
+			}
+			if input.GetCloseStdin() {
+				pipe_writer.Close()
+				stdin_closed = true
+			}
+			input, err = r.Recv()
+			if err == io.EOF {
+				pipe_writer.Close()
+				return
+			}
+			if err != nil {
+				pipe_writer.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return first.GetOptions(), input_reader, nil
+}
+
+// SendCommandInput sends |ro| followed by the contents of |stdin| via |s|.
+// Returns once |stdin| reaches EOF or can't be sent.
+func SendCommandInput(ro *RunOptions, stdin io.Reader, s CommandInputSender) error {
+	err := s.Send(&CommandInput{Options: ro})
+	if err != nil {
+		return err
+	}
+	return SendStdin(stdin, s)
+}
+
+// SendStdin sends the contents of |stdin| via |s| and closes the sending side
+// of |s| once |stdin| reaches EOF. The RunOptions for the command must have
+// been sent already.
+func SendStdin(stdin io.Reader, s CommandInputSender) error {
+	var err error
+	buffer := make([]byte, kCommandInputChunkSize)
+	for {
+		count, read_err := stdin.Read(buffer)
+		if count > 0 {
+			data := make([]byte, count)
+			copy(data, buffer[:count])
+			err = s.Send(&CommandInput{Stdin: data})
+			if err != nil {
+				return err
+			}
+		}
+		if read_err == io.EOF {
+			break
+		}
+		if read_err != nil {
+			return read_err
+		}
+	}
+
+	err = s.Send(&CommandInput{CloseStdin: true})
+	if err != nil {
+		return err
+	}
+	return s.CloseSend()
+}
+
+// SynchronizedCommandInputSender allows multiple goroutines to send input for
+// a single command.
+type SynchronizedCommandInputSender struct {
+	sender CommandInputSender
+	mutex  sync.Mutex
+}
+
+func NewSynchronizedCommandInputSender(s CommandInputSender) *SynchronizedCommandInputSender {
+	return &SynchronizedCommandInputSender{sender: s}
+}
+
+func (s *SynchronizedCommandInputSender) Send(i *CommandInput) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sender.Send(i)
+}
+
+func (s *SynchronizedCommandInputSender) CloseSend() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sender.CloseSend()
+}
@@ -0,0 +1,88 @@
+package stonesthrow
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// commandInputPipe connects a CommandInputSender to a CommandInputReceiver.
+type commandInputPipe chan *CommandInput
+
+func (c commandInputPipe) Send(i *CommandInput) error {
+	c <- i
+	return nil
+}
+
+func (c commandInputPipe) CloseSend() error {
+	close(c)
+	return nil
+}
+
+func (c commandInputPipe) Recv() (*CommandInput, error) {
+	i, ok := <-c
+	if !ok {
+		return nil, io.EOF
+	}
+	return i, nil
+}
+
+func TestCommandInput_RoundTrip(t *testing.T) {
+	pipe := make(commandInputPipe, 10)
+	go SendCommandInput(&RunOptions{Platform: "linux"}, strings.NewReader("hello\nworld\n"), pipe)
+
+	ro, stdin, err := ReceiveCommandInput(pipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ro.GetPlatform() != "linux" {
+		t.Fatalf("unexpected options %v", ro)
+	}
+	data, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello\nworld\n" {
+		t.Fatalf("unexpected stdin %q", string(data))
+	}
+}
+
+func TestCommandInput_NoOptions(t *testing.T) {
+	pipe := make(commandInputPipe, 1)
+	pipe.Send(&CommandInput{Stdin: []byte("x")})
+	_, _, err := ReceiveCommandInput(pipe)
+	if !IsInvalidArgumentError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestJobEventExecutor_Stdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires cat")
+	}
+
+	pipe := make(commandInputPipe, 10)
+	go SendCommandInput(&RunOptions{}, strings.NewReader("from the client\n"), pipe)
+	_, stdin, err := ReceiveCommandInput(pipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events recordingJobEventSender
+	executor := NewJobEventExecutor("host", "", "", "", nil, &events)
+	executor.SetStdin(stdin)
+	err = executor.ExecutePassthrough(context.Background(), "cat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := ""
+	for _, e := range events.events {
+		output += string(e.GetCommandOutputEvent().GetData())
+	}
+	if output != "from the client\n" {
+		t.Fatalf("stdin wasn't passed to the command. output: %q", output)
+	}
+}
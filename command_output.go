@@ -0,0 +1,174 @@
+package stonesthrow
+
+import (
+	"bytes"
+	"time"
+)
+
+const (
+	kCommandOutputChunkSize    = 32 * 1024
+	kMaxControlSequenceLength  = 1024 * 1024
+	kMaxPendingOutputLineBytes = 1024 * 1024
+	kControlSequenceDelimiter  = "@@@"
+	kLineTerminator            = '\n'
+	kCarriageReturn            = '\r'
+
+	// How long output is read after a command exits. See
+	// JobEventExecutor.execute.
+	kCommandOutputDrainTimeout = time.Second
+)
+
+// commandOutputWriter turns the output of a command into chunked
+// CommandOutputEvents. Output is passed along as soon as it is written, except
+// for lines that look like control sequences. Those are held back until the
+// line is complete and then handed over to |onControlSequence| instead.
+type commandOutputWriter struct {
+	stream            CommandOutputEvent_Stream
+	sender            JobEventSender
+	onControlSequence func(string)
+
+	pending     []byte
+	atLineStart bool
+}
+
+func newCommandOutputWriter(stream CommandOutputEvent_Stream, sender JobEventSender, onControlSequence func(string)) *commandOutputWriter {
+	return &commandOutputWriter{
+		stream:            stream,
+		sender:            sender,
+		onControlSequence: onControlSequence,
+		atLineStart:       true}
+}
+
+// mightBeControlSequence returns true if |data|, which starts at the
+// beginning of a line, is or could become a control sequence.
+func mightBeControlSequence(data []byte) bool {
+	length := len(data)
+	if length > len(kControlSequenceDelimiter) {
+		length = len(kControlSequenceDelimiter)
+	}
+	return bytes.Equal(data[:length], []byte(kControlSequenceDelimiter[:length]))
+}
+
+func isControlSequence(line []byte) bool {
+	return len(line) >= 2*len(kControlSequenceDelimiter) &&
+		bytes.HasPrefix(line, []byte(kControlSequenceDelimiter)) &&
+		bytes.HasSuffix(line, []byte(kControlSequenceDelimiter))
+}
+
+func (w *commandOutputWriter) send(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	w.sender.Send(&JobEvent{
+		CommandOutputEvent: &CommandOutputEvent{
+			Stream: w.stream,
+			Data:   append([]byte(nil), data...)}})
+}
+
+func (w *commandOutputWriter) Write(p []byte) (int, error) {
+	data := append(w.pending, p...)
+	w.pending = nil
+
+	flush_from := 0
+	index := 0
+	for index < len(data) {
+		line_end := bytes.IndexByte(data[index:], kLineTerminator)
+		if w.atLineStart && mightBeControlSequence(data[index:]) {
+			if line_end < 0 && len(data)-index <= kMaxControlSequenceLength {
+				w.send(data[flush_from:index])
+				w.pending = append([]byte(nil), data[index:]...)
+				return len(p), nil
+			}
+			if line_end >= 0 {
+				line := bytes.TrimRight(data[index:index+line_end], string(kCarriageReturn))
+				if isControlSequence(line) {
+					w.send(data[flush_from:index])
+					w.onControlSequence(string(bytes.Trim(line, "@")))
+					index += line_end + 1
+					flush_from = index
+					continue
+				}
+			}
+		}
+
+		if line_end < 0 {
+			w.atLineStart = false
+			break
+		}
+		index += line_end + 1
+		w.atLineStart = true
+	}
+	w.send(data[flush_from:])
+	return len(p), nil
+}
+
+// Close passes along any output that was being held back.
+func (w *commandOutputWriter) Close() error {
+	w.send(w.pending)
+	w.pending = nil
+	return nil
+}
+
+// OutputLineSplitter reassembles the chunked output of commands into lines
+// for consumers that process output line by line. Each stream is split
+// separately. Partial lines are held back until they are completed or
+// flushed. Very long lines are split.
+type OutputLineSplitter struct {
+	partial map[CommandOutputEvent_Stream][]byte
+}
+
+// Split returns the complete lines in |e| along with any preceding partial
+// line for the same stream. Each line includes its line terminator, if any.
+// Output from older hosts which is already split into lines is returned as
+// is.
+func (s *OutputLineSplitter) Split(e *CommandOutputEvent) []string {
+	if len(e.GetData()) == 0 {
+		if e.GetOutput() == "" {
+			return nil
+		}
+		return []string{e.GetOutput() + "\n"}
+	}
+	if s.partial == nil {
+		s.partial = make(map[CommandOutputEvent_Stream][]byte)
+	}
+
+	data := append(s.partial[e.GetStream()], e.GetData()...)
+	lines := []string{}
+	for {
+		line_end := bytes.IndexByte(data, kLineTerminator)
+		if line_end < 0 {
+			break
+		}
+		lines = append(lines, string(data[:line_end+1]))
+		data = data[line_end+1:]
+	}
+	if len(data) > kMaxPendingOutputLineBytes {
+		lines = append(lines, string(data))
+		data = nil
+	}
+	s.partial[e.GetStream()] = append([]byte(nil), data...)
+	return lines
+}
+
+// Flush returns all partial lines that have been held back.
+func (s *OutputLineSplitter) Flush() []string {
+	lines := []string{}
+	for _, stream := range []CommandOutputEvent_Stream{CommandOutputEvent_OUT, CommandOutputEvent_ERR} {
+		if len(s.partial[stream]) != 0 {
+			lines = append(lines, string(s.partial[stream]))
+		}
+	}
+	s.partial = nil
+	return lines
+}
+
+// SplitLineTerminator splits |line| into its contents and its line
+// terminator.
+func SplitLineTerminator(line string) (string, string) {
+	contents := line
+	for len(contents) > 0 &&
+		(contents[len(contents)-1] == kLineTerminator || contents[len(contents)-1] == kCarriageReturn) {
+		contents = contents[:len(contents)-1]
+	}
+	return contents, line[len(contents):]
+}
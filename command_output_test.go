@@ -0,0 +1,81 @@
+package stonesthrow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandOutputWriter(t *testing.T) {
+	var events recordingJobEventSender
+	control_sequences := []string{}
+	writer := newCommandOutputWriter(CommandOutputEvent_OUT, &events, func(s string) {
+		control_sequences = append(control_sequences, s)
+	})
+
+	// Control sequences can be split across writes. Everything else is
+	// passed along right away, including partial lines.
+	writes := []string{
+		"first line\n@@",
+		"@J:{}@@@\nprogress 1\rprogress 2\r",
+		"@@@ not a control sequence\n",
+		"binary \x00\xff and no newline"}
+	for _, w := range writes {
+		writer.Write([]byte(w))
+	}
+	writer.Close()
+
+	if !reflect.DeepEqual(control_sequences, []string{"J:{}"}) {
+		t.Fatalf("unexpected control sequences %v", control_sequences)
+	}
+
+	output := ""
+	for _, e := range events.events {
+		output += string(e.GetCommandOutputEvent().GetData())
+	}
+	expected := "first line\nprogress 1\rprogress 2\r@@@ not a control sequence\nbinary \x00\xff and no newline"
+	if output != expected {
+		t.Fatalf("unexpected output %q", output)
+	}
+	if string(events.events[0].GetCommandOutputEvent().GetData()) != "first line\n" {
+		t.Fatalf("output was held back: %v", events.events)
+	}
+}
+
+func TestCommandOutputWriter_LongLines(t *testing.T) {
+	var events recordingJobEventSender
+	writer := newCommandOutputWriter(CommandOutputEvent_ERR, &events, func(string) {})
+	long_line := strings.Repeat("x", 200*1024) + "\n"
+	writer.Write([]byte(long_line))
+	writer.Close()
+
+	output := ""
+	for _, e := range events.events {
+		if e.GetCommandOutputEvent().GetStream() != CommandOutputEvent_ERR {
+			t.Fatalf("unexpected stream")
+		}
+		output += string(e.GetCommandOutputEvent().GetData())
+	}
+	if output != long_line {
+		t.Fatalf("long line was mangled. got %d bytes", len(output))
+	}
+}
+
+func TestOutputLineSplitter(t *testing.T) {
+	var s OutputLineSplitter
+	lines := s.Split(&CommandOutputEvent{Data: []byte("a\nb")})
+	lines = append(lines, s.Split(&CommandOutputEvent{Stream: CommandOutputEvent_ERR, Data: []byte("error\r\n")})...)
+	lines = append(lines, s.Split(&CommandOutputEvent{Data: []byte("c\nd")})...)
+	lines = append(lines, s.Split(&CommandOutputEvent{Output: "old style"})...)
+	lines = append(lines, s.Flush()...)
+
+	expected := []string{"a\n", "error\r\n", "bc\n", "old style\n", "d"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+
+	contents, terminator := SplitLineTerminator("error\r\n")
+	if contents != "error" || terminator != "\r\n" {
+		t.Fatalf("unexpected split %q %q", contents, terminator)
+	}
+}
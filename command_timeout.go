@@ -0,0 +1,32 @@
+package stonesthrow
+
+import (
+	"context"
+	"time"
+)
+
+// kCommandDeadlineMargin is how long before the deadline of an RPC the command
+// that it runs times out. This leaves time for the command to be stopped and
+// for its EndCommandEvent to reach the client before the RPC fails.
+const kCommandDeadlineMargin = kJobTerminationGracePeriod + 5*time.Second
+
+// WithCommandTimeout returns a context for an RPC whose command should time
+// out after |timeout|. The timeout travels to the host as the deadline of the
+// RPC.
+func WithCommandTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout+kCommandDeadlineMargin)
+}
+
+// withCommandDeadline returns the context for running a command on behalf of
+// an RPC with context |ctx|. If |ctx| has a deadline, the command times out
+// kCommandDeadlineMargin before it. Otherwise the command times out after
+// |default_timeout| unless the latter is zero.
+func withCommandDeadline(ctx context.Context, default_timeout time.Duration) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(ctx, deadline.Add(-kCommandDeadlineMargin))
+	}
+	if default_timeout > 0 {
+		return context.WithTimeout(ctx, default_timeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,49 @@
+package stonesthrow
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWithCommandDeadline(t *testing.T) {
+	rpc_ctx, cancel := WithCommandTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx, cancel := withCommandDeadline(rpc_ctx, time.Hour)
+	defer cancel()
+
+	rpc_deadline, _ := rpc_ctx.Deadline()
+	deadline, ok := ctx.Deadline()
+	if !ok || rpc_deadline.Sub(deadline) != kCommandDeadlineMargin {
+		t.Fatalf("unexpected deadline %v for RPC deadline %v", deadline, rpc_deadline)
+	}
+	if time.Until(deadline) > time.Minute {
+		t.Fatalf("deadline %v is too late", deadline)
+	}
+
+	ctx, cancel = withCommandDeadline(context.Background(), 0)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("unexpected deadline")
+	}
+}
+
+func TestJobEventExecutor_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+
+	var events recordingJobEventSender
+	executor := NewJobEventExecutor("host", "", "", "", nil, &events)
+	executor.SetDefaultTimeout(100 * time.Millisecond)
+	err := executor.ExecutePassthrough(context.Background(), "sleep", "30")
+	if !IsTimedOutError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	last := events.events[len(events.events)-1]
+	if !last.GetEndCommandEvent().GetTimedOut() {
+		t.Fatalf("expected a timed out EndCommandEvent. got %v", last)
+	}
+}
@@ -12,6 +12,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type FileExtractor struct {
@@ -55,6 +59,29 @@ type ClientConnection struct {
 	repository      string
 	config_filename string
 	rpcConnection   *grpc.ClientConn
+	lastEndCommand  *EndCommandEvent
+	serverInfo      *HelloResult
+	debug           bool
+}
+
+type endCommandRecorder struct {
+	JobEventReceiver
+	conn *ClientConnection
+}
+
+func (r endCommandRecorder) Recv() (*JobEvent, error) {
+	je, err := r.JobEventReceiver.Recv()
+	if err == nil && je.GetEndCommandEvent() != nil {
+		r.conn.lastEndCommand = je.GetEndCommandEvent()
+	}
+	return je, err
+}
+
+// Drain passes the events from |receiver| to the output sink while keeping
+// track of the last command that finished. If the request fails, the exit
+// status of the client is that of the last command.
+func (c *ClientConnection) Drain(receiver JobEventReceiver) error {
+	return c.Sink.Drain(endCommandRecorder{receiver, c})
 }
 
 func (c ClientConnection) IsRemote() bool {
@@ -68,6 +95,7 @@ func (c *ClientConnection) SetupTopLevelFlags(f *flag.FlagSet) {
 	f.StringVar(&c.platform, "platform", default_server_platform, "target server platform.")
 	f.StringVar(&c.repository, "repository", "", "repository name. defaults to the repository corresponding to the current directory.")
 	f.StringVar(&c.config_filename, "config", default_config_file, "configuration file.")
+	f.BoolVar(&c.debug, "debug", false, "show stack traces for errors, including those that occur on the server.")
 }
 
 func (c *ClientConnection) InitFromFlags(ctx context.Context, f *flag.FlagSet) error {
@@ -99,11 +127,15 @@ func (c *ClientConnection) InitFromFlags(ctx context.Context, f *flag.FlagSet) e
 	c.ClientConfig = client_config
 	c.ServerConfig = server_config
 	c.Sink = c.Sinkerator(server_config)
-	c.Executor = NewJobEventExecutor(client_config.Host.Name, client_config.GetSourcePath(), nil, c.Sink)
+	c.Executor = NewJobEventExecutor(client_config.Host.Name, client_config.Repository.Name, "",
+		client_config.GetSourcePath(), nil, c.Sink)
 	return nil
 }
 
-func (c *ClientConnection) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
+// GetUncheckedConnection connects to the server without checking whether the
+// client can talk to it. Only use it for RPCs that work with every version of
+// the server.
+func (c *ClientConnection) GetUncheckedConnection(ctx context.Context) (*grpc.ClientConn, error) {
 	if c.rpcConnection != nil {
 		return c.rpcConnection, nil
 	}
@@ -112,9 +144,200 @@ func (c *ClientConnection) GetConnection(ctx context.Context) (*grpc.ClientConn,
 		return nil, fmt.Errorf("can't connect to remote %s : %s", c.ServerConfig.Host.Name, err.Error())
 	}
 	c.rpcConnection = rpc_connection
+
+	for _, warning := range GetCertificateExpiryWarnings(c.ClientConfig.Host.Certificates) {
+		c.Sink.OnJobEvent(&JobEvent{
+			LogEvent: &LogEvent{
+				Host:     c.ClientConfig.Host.Name,
+				Severity: LogEvent_WARNING,
+				Msg:      warning + `. Use "certs rotate" to replace it.`}})
+	}
+	return rpc_connection, nil
+}
+
+// GetConnection connects to the server and checks that the client can talk to
+// it. Returns an IncompatibleVersionError that explains how to update the
+// client or the server if it can't.
+func (c *ClientConnection) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
+	rpc_connection, err := c.GetUncheckedConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.serverInfo != nil {
+		return rpc_connection, nil
+	}
+	server_info, err := Hello(ctx, c.ServerConfig.Host.Name, rpc_connection)
+	if err != nil {
+		return nil, err
+	}
+	c.serverInfo = server_info
 	return rpc_connection, nil
 }
 
+// GetServerInfo returns the version and capabilities of the server.
+func (c *ClientConnection) GetServerInfo(ctx context.Context) (*HelloResult, error) {
+	_, err := c.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.serverInfo, nil
+}
+
+// GetHostClient returns a client for the Host service. Returns nil if the
+// server only implements the older BuildHost, RepositoryHost and ServiceHost
+// services.
+func (c *ClientConnection) GetHostClient(ctx context.Context) (HostClient, error) {
+	rpc_connection, err := c.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !c.serverInfo.HasCapability(CapabilityHostService) {
+		return nil, nil
+	}
+	return NewHostClient(rpc_connection), nil
+}
+
+// RequireCapabilities returns an IncompatibleVersionError that suggests
+// updating the server unless the server supports all of |capabilities|.
+func (c *ClientConnection) RequireCapabilities(ctx context.Context, capabilities ...string) error {
+	server_info, err := c.GetServerInfo(ctx)
+	if err != nil {
+		return err
+	}
+	for _, capability := range capabilities {
+		if !server_info.HasCapability(capability) {
+			return NewIncompatibleVersionError(
+				"st_host on %s doesn't support %s. run 'st_client update' to update the server.",
+				c.ServerConfig.Host.Name, capability)
+		}
+	}
+	return nil
+}
+
+// commandCapabilities returns the capabilities that the server needs for
+// running |ro| with a timeout of |timeout|.
+func commandCapabilities(ro *RunOptions, timeout time.Duration) []string {
+	capabilities := []string{}
+	if ro.GetDetach() {
+		capabilities = append(capabilities, CapabilityDetach)
+	}
+	if ro.GetTty() {
+		capabilities = append(capabilities, CapabilityTerminal)
+	}
+	if len(ro.GetCommand().GetEnv()) != 0 {
+		capabilities = append(capabilities, CapabilityEnvironment)
+	}
+	if timeout > 0 {
+		capabilities = append(capabilities, CapabilityTimeout)
+	}
+	return capabilities
+}
+
+// OpenCommandStream starts the shell or script command described by |ro|. The
+// command doesn't run until |ro| is sent via the returned stream.
+func (c *ClientConnection) OpenCommandStream(ctx context.Context, ro *RunOptions, shell bool) (CommandStream, error) {
+	host_client, err := c.GetHostClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if host_client != nil {
+		ro.Shell = shell
+		stream, err := host_client.RunCommand(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewTaskEventStream(stream), nil
+	}
+
+	builder_client := NewBuildHostClient(c.rpcConnection)
+	if shell {
+		return builder_client.RunShellCommandWithInput(ctx)
+	}
+	return builder_client.RunScriptCommandWithInput(ctx)
+}
+
+// runCommandWithoutInput runs |ro| via the RPCs of the BuildHost service that
+// don't take any input.
+func (c *ClientConnection) runCommandWithoutInput(ctx context.Context, ro *RunOptions, shell bool) error {
+	builder_client := NewBuildHostClient(c.rpcConnection)
+	var event_stream JobEventReceiver
+	var err error
+	if shell {
+		event_stream, err = builder_client.RunShellCommand(ctx, ro)
+	} else {
+		event_stream, err = builder_client.RunScriptCommand(ctx, ro)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Drain(event_stream)
+}
+
+// RunCommand runs the shell or script command described by |ro| with its
+// stdin read from |stdin|. The command runs without stdin if |stdin| is nil,
+// or if the server doesn't support forwarding stdin.
+func (c *ClientConnection) RunCommand(ctx context.Context, ro *RunOptions, shell bool, stdin io.Reader) error {
+	host_client, err := c.GetHostClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if host_client == nil && stdin != nil && !c.serverInfo.HasCapability(CapabilityCommandInput) {
+		c.Sink.OnJobEvent(&JobEvent{
+			LogEvent: &LogEvent{
+				Host:     c.ServerConfig.Host.Name,
+				Severity: LogEvent_WARNING,
+				Msg:      "st_host doesn't support forwarding stdin. running the command without it."}})
+		stdin = nil
+	}
+	if host_client == nil && stdin == nil {
+		return c.runCommandWithoutInput(ctx, ro, shell)
+	}
+
+	stream, err := c.OpenCommandStream(ctx, ro, shell)
+	if err != nil {
+		return err
+	}
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+	go SendCommandInput(ro, stdin, stream)
+	return c.Drain(stream)
+}
+
+// fetchFile requests the files described by |fo|. The files arrive as
+// ZippedContentEvents.
+func (c *ClientConnection) fetchFile(ctx context.Context, fo *FetchFileOptions) (JobEventReceiver, error) {
+	host_client, err := c.GetHostClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if host_client == nil {
+		return NewBuildHostClient(c.rpcConnection).FetchFile(ctx, fo)
+	}
+
+	result_stream, err := host_client.FetchFile(ctx, fo)
+	if err != nil {
+		return nil, err
+	}
+	return &convertedJobEventReceiver{
+		stream: result_stream,
+		recv: func() ([]*JobEvent, error) {
+			result, err := result_stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			events := []*JobEvent{}
+			for _, e := range result.GetLog() {
+				events = append(events, &JobEvent{LogEvent: e})
+			}
+			if len(result.GetData()) != 0 {
+				events = append(events, &JobEvent{ZippedContent: &ZippedContentEvent{Data: result.GetData()}})
+			}
+			return events, nil
+		}}, nil
+}
+
 type FlagSetter func(*flag.FlagSet)
 type RequestHandler func(context.Context, *ClientConnection, *flag.FlagSet) error
 
@@ -153,19 +376,56 @@ func (h CommandHandler) Execute(ctx context.Context, f *flag.FlagSet, args ...in
 	conn := args[0].(*ClientConnection)
 
 	err := h.handler(ctx, conn, f)
+	if err != nil && ctx.Err() != nil {
+		// The RPC error is less informative than the reason for the
+		// cancellation.
+		err = ctx.Err()
+	}
 	if IsInvalidArgumentError(err) {
 		return subcommands.ExitUsageError
 	}
+	if err != nil && err != io.EOF && conn.lastEndCommand.GetReturnCode() != 0 && ctx.Err() == nil {
+		// The failure has already been reported via the EndCommandEvent.
+		return subcommands.ExitStatus(conn.lastEndCommand.GetReturnCode())
+	}
 	if err != nil && err != io.EOF {
+		msg := err.Error()
+		if error_with_details, ok := err.(ErrorWithDetails); ok && conn.debug && len(error_with_details.Stack) != 0 {
+			msg += "\n" + string(error_with_details.Stack)
+		}
 		conn.Sink.OnJobEvent(&JobEvent{
 			LogEvent: &LogEvent{
 				Severity: LogEvent_ERROR,
-				Msg:      err.Error()}})
+				Msg:      msg}})
 		return subcommands.ExitFailure
 	}
 	return subcommands.ExitSuccess
 }
 
+// EnvironmentFlag collects environment variables specified as KEY=VALUE via
+// repeated uses of a flag.
+type EnvironmentFlag map[string]string
+
+func (e EnvironmentFlag) String() string {
+	pairs := []string{}
+	for key, value := range e {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func (e EnvironmentFlag) Set(value string) error {
+	index := strings.Index(value, "=")
+	if index <= 0 {
+		return NewInvalidArgumentError("environment variable should be specified as KEY=VALUE: %s", value)
+	}
+	e[value[:index]] = value[index+1:]
+	return nil
+}
+
+const kTimeoutFlagUsage = "stop the command if it runs for longer than this. e.g. 30s or 10m. defaults to the platform's timeout, if any."
+
 var (
 	Flag_BranchFilter          string
 	Flag_Force                 bool
@@ -176,6 +436,22 @@ var (
 	Flag_Source                bool
 	Flag_TargetPath            string
 	Flag_AutomaticDependencies bool
+	Flag_RunningOnly           bool
+	Flag_All                   bool
+	Flag_Detach                bool
+	Flag_NoStdin               bool
+	Flag_Terminal              bool
+	Flag_MaxJobs               int
+	Flag_MaxRecords            int
+	Flag_Timeout               time.Duration
+	Flag_Environment           = EnvironmentFlag{}
+	Flag_CertificateDir        string
+	Flag_ClientIdentity        string
+	Flag_AuditIdentity         string
+	Flag_AuditMethod           string
+	Flag_AuditRepository       string
+	Flag_FailedOnly            bool
+	Flag_Since                 time.Duration
 )
 
 var DefaultHandlers = []CommandHandler{
@@ -212,7 +488,9 @@ The shell command specified by [command] and [options] will be executed in the o
     {out} : Expands to the full path to the output directory for the platform.
     {st}  : Expands to the full path to the host's StonesThrow installation.
 
-The tokens are expanded in the option value for '-dir' in addition to the command specification. Shell globs will not be expanded on the remote side.
+The tokens are expanded in the option value for '-dir' and in the values of environment variables specified via '-e' in addition to the command specification. Shell globs will not be expanded on the remote side.
+
+Stdin is forwarded to the command unless -no-stdin or -detach is specified. Use -t to run interactive programs like gdb or less in a pseudo-terminal on the build host.
 
 E.g.:
     run {src}/foo/bar {out}/a
@@ -220,13 +498,16 @@ E.g.:
 ... executes foo/bar relative to the source directory. The only argument to bar is the absolute path to |a| which is assumed to be in the output directory.
 `, func(f *flag.FlagSet) {
 			f.StringVar(&Flag_TargetPath, "dir", "{out}", "directory under which the command should be executed.")
+			f.BoolVar(&Flag_Detach, "detach", false, "run the command in the background. keeps running if the connection drops. use 'attach' to follow its output.")
+			f.BoolVar(&Flag_NoStdin, "no-stdin", false, "don't forward stdin to the command.")
+			f.BoolVar(&Flag_Terminal, "t", false, "run the command in a pseudo-terminal. needed for interactive programs like debuggers.")
+			f.Var(Flag_Environment, "e", "set environment variable. specify as KEY=VALUE. can be repeated.")
+			f.DurationVar(&Flag_Timeout, "timeout", 0, kTimeoutFlagUsage)
 		},
 		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
-			rpc_connection, err := conn.GetConnection(ctx)
-			if err != nil {
-				return err
+			if Flag_Terminal && (Flag_Detach || Flag_NoStdin) {
+				return NewInvalidArgumentError("-t can't be combined with -detach or -no-stdin")
 			}
-			builder_client := NewBuildHostClient(rpc_connection)
 			repo_state, err := GetRepositoryState(
 				ctx, conn.ClientConfig.Repository, conn.Executor, false)
 			if err != nil {
@@ -236,14 +517,53 @@ E.g.:
 				Repository: repo_state.Repository,
 				Revision:   repo_state.Revision,
 				Platform:   conn.ServerConfig.Platform.Name,
+				Detach:     Flag_Detach,
+				Tty:        Flag_Terminal,
 				Command: &ShellCommand{
 					Directory: Flag_TargetPath,
-					Command:   f.Args()}}
-			event_stream, err := builder_client.RunShellCommand(ctx, &run_options)
+					Command:   f.Args(),
+					Env:       Flag_Environment}}
+			err = conn.RequireCapabilities(ctx, commandCapabilities(&run_options, Flag_Timeout)...)
+			if err != nil {
+				return err
+			}
+			if Flag_Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = WithCommandTimeout(ctx, Flag_Timeout)
+				defer cancel()
+			}
+			if Flag_Detach || Flag_NoStdin {
+				return conn.RunCommand(ctx, &run_options, true, nil)
+			}
+			if !Flag_Terminal {
+				return conn.RunCommand(ctx, &run_options, true, os.Stdin)
+			}
+
+			event_stream, err := conn.OpenCommandStream(ctx, &run_options, true)
+			if err != nil {
+				return err
+			}
+
+			// Like 'ssh -t', all input including control characters is
+			// passed along to the remote terminal.
+			run_options.WindowSize = GetTerminalSize(os.Stdout)
+			restore_terminal, err := MakeTerminalRaw(os.Stdin)
+			if err != nil {
+				return err
+			}
+			defer restore_terminal()
+
+			sender := NewSynchronizedCommandInputSender(event_stream)
+			err = sender.Send(&CommandInput{Options: &run_options})
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			stop_watching := WatchTerminalSize(os.Stdout, func(size *WindowSize) {
+				sender.Send(&CommandInput{WindowSize: size})
+			})
+			defer stop_watching()
+			go SendStdin(os.Stdin, sender)
+			return conn.Drain(event_stream)
 		}},
 
 	{"get", "builder",
@@ -263,12 +583,9 @@ E.g.:
 				return NewInvalidArgumentError("too many paths specified")
 			}
 
-			rpc_connection, err := conn.GetConnection(ctx)
-			if err != nil {
-				return err
-			}
-
-			options := FetchFileOptions{}
+			options := FetchFileOptions{
+				Repository: conn.ServerConfig.Repository.Name,
+				Platform:   conn.ServerConfig.Platform.Name}
 			if f.NArg() == 1 {
 				options.FilenameGlob = f.Arg(0)
 			} else {
@@ -277,8 +594,7 @@ E.g.:
 			}
 			options.Recurse = Flag_Recursive
 
-			builder_client := NewBuildHostClient(rpc_connection)
-			stream, err := builder_client.FetchFile(ctx, &options)
+			stream, err := conn.fetchFile(ctx, &options)
 			if err != nil {
 				return err
 			}
@@ -293,7 +609,7 @@ E.g.:
 				sender:     conn.Sink,
 				base_path:  base_path,
 				dont_write: Flag_NoWrite}
-			return conn.Sink.Drain(extractor)
+			return conn.Drain(extractor)
 		}},
 
 	{"ping", "service control",
@@ -303,14 +619,214 @@ E.g.:
 			if err != nil {
 				return err
 			}
-			service_host_client := NewServiceHostClient(rpc_connection)
-			ping_result, err := service_host_client.Ping(ctx, &PingOptions{Ping: "Ping!"})
+			host_client, err := conn.GetHostClient(ctx)
+			if err != nil {
+				return err
+			}
+			var ping_result *PingResult
+			if host_client != nil {
+				ping_result, err = host_client.Ping(ctx, &PingOptions{Ping: "Ping!"})
+			} else {
+				ping_result, err = NewServiceHostClient(rpc_connection).Ping(ctx, &PingOptions{Ping: "Ping!"})
+			}
 			if err != nil {
 				return err
 			}
 			return conn.Sink.OnPong(ping_result)
 		}},
 
+	{"jobs", "service control",
+		`list running and recently completed jobs.`, `Usage: jobs [-r]
+`,
+		func(f *flag.FlagSet) {
+			f.BoolVar(&Flag_RunningOnly, "r", false, "only list jobs that are still running")
+		},
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			rpc_connection, err := conn.GetConnection(ctx)
+			if err != nil {
+				return err
+			}
+			list_options := &ListJobsOptions{IncludeCompleted: !Flag_RunningOnly}
+			host_client, err := conn.GetHostClient(ctx)
+			if err != nil {
+				return err
+			}
+			if host_client == nil {
+				builder_jobs, err := NewServiceHostClient(rpc_connection).ListJobs(ctx, list_options)
+				if err != nil {
+					return err
+				}
+				return conn.Sink.OnBuilderJobs(builder_jobs)
+			}
+
+			result_stream, err := host_client.ListJobs(ctx, list_options)
+			if err != nil {
+				return err
+			}
+			builder_jobs := &BuilderJobs{}
+			for {
+				result, err := result_stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				for _, task := range result.GetTask() {
+					builder_jobs.Jobs = append(builder_jobs.Jobs, NewBuilderJobFromTask(task))
+				}
+			}
+			return conn.Sink.OnBuilderJobs(builder_jobs)
+		}},
+
+	{"kill", "service control",
+		`kill running jobs.`, `Usage: kill [-all] [job id ...]
+
+Terminates the specified jobs along with any processes they started. Use 'jobs' to list job IDs.
+`,
+		func(f *flag.FlagSet) {
+			f.BoolVar(&Flag_All, "all", false, "kill all running jobs")
+		},
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			kill_options := KillJobsOptions{All: Flag_All}
+			for _, arg := range f.Args() {
+				id, err := strconv.ParseInt(arg, 10, 32)
+				if err != nil {
+					return NewInvalidArgumentError("invalid job ID: %s", arg)
+				}
+				kill_options.Id = append(kill_options.Id, int32(id))
+			}
+			if len(kill_options.Id) == 0 && !kill_options.All {
+				return NewInvalidArgumentError("no job IDs specified")
+			}
+
+			rpc_connection, err := conn.GetConnection(ctx)
+			if err != nil {
+				return err
+			}
+			host_client, err := conn.GetHostClient(ctx)
+			if err != nil {
+				return err
+			}
+			if host_client == nil {
+				event_stream, err := NewServiceHostClient(rpc_connection).KillJobs(ctx, &kill_options)
+				if err != nil {
+					return err
+				}
+				return conn.Drain(event_stream)
+			}
+
+			result_stream, err := host_client.KillJobs(ctx, &kill_options)
+			if err != nil {
+				return err
+			}
+			return conn.Drain(&convertedJobEventReceiver{
+				stream: result_stream,
+				recv: func() ([]*JobEvent, error) {
+					result, err := result_stream.Recv()
+					if err != nil {
+						return nil, err
+					}
+					events := []*JobEvent{}
+					for _, e := range result.GetLog() {
+						events = append(events, &JobEvent{LogEvent: e})
+					}
+					for _, task := range result.GetTask() {
+						events = append(events, &JobEvent{Job: NewBuilderJobFromTask(task)})
+					}
+					return events, nil
+				}})
+		}},
+
+	{"attach", "service control",
+		`attach to a running job.`, `Usage: attach job-id
+
+Replays the output of a running or recently completed job and then follows the job until it completes. Interrupting 'attach' doesn't affect the job. Use 'jobs' to list job IDs.
+
+Only jobs started with -detach keep running once the client that started them disconnects. The build host can't tell a lost connection apart from an interrupted client, so other jobs are stopped when their connection drops.
+`, nil,
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			if f.NArg() != 1 {
+				return NewInvalidArgumentError("expected a single job ID")
+			}
+			id, err := strconv.ParseInt(f.Arg(0), 10, 32)
+			if err != nil {
+				return NewInvalidArgumentError("invalid job ID: %s", f.Arg(0))
+			}
+
+			rpc_connection, err := conn.GetConnection(ctx)
+			if err != nil {
+				return err
+			}
+			service_host_client := NewServiceHostClient(rpc_connection)
+			event_stream, err := service_host_client.AttachJob(ctx, &AttachJobOptions{Id: int32(id)})
+			if err != nil {
+				return err
+			}
+			return conn.Drain(event_stream)
+		}},
+
+	{"history", "service control",
+		`list previously run jobs.`, `Usage: history [-all] [-n count]
+
+Lists jobs that were run for the current repository and platform, most recent first. Use 'log' to view the output of a job.
+`,
+		func(f *flag.FlagSet) {
+			f.BoolVar(&Flag_All, "all", false, "include jobs for all repositories and platforms")
+			f.IntVar(&Flag_MaxJobs, "n", 20, "maximum number of jobs to list")
+		},
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			rpc_connection, err := conn.GetConnection(ctx)
+			if err != nil {
+				return err
+			}
+			err = conn.RequireCapabilities(ctx, CapabilityJobHistory)
+			if err != nil {
+				return err
+			}
+			history_options := JobHistoryOptions{MaxJobs: int32(Flag_MaxJobs)}
+			if !Flag_All {
+				history_options.Repository = conn.ServerConfig.Repository.Name
+				history_options.Platform = conn.ServerConfig.Platform.Name
+			}
+			service_host_client := NewServiceHostClient(rpc_connection)
+			builder_jobs, err := service_host_client.GetJobHistory(ctx, &history_options)
+			if err != nil {
+				return err
+			}
+			return conn.Sink.OnBuilderJobs(builder_jobs)
+		}},
+
+	{"log", "service control",
+		`show the output of a previously run job.`, `Usage: log job-id
+
+Shows the recorded output of a job. Use 'history' to list job IDs.
+`, nil,
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			if f.NArg() != 1 {
+				return NewInvalidArgumentError("expected a single job ID")
+			}
+			id, err := strconv.ParseInt(f.Arg(0), 10, 32)
+			if err != nil {
+				return NewInvalidArgumentError("invalid job ID: %s", f.Arg(0))
+			}
+
+			rpc_connection, err := conn.GetConnection(ctx)
+			if err != nil {
+				return err
+			}
+			err = conn.RequireCapabilities(ctx, CapabilityJobHistory)
+			if err != nil {
+				return err
+			}
+			service_host_client := NewServiceHostClient(rpc_connection)
+			event_stream, err := service_host_client.GetJobLog(ctx, &JobLogOptions{Id: int32(id)})
+			if err != nil {
+				return err
+			}
+			return conn.Drain(event_stream)
+		}},
+
 	{"pull", "repository management",
 		`pull a specific branch or branches from upstream.`, "", nil,
 		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
@@ -323,7 +839,7 @@ E.g.:
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			return conn.Drain(event_stream)
 		}},
 
 	{"push", "repository management",
@@ -342,7 +858,7 @@ E.g.:
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			return conn.Drain(event_stream)
 		}},
 
 	{"status", "repository management",
@@ -361,7 +877,7 @@ E.g.:
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			return conn.Drain(event_stream)
 		}},
 
 	{"sync_workdir", "repository management",
@@ -380,7 +896,7 @@ E.g.:
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			return conn.Drain(event_stream)
 		}},
 
 	{"quit", "service control",
@@ -390,18 +906,29 @@ E.g.:
 			if err != nil {
 				return err
 			}
+			host_client, err := conn.GetHostClient(ctx)
+			if err != nil {
+				return err
+			}
+			if host_client != nil {
+				_, err = host_client.Shutdown(ctx, &ShutdownOptions{})
+				return err
+			}
 			service_host_client := NewServiceHostClient(rpc_connection)
 			event_stream, err := service_host_client.Shutdown(ctx, &ShutdownOptions{})
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			return conn.Drain(event_stream)
 		}},
 
 	{"update", "service control",
 		`self-update`, "", nil,
 		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
-			rpc_connection, err := conn.GetConnection(ctx)
+			// Updating is how a server that's too old for this client is
+			// fixed. Hence this uses the ServiceHost service which every
+			// version of the server implements.
+			rpc_connection, err := conn.GetUncheckedConnection(ctx)
 			if err != nil {
 				return err
 			}
@@ -410,7 +937,17 @@ E.g.:
 			if err != nil {
 				return err
 			}
-			return conn.Sink.Drain(event_stream)
+			return conn.Drain(event_stream)
+		}},
+
+	{"version", "service control",
+		`show the version and capabilities of the server.`, "", nil,
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			server_info, err := conn.GetServerInfo(ctx)
+			if err != nil {
+				return err
+			}
+			return conn.Sink.OnHello(server_info)
 		}},
 
 	{"list_targets", "builder",
@@ -428,6 +965,112 @@ E.g.:
 			return conn.Sink.OnTargetList(target_list)
 		}},
 
+	{"route", "service control",
+		"show how the client connects to the server.", "", nil,
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			route, err := FindRoute(conn.ClientConfig.Host, conn.ServerConfig.Host)
+			if err != nil {
+				return err
+			}
+			from := route.Client
+			for _, hop := range route.Hops {
+				msg := fmt.Sprintf("ssh to %s: %s", hop.Host.Name, strings.Join(hop.GetSshPassthroughCommand(&conn.ServerConfig), " "))
+				if hop.NativeSsh {
+					msg = fmt.Sprintf("native ssh to %s via %s", hop.Host.Name, hop.SshHost)
+				}
+				conn.Sink.OnJobEvent(&JobEvent{
+					LogEvent: &LogEvent{
+						Host:     from.Name,
+						Severity: LogEvent_INFO,
+						Msg:      msg}})
+				from = hop.Host
+			}
+			return conn.Sink.OnJobEvent(&JobEvent{
+				LogEvent: &LogEvent{
+					Host:     from.Name,
+					Severity: LogEvent_INFO,
+					Msg:      fmt.Sprintf("connect to %s at %s %s", route.Server.Name, route.Endpoint.Network, route.Endpoint.Address)}})
+		}},
+
+	{"audit", "service control",
+		`show who made which requests to the server.`, `Usage: audit [-user identity] [-method name] [-repo repository] [-failed] [-since duration] [-n count]
+
+Lists the requests recorded in the server's audit log, most recent first. Requests are recorded when they arrive and again when they finish. Each record shows who made the request, the command and jobs, if any, and the error if the request failed. Requires the admin role on the server.
+`,
+		func(f *flag.FlagSet) {
+			f.StringVar(&Flag_AuditIdentity, "user", "", "only show requests made by this identity. e.g. the common name of a client certificate.")
+			f.StringVar(&Flag_AuditMethod, "method", "", "only show requests for RPCs whose name contains this. e.g. Shutdown or Shell.")
+			f.StringVar(&Flag_AuditRepository, "repo", "", "only show requests for this repository.")
+			f.BoolVar(&Flag_FailedOnly, "failed", false, "only show requests that failed.")
+			f.DurationVar(&Flag_Since, "since", 0, "only show requests made within this duration. e.g. 24h.")
+			f.IntVar(&Flag_MaxRecords, "n", 50, "maximum number of records to list")
+		},
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			rpc_connection, err := conn.GetConnection(ctx)
+			if err != nil {
+				return err
+			}
+			err = conn.RequireCapabilities(ctx, CapabilityAuditLog)
+			if err != nil {
+				return err
+			}
+			audit_options := AuditLogOptions{
+				Identity:   Flag_AuditIdentity,
+				Method:     Flag_AuditMethod,
+				Repository: Flag_AuditRepository,
+				FailedOnly: Flag_FailedOnly,
+				MaxRecords: int32(Flag_MaxRecords)}
+			if Flag_Since > 0 {
+				audit_options.Since = NewTimestampFromTime(time.Now().Add(-Flag_Since))
+			}
+			host_client, err := conn.GetHostClient(ctx)
+			if err != nil {
+				return err
+			}
+			var audit_records *AuditRecords
+			if host_client != nil {
+				audit_records, err = host_client.GetAuditLog(ctx, &audit_options)
+			} else {
+				audit_records, err = NewServiceHostClient(rpc_connection).GetAuditLog(ctx, &audit_options)
+			}
+			if err != nil {
+				return err
+			}
+			return conn.Sink.OnAuditRecords(audit_records)
+		}},
+
+	{"certs", "service control",
+		`manage the certificates used by the hosts.`, `Usage: certs [-dir directory] [-force] init|issue|rotate [host ...]
+
+init   : create a private certificate authority (CA). Use -force to replace an
+         existing CA, which invalidates the certificates it issued.
+
+issue  : issue server and client certificates for the specified hosts, or all
+         hosts if none are specified. Server certificates are valid for the
+         host's name and nicknames. The paths are written to the configuration
+         file. With -client <identity>, issues a client certificate for a user
+         instead. Grant the identity a role via the authorization section of
+         each host.
+
+rotate : like issue, but only for hosts whose certificates are missing or
+         expire within 30 days. Use -force to reissue all certificates.
+
+The CA is kept under the state directory next to the configuration file unless
+-dir is specified.
+
+Servers only accept TLS connections from clients that present a certificate
+issued by their CA. Before updating a server, run "certs issue" for the hosts
+that connect to it, or add a "client" entry to their certificates section.
+`,
+		func(f *flag.FlagSet) {
+			f.StringVar(&Flag_CertificateDir, "dir", "", "directory containing the CA.")
+			f.StringVar(&Flag_ClientIdentity, "client", "", "issue a client certificate for this identity.")
+			f.BoolVar(&Flag_Force, "force", false, "replace the CA or reissue certificates that aren't expiring.")
+		},
+		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
+			return RunCertsCommand(conn, Flag_CertificateDir, Flag_ClientIdentity, Flag_Force, f.Args())
+		}},
+
 	{"passthrough", "service control",
 		"run passthrough client.", "Only used internally", nil,
 		func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
@@ -453,10 +1096,19 @@ func RegisterRemoteCommands(ctx context.Context, conn *ClientConnection, command
 		return err
 	}
 
-	builder_client := NewBuildHostClient(rpc_connection)
-	command_list, err := builder_client.ListScriptCommands(ctx, &ListCommandsOptions{
+	list_options := &ListCommandsOptions{
 		Repository: conn.ServerConfig.Repository.Name,
-		Platform:   conn.ServerConfig.Platform.Name})
+		Platform:   conn.ServerConfig.Platform.Name}
+	host_client, err := conn.GetHostClient(ctx)
+	if err != nil {
+		return err
+	}
+	var command_list *CommandList
+	if host_client != nil {
+		command_list, err = host_client.ListCommands(ctx, list_options)
+	} else {
+		command_list, err = NewBuildHostClient(rpc_connection).ListScriptCommands(ctx, list_options)
+	}
 	if err != nil {
 		return err
 	}
@@ -469,13 +1121,13 @@ func RegisterRemoteCommands(ctx context.Context, conn *ClientConnection, command
 			"builder (script)",
 			command.GetDescription(),
 			command.GetUsage(),
-			nil,
+			func(f *flag.FlagSet) {
+				f.BoolVar(&Flag_Detach, "detach", false, "run the command in the background. keeps running if the connection drops. use 'attach' to follow its output.")
+				f.BoolVar(&Flag_NoStdin, "no-stdin", false, "don't forward stdin to the command.")
+				f.Var(Flag_Environment, "e", "set environment variable for the script and the commands it runs. specify as KEY=VALUE. can be repeated.")
+				f.DurationVar(&Flag_Timeout, "timeout", 0, kTimeoutFlagUsage)
+			},
 			func(ctx context.Context, conn *ClientConnection, f *flag.FlagSet) error {
-				rpc_connection, err := conn.GetConnection(ctx)
-				if err != nil {
-					return err
-				}
-				builder_client := NewBuildHostClient(rpc_connection)
 				repo_state, err := GetRepositoryState(
 					ctx, conn.ClientConfig.Repository, conn.Executor,
 					depends_on_source && conn.IsRemote())
@@ -488,23 +1140,34 @@ func RegisterRemoteCommands(ctx context.Context, conn *ClientConnection, command
 					Repository: repo_state.Repository,
 					Revision:   repo_state.Revision,
 					Platform:   conn.ServerConfig.Platform.Name,
+					Detach:     Flag_Detach,
 					Command: &ShellCommand{
 						Command:   args,
-						Directory: "{out}"}}
-
-				event_stream, err := builder_client.RunScriptCommand(ctx, &ro)
+						Directory: "{out}",
+						Env:       Flag_Environment}}
+				err = conn.RequireCapabilities(ctx, commandCapabilities(&ro, Flag_Timeout)...)
 				if err != nil {
 					return err
 				}
 
-				return conn.Sink.Drain(event_stream)
+				if Flag_Timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = WithCommandTimeout(ctx, Flag_Timeout)
+					defer cancel()
+				}
+				if Flag_Detach || Flag_NoStdin {
+					return conn.RunCommand(ctx, &ro, false, nil)
+				}
+				return conn.RunCommand(ctx, &ro, false, os.Stdin)
 			}}
 		commander.Register(handler, handler.group)
 	}
 	return nil
 }
 
-func InvokeCommandline(ctx context.Context, sinkerator func(Config) OutputSink) error {
+// InvokeCommandline parses and runs the command specified on the command line.
+// Returns the exit status for the process.
+func InvokeCommandline(ctx context.Context, sinkerator func(Config) OutputSink) (int, error) {
 	toplevel_flags := flag.NewFlagSet("", flag.ContinueOnError)
 	conn := &ClientConnection{Sinkerator: sinkerator}
 	conn.SetupTopLevelFlags(toplevel_flags)
@@ -517,7 +1180,10 @@ func InvokeCommandline(ctx context.Context, sinkerator func(Config) OutputSink)
 	err := conn.InitFromFlags(ctx, toplevel_flags)
 	if err != nil {
 		toplevel_flags.Usage()
-		return err
+		return int(subcommands.ExitUsageError), err
+	}
+	if conn.debug {
+		ctx = WithErrorStacks(ctx)
 	}
 
 	child_flags := flag.NewFlagSet("", flag.ContinueOnError)
@@ -529,7 +1195,7 @@ func InvokeCommandline(ctx context.Context, sinkerator func(Config) OutputSink)
 	if !IsInvokingBuiltinCommand(toplevel_flags) {
 		err = RegisterRemoteCommands(ctx, conn, commander)
 		if err != nil {
-			return err
+			return int(subcommands.ExitFailure), err
 		}
 	}
 
@@ -538,14 +1204,13 @@ func InvokeCommandline(ctx context.Context, sinkerator func(Config) OutputSink)
 
 	if toplevel_flag_err == flag.ErrHelp {
 		commander.HelpCommand().Execute(ctx, toplevel_flags)
-		return nil
+		return int(subcommands.ExitSuccess), nil
 	}
 
 	err = child_flags.Parse(toplevel_flags.Args())
 	if err != nil {
-		return NewInvalidArgumentError("invalid commandline arguments: %#v", os.Args)
+		return int(subcommands.ExitUsageError), NewInvalidArgumentError("invalid commandline arguments: %#v", os.Args)
 	}
 
-	commander.Execute(ctx, conn)
-	return nil
+	return int(commander.Execute(ctx, conn)), nil
 }
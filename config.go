@@ -1,11 +1,15 @@
 package stonesthrow
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"text/template"
 )
 
@@ -20,6 +24,186 @@ func (c *ConfigurationFile) ReadFrom(filename string) error {
 	return c.HostsConfig.ReadFrom(filename)
 }
 
+// configEdit replaces the bytes from |start| to |end| of a configuration file
+// with |text|.
+type configEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// lineIndent returns the whitespace at the start of the line that contains
+// |offset| in |data|.
+func lineIndent(data []byte, offset int) string {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := start
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// readDelim reads the token |delim| from |decoder|.
+func readDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %s at offset %d", delim, decoder.InputOffset())
+	}
+	return nil
+}
+
+// readMemberName reads the name of the next member of an object from
+// |decoder|.
+func readMemberName(decoder *json.Decoder) (string, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return "", err
+	}
+	name, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a member name at offset %d", decoder.InputOffset())
+	}
+	return name, nil
+}
+
+// certificateEdits returns the edits to the configuration file contents
+// |data| that set the certificates of the hosts in |certificates|. Existing
+// certificates members are replaced. Otherwise one is added after the last
+// member of the host. The new members are indented like their neighbours.
+func certificateEdits(data []byte, certificates map[string]*CertificateConfig) ([]configEdit, map[string]bool, error) {
+	edits := []configEdit{}
+	found := make(map[string]bool)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	err := readDelim(decoder, '{')
+	if err != nil {
+		return nil, nil, err
+	}
+	for decoder.More() {
+		host_name, err := readMemberName(decoder)
+		if err != nil {
+			return nil, nil, err
+		}
+		host_indent := lineIndent(data, int(decoder.InputOffset()))
+		host_certificates, ok := certificates[host_name]
+		if !ok {
+			var value json.RawMessage
+			err = decoder.Decode(&value)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		found[host_name] = true
+
+		err = readDelim(decoder, '{')
+		if err != nil {
+			return nil, nil, err
+		}
+		insert_at := int(decoder.InputOffset())
+		member_indent := host_indent + "\t"
+		has_members := false
+		var edit *configEdit
+		for decoder.More() {
+			has_members = true
+			name, err := readMemberName(decoder)
+			if err != nil {
+				return nil, nil, err
+			}
+			name_end := int(decoder.InputOffset())
+			member_indent = lineIndent(data, name_end)
+			var value json.RawMessage
+			err = decoder.Decode(&value)
+			if err != nil {
+				return nil, nil, err
+			}
+			value_end := int(decoder.InputOffset())
+			if name == "certificates" {
+				value_start := name_end + bytes.IndexByte(data[name_end:], ':') + 1
+				for data[value_start] == ' ' || data[value_start] == '\t' ||
+					data[value_start] == '\r' || data[value_start] == '\n' {
+					value_start++
+				}
+				edit = &configEdit{start: value_start, end: value_end}
+			}
+			insert_at = value_end
+		}
+		err = readDelim(decoder, '}')
+		if err != nil {
+			return nil, nil, err
+		}
+
+		indent_unit := strings.TrimPrefix(member_indent, host_indent)
+		if indent_unit == "" || indent_unit == member_indent {
+			indent_unit = "\t"
+		}
+		value, err := json.MarshalIndent(host_certificates, member_indent, indent_unit)
+		if err != nil {
+			return nil, nil, err
+		}
+		if edit != nil {
+			edit.text = string(value)
+		} else {
+			separator := ""
+			if has_members {
+				separator = ","
+			}
+			edit = &configEdit{start: insert_at, end: insert_at,
+				text: separator + "\n" + member_indent + `"certificates": ` + string(value)}
+		}
+		edits = append(edits, *edit)
+	}
+	return edits, found, nil
+}
+
+// SetCertificates updates the certificate configuration of the hosts in
+// |certificates|, which is keyed by host name, and writes it back to the
+// configuration file. Only the certificates of each host are changed. The
+// rest of the file is left as is.
+func (c *ConfigurationFile) SetCertificates(certificates map[string]*CertificateConfig) error {
+	data, err := ioutil.ReadFile(c.FileName)
+	if err != nil {
+		return err
+	}
+	edits, found, err := certificateEdits(data, certificates)
+	if err != nil {
+		return fmt.Errorf("Can't read configuration file %s : %s", c.FileName, err.Error())
+	}
+
+	for host_name := range certificates {
+		if !found[host_name] {
+			return NewConfigurationError("%s: host %s not found", c.FileName, host_name)
+		}
+	}
+	for host_name, host_certificates := range certificates {
+		if host_config := c.HostsConfig.HostByName(host_name); host_config != nil {
+			host_config.Certificates = host_certificates
+		}
+	}
+
+	updated := []byte{}
+	offset := 0
+	for _, edit := range edits {
+		updated = append(updated, data[offset:edit.start]...)
+		updated = append(updated, edit.text...)
+		offset = edit.end
+	}
+	updated = append(updated, data[offset:]...)
+
+	info, err := os.Stat(c.FileName)
+	if err != nil {
+		return err
+	}
+	temp_file := c.FileName + ".tmp"
+	err = ioutil.WriteFile(temp_file, updated, info.Mode())
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp_file, c.FileName)
+}
+
 // Config describes a configuration where a host, repository and platform is
 // defined.
 type Config struct {
@@ -0,0 +1,160 @@
+package stonesthrow
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"sync"
+	"time"
+)
+
+const kDefaultConnectionIdleTimeout = 5 * time.Minute
+
+// ConnectionPool caches connections to peers so that repeated RPCs to the same
+// host and platform reuse a connection, and for SSH routes, an ssh process.
+// Connections that fail are replaced. Those that aren't used for IdleTimeout
+// are closed.
+//
+// A nil ConnectionPool doesn't cache anything. Each connection is closed when
+// it is released.
+type ConnectionPool struct {
+	IdleTimeout time.Duration
+
+	mutex       sync.Mutex
+	connections map[string]*pooledConnection
+	closed      bool
+
+	// connect is ConnectTo except in tests.
+	connect func(ctx context.Context, client_config, server_config Config) (*grpc.ClientConn, error)
+}
+
+type pooledConnection struct {
+	connection *grpc.ClientConn
+	users      int
+	idleTimer  *time.Timer
+}
+
+func NewConnectionPool(idle_timeout time.Duration) *ConnectionPool {
+	if idle_timeout <= 0 {
+		idle_timeout = kDefaultConnectionIdleTimeout
+	}
+	return &ConnectionPool{
+		IdleTimeout: idle_timeout,
+		connections: make(map[string]*pooledConnection),
+		connect:     ConnectTo}
+}
+
+func connectionPoolKey(server_config Config) string {
+	return server_config.Host.Name + ":" + server_config.Platform.Name
+}
+
+func isHealthy(c *grpc.ClientConn) bool {
+	state := c.GetState()
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
+
+// Get returns a connection to |server_config|. The caller must call the
+// returned function once it's done with the connection. The connection
+// outlives |ctx|.
+func (p *ConnectionPool) Get(ctx context.Context, client_config, server_config Config) (*grpc.ClientConn, func(), error) {
+	if p == nil {
+		connection, err := ConnectTo(context.Background(), client_config, server_config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return connection, func() { connection.Close() }, nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return nil, nil, NewConnectionError("connection pool is closed")
+	}
+
+	key := connectionPoolKey(server_config)
+	pooled, ok := p.connections[key]
+	if ok && !isHealthy(pooled.connection) {
+		// The failed connection is closed once its last user releases
+		// it.
+		if pooled.users == 0 {
+			p.remove(key, pooled)
+		} else {
+			delete(p.connections, key)
+		}
+		ok = false
+	}
+	if !ok {
+		// Connections are shared by RPCs that outlive the one that
+		// established the connection. Hence |ctx| isn't used.
+		connection, err := p.connect(context.Background(), client_config, server_config)
+		if err != nil {
+			return nil, nil, err
+		}
+		pooled = &pooledConnection{connection: connection}
+		p.connections[key] = pooled
+	}
+
+	pooled.users++
+	if pooled.idleTimer != nil {
+		pooled.idleTimer.Stop()
+		pooled.idleTimer = nil
+	}
+	return pooled.connection, func() { p.release(key, pooled) }, nil
+}
+
+func (p *ConnectionPool) release(key string, pooled *pooledConnection) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pooled.users--
+	if pooled.users > 0 {
+		return
+	}
+	if p.closed || p.connections[key] != pooled || !isHealthy(pooled.connection) {
+		p.remove(key, pooled)
+		return
+	}
+	pooled.idleTimer = time.AfterFunc(p.IdleTimeout, func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		if pooled.users == 0 {
+			p.remove(key, pooled)
+		}
+	})
+}
+
+// remove closes |pooled| and removes it from the pool if it's still there.
+// Must be called with |mutex| held.
+func (p *ConnectionPool) remove(key string, pooled *pooledConnection) {
+	if p.connections[key] == pooled {
+		delete(p.connections, key)
+	}
+	if pooled.idleTimer != nil {
+		pooled.idleTimer.Stop()
+		pooled.idleTimer = nil
+	}
+	pooled.connection.Close()
+}
+
+// Len returns the number of connections in the pool.
+func (p *ConnectionPool) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.connections)
+}
+
+// Close closes the connections that aren't in use. Those that are get closed
+// when they are released.
+func (p *ConnectionPool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+	for key, pooled := range p.connections {
+		if pooled.users == 0 {
+			p.remove(key, pooled)
+		} else {
+			delete(p.connections, key)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package stonesthrow
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestConnectionPool(t *testing.T, idle_timeout time.Duration) (*ConnectionPool, *int, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	go server.Serve(listener)
+
+	connects := 0
+	pool := NewConnectionPool(idle_timeout)
+	pool.connect = func(ctx context.Context, client_config, server_config Config) (*grpc.ClientConn, error) {
+		connects++
+		return grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	}
+	return pool, &connects, server.Stop
+}
+
+func testConfigForHost(host string, platform string) Config {
+	return Config{Host: &HostConfig{Name: host}, Platform: &PlatformConfig{Name: platform}}
+}
+
+func TestConnectionPool_Reuse(t *testing.T) {
+	pool, connects, stop := newTestConnectionPool(t, time.Hour)
+	defer stop()
+	defer pool.Close()
+
+	ctx := context.Background()
+	client := testConfigForHost("a", "")
+	first, release_first, err := pool.Get(ctx, client, testConfigForHost("b", "linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release_first()
+
+	second, release_second, err := pool.Get(ctx, client, testConfigForHost("b", "linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release_second()
+	if first != second || *connects != 1 {
+		t.Fatalf("expected the connection to be reused. connected %d times", *connects)
+	}
+
+	other, release_other, err := pool.Get(ctx, client, testConfigForHost("b", "mac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release_other()
+	if other == first || pool.Len() != 2 {
+		t.Fatalf("expected a separate connection per platform. have %d", pool.Len())
+	}
+}
+
+func TestConnectionPool_IdleAndFailed(t *testing.T) {
+	pool, connects, stop := newTestConnectionPool(t, 10*time.Millisecond)
+	defer stop()
+	defer pool.Close()
+
+	ctx := context.Background()
+	client := testConfigForHost("a", "")
+	connection, release, err := pool.Get(ctx, client, testConfigForHost("b", "linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if pool.Len() != 1 {
+		t.Fatal("a connection that's in use shouldn't be closed")
+	}
+	release()
+
+	for i := 0; pool.Len() != 0; i++ {
+		if i == 100 {
+			t.Fatal("idle connection wasn't closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if connection.GetState() != connectivity.Shutdown {
+		t.Fatalf("unexpected state %v", connection.GetState())
+	}
+
+	// A connection that has been shut down is replaced.
+	connection, release, err = pool.Get(ctx, client, testConfigForHost("b", "linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	connection.Close()
+	replacement, release_replacement, err := pool.Get(ctx, client, testConfigForHost("b", "linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release_replacement()
+	if replacement == connection || *connects != 3 {
+		t.Fatalf("expected a new connection. connected %d times", *connects)
+	}
+}
+
+func TestConnectionPool_Nil(t *testing.T) {
+	var pool *ConnectionPool
+	_, _, err := pool.Get(context.Background(), Config{}, Config{})
+	if !IsConfigIncompleteError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package stonesthrow
+
+import (
+	"context"
+	"sync"
+)
+
+// lastEndCommandSender passes events along to |sender| and remembers the last
+// EndCommandEvent. That's the one for the command that was run directly,
+// since the EndCommandEvents of the commands that it reports come before it.
+type lastEndCommandSender struct {
+	sender JobEventSender
+
+	mutex    sync.Mutex
+	endEvent *EndCommandEvent
+}
+
+func (r *lastEndCommandSender) Send(e *JobEvent) error {
+	if e.EndCommandEvent != nil {
+		r.mutex.Lock()
+		r.endEvent = e.EndCommandEvent
+		r.mutex.Unlock()
+	}
+	return r.sender.Send(e)
+}
+
+func (r *lastEndCommandSender) lastEndEvent() *EndCommandEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.endEvent
+}
+
+// RunDetached registers |job| with |adder| and invokes |run| in the
+// background. The ID of the job is returned to the client right away, even if
+// the job has to wait for the JobScheduler before it can start. The job is not
+// tied to the lifetime of |s|. Its events can be retrieved via AttachJob and it
+// can be stopped via KillJob. The commands that |run| executes become child
+// jobs of |job|.
+//
+// |run| should use the context and the JobEventSender that it is passed
+// instead of those associated with |s|.
+func RunDetached(s JobEventServer, adder ProcessAdder, job *BuilderJob, run func(context.Context, JobEventSender) error) error {
+	if adder == nil {
+		return NewNothingToDoError("jobs can't run in the background on this host")
+	}
+	handle := adder.AddJob(job)
+	job_id := handle.Id()
+
+	// The job outlives the RPC, but is still bound by its deadline.
+	ctx := WithParentJob(context.Background(), job_id)
+	cancel_deadline := func() {}
+	if deadline, ok := s.Context().Deadline(); ok {
+		ctx, cancel_deadline = context.WithDeadline(ctx, deadline)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	handle.SetCancel(cancel)
+
+	begin_event := &JobEvent{BeginCommandEvent: &BeginCommandEvent{Command: job.Command, JobId: job_id}}
+	handle.Send(begin_event)
+
+	go func() {
+		defer cancel_deadline()
+		defer cancel()
+		sender := &lastEndCommandSender{sender: handle}
+		err := run(ctx, sender)
+		end_event := sender.lastEndEvent()
+		if end_event == nil {
+			// The command didn't start.
+			if err != nil {
+				SendLog(handle, LogEvent_ERROR, "%s", err.Error())
+			}
+			end_event = &EndCommandEvent{
+				ReturnCode: -1,
+				Cancelled:  ctx.Err() != nil,
+				TimedOut:   ctx.Err() == context.DeadlineExceeded}
+			handle.Send(&JobEvent{EndCommandEvent: end_event})
+		}
+		handle.Finish(end_event)
+	}()
+
+	err := s.Send(begin_event)
+	if err != nil {
+		return err
+	}
+	return SendLog(s, LogEvent_INFO,
+		"job %d is running in the background. Use 'attach %d' to follow its progress.", job_id, job_id)
+}
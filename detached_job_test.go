@@ -0,0 +1,103 @@
+package stonesthrow
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type recordingJobEventServer struct {
+	grpc.ServerStream
+	recordingJobEventSender
+}
+
+func (s *recordingJobEventServer) Context() context.Context {
+	return context.Background()
+}
+
+func waitForJob(t *testing.T, r *JobRegistry, id int32) *BuilderJob {
+	for i := 0; i < 500; i++ {
+		job, err := r.GetJob(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !job.GetState().GetRunning() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %d is still running", id)
+	return nil
+}
+
+func TestRunDetached_Queued(t *testing.T) {
+	r := NewJobRegistry()
+	s := NewJobScheduler(1)
+	release, err := s.Acquire(context.Background(), "linux", 0, NilJobEventSender{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// The job ID is returned while the job is still waiting for the
+	// scheduler.
+	var server recordingJobEventServer
+	job := &BuilderJob{Command: &ShellCommand{Command: []string{"build"}}, Platform: "linux"}
+	err = RunDetached(&server, r, job, func(ctx context.Context, sender JobEventSender) error {
+		release, err := s.Acquire(ctx, "linux", 0, sender)
+		if err != nil {
+			return err
+		}
+		release()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job_id := server.events[0].GetBeginCommandEvent().GetJobId()
+	if job_id == 0 {
+		t.Fatalf("expected a job ID. got %v", server.events)
+	}
+	waitForQueueLength(t, s, 1)
+	if jobs := r.ListJobs(false).GetJobs(); len(jobs) != 1 || jobs[0].Id != job_id {
+		t.Fatalf("unexpected jobs %v", jobs)
+	}
+
+	killed, err := r.KillJob(context.Background(), job_id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !killed.GetState().GetCancelled() {
+		t.Fatalf("unexpected state %v", killed.GetState())
+	}
+	waitForQueueLength(t, s, 0)
+}
+
+func TestRunDetached_ChildJobs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	r := NewJobRegistry()
+	var server recordingJobEventServer
+	job := &BuilderJob{Command: &ShellCommand{Command: []string{"sh", "-c", "exit 3"}}}
+	err := RunDetached(&server, r, job, func(ctx context.Context, sender JobEventSender) error {
+		return NewJobEventExecutor("host", "", "", "", r, sender).ExecutePassthrough(ctx, "sh", "-c", "exit 3")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job_id := server.events[0].GetBeginCommandEvent().GetJobId()
+
+	// The command is a child of the detached job, which ends the same way.
+	finished := waitForJob(t, r, job_id)
+	if finished.GetState().GetReturnCode() != 3 {
+		t.Fatalf("unexpected state %v", finished.GetState())
+	}
+	jobs := r.ListJobs(true).GetJobs()
+	if len(jobs) != 2 || jobs[1].ParentId != job_id {
+		t.Fatalf("unexpected jobs %v", jobs)
+	}
+}
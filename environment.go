@@ -0,0 +1,63 @@
+package stonesthrow
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// MergeEnvironment combines the variables in |envs|. Variables in later maps
+// override those in earlier ones. Tokens like {src} and {out} in the values
+// are expanded using |r|.
+func MergeEnvironment(r *strings.Replacer, envs ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, env := range envs {
+		for key, value := range env {
+			merged[key] = r.Replace(value)
+		}
+	}
+	return merged
+}
+
+const kRedactedValue = "<redacted>"
+
+// redactEnvironment returns a copy of |env| with only the names of the
+// variables. The values may contain secrets, and the environment of a command
+// ends up in job listings and history that are visible to every client.
+func redactEnvironment(env map[string]string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string)
+	for key := range env {
+		redacted[key] = kRedactedValue
+	}
+	return redacted
+}
+
+// environmentForCommand returns the environment for a command that inherits
+// the environment of the current process with the variables in |env| added
+// or replaced. Returns nil if |env| is empty, which causes the environment to
+// be inherited as is.
+func environmentForCommand(env map[string]string) ([]string, error) {
+	if len(env) == 0 {
+		return nil, nil
+	}
+
+	keys := []string{}
+	for key := range env {
+		if key == "" || strings.ContainsAny(key, "=\x00") {
+			return nil, NewInvalidArgumentError("invalid environment variable name \"%s\"", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// exec.Cmd uses the last value if a variable is specified more than
+	// once.
+	result := os.Environ()
+	for _, key := range keys {
+		result = append(result, key+"="+env[key])
+	}
+	return result, nil
+}
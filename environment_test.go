@@ -0,0 +1,66 @@
+package stonesthrow
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMergeEnvironment(t *testing.T) {
+	r := strings.NewReplacer("{src}", "/src", "{out}", "/src/out/Debug")
+	env := MergeEnvironment(r,
+		map[string]string{"CHROME_HEADLESS": "1", "GTEST_FILTER": "*"},
+		map[string]string{"CHROME_DEVEL_SANDBOX": "{out}/chrome_sandbox"},
+		map[string]string{"GTEST_FILTER": "Foo.*"})
+
+	if len(env) != 3 || env["CHROME_HEADLESS"] != "1" || env["GTEST_FILTER"] != "Foo.*" ||
+		env["CHROME_DEVEL_SANDBOX"] != "/src/out/Debug/chrome_sandbox" {
+		t.Fatalf("unexpected environment %v", env)
+	}
+}
+
+func TestJobEventExecutor_Environment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	var events recordingJobEventSender
+	executor := NewJobEventExecutor("host", "", "", "", nil, &events)
+	executor.SetEnvironment(map[string]string{"ST_TEST_VARIABLE": "hello"})
+	output, err := executor.Execute(context.Background(), "sh", "-c", "echo $ST_TEST_VARIABLE $PATH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(output, "hello /") {
+		t.Fatalf("environment wasn't set or inherited. output: %q", output)
+	}
+
+	// Only the names of the variables are reported.
+	env := events.events[0].GetBeginCommandEvent().GetCommand().GetEnv()
+	if len(env) != 1 || env["ST_TEST_VARIABLE"] != kRedactedValue {
+		t.Fatalf("unexpected environment in BeginCommandEvent %v", env)
+	}
+
+	executor.SetEnvironment(map[string]string{"A=B": "C"})
+	_, err = executor.Execute(context.Background(), "true")
+	if !IsInvalidArgumentError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestEnvironmentFlag(t *testing.T) {
+	env := EnvironmentFlag{}
+	for _, value := range []string{"GTEST_FILTER=Foo.*", "EMPTY=", "X=a=b"} {
+		err := env.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if env.String() != "EMPTY= GTEST_FILTER=Foo.* X=a=b" {
+		t.Fatalf("unexpected environment %s", env.String())
+	}
+	if env.Set("=foo") == nil || env.Set("FOO") == nil {
+		t.Fatal("expected invalid values to be rejected")
+	}
+}
@@ -2,25 +2,31 @@ package stonesthrow
 
 import (
 	"fmt"
+	"google.golang.org/grpc/codes"
 	"runtime/debug"
 )
 
+// ErrorWithDetails is an error that belongs to an error class. |Code| is the
+// gRPC status code used when the error is returned by an RPC. The stack isn't
+// part of the error message. Use the -debug flag to see it.
 type ErrorWithDetails struct {
 	ErrorClass string
 	Details    string
 	Stack      []byte
+	Code       codes.Code
 }
 
 func (e ErrorWithDetails) Error() string {
-	return fmt.Sprintf("%s: %s: %s", e.ErrorClass, e.Details, e.Stack)
+	return fmt.Sprintf("%s: %s", e.ErrorClass, e.Details)
 }
 
-func NewErrorClass(class string) (func(string, ...interface{}) error, func(error) bool) {
+func NewErrorClass(class string, code codes.Code) (func(string, ...interface{}) error, func(error) bool) {
 	return func(details string, extra ...interface{}) error {
 			return ErrorWithDetails{
 				ErrorClass: class,
 				Details:    fmt.Sprintf(details, extra...),
-				Stack:      debug.Stack()}
+				Stack:      debug.Stack(),
+				Code:       code}
 		},
 		func(err error) bool {
 			error_with_details, ok := err.(ErrorWithDetails)
@@ -29,26 +35,30 @@ func NewErrorClass(class string) (func(string, ...interface{}) error, func(error
 }
 
 var (
-	NewConfigurationError, IsConfigurationError                         = NewErrorClass("configuration error")
-	NewConfigIncompleteError, IsConfigIncompleteError                   = NewErrorClass("configuration incomplete")
-	NewDepsChangedError, IsDepsChangedError                             = NewErrorClass("DEPS changed")
-	NewEmptyCommandError, IsEmptyCommandError                           = NewErrorClass("empty command")
-	NewExternalCommandFailedError, IsExternalCommandFailedError         = NewErrorClass("external command failed")
-	NewInvalidArgumentError, IsInvalidArgumentError                     = NewErrorClass("invalid argument")
-	NewInvalidWrappedMessageTypeError, IsInvalidWrappedMessageTypeError = NewErrorClass("invalid message type during unwrap")
-	NewInvalidMessageTypeError, IsInvalidMessageTypeError               = NewErrorClass("invalid message type during wrap")
-	NewInvalidPlatformError, IsInvalidPlatformError                     = NewErrorClass("invalid platform")
-	NewInvalidRepositoryError, IsInvalidRepositoryError                 = NewErrorClass("invalid repository")
-	NewNoRouteToTargetError, IsNoRouteToTargetError                     = NewErrorClass("no route to target host")
-	NewNoTargetError, IsNoTargetError                                   = NewErrorClass("no target specified")
-	NewNoUpstreamError, IsNoUpstreamError                               = NewErrorClass("no upstream configured for this repository")
-	NewOnlyOnMasterError, IsOnlyOnMasterError                           = NewErrorClass("command is only available on master")
-	NewTimedOutError, IsTimedOutError                                   = NewErrorClass("timed out")
-	NewUnmergedChangesExistError, IsUnmergedChangesExistError           = NewErrorClass("working directory has unmerged changes")
-	NewUnrecognizedResponseError, IsUnrecognizedResponseError           = NewErrorClass("server sent an unrecognized response")
-	NewWorkTreeDirtyError, IsWorkTreeDirtyError                         = NewErrorClass("working directory is dirty")
-	NewFailedToPushGitBranchError, IsFailedToPushGitBranchError         = NewErrorClass("failed to push git branch")
-	NewEndpointNotFoundError, IsEndpointNotFoundError                   = NewErrorClass("endpoint not found")
-	NewNothingToDoError, IsNothingToDoError                             = NewErrorClass("nothing to do")
-	NewConnectionError, IsConnectionError                               = NewErrorClass("connection failed")
+	NewConfigurationError, IsConfigurationError                         = NewErrorClass("configuration error", codes.FailedPrecondition)
+	NewConfigIncompleteError, IsConfigIncompleteError                   = NewErrorClass("configuration incomplete", codes.FailedPrecondition)
+	NewDepsChangedError, IsDepsChangedError                             = NewErrorClass("DEPS changed", codes.FailedPrecondition)
+	NewEmptyCommandError, IsEmptyCommandError                           = NewErrorClass("empty command", codes.InvalidArgument)
+	NewExternalCommandFailedError, IsExternalCommandFailedError         = NewErrorClass("external command failed", codes.Unknown)
+	NewIncompatibleVersionError, IsIncompatibleVersionError             = NewErrorClass("incompatible version", codes.FailedPrecondition)
+	NewInvalidArgumentError, IsInvalidArgumentError                     = NewErrorClass("invalid argument", codes.InvalidArgument)
+	NewJobNotFoundError, IsJobNotFoundError                             = NewErrorClass("job not found", codes.NotFound)
+	NewInvalidWrappedMessageTypeError, IsInvalidWrappedMessageTypeError = NewErrorClass("invalid message type during unwrap", codes.Internal)
+	NewInvalidMessageTypeError, IsInvalidMessageTypeError               = NewErrorClass("invalid message type during wrap", codes.Internal)
+	NewInvalidPlatformError, IsInvalidPlatformError                     = NewErrorClass("invalid platform", codes.InvalidArgument)
+	NewInvalidRepositoryError, IsInvalidRepositoryError                 = NewErrorClass("invalid repository", codes.InvalidArgument)
+	NewNoRouteToTargetError, IsNoRouteToTargetError                     = NewErrorClass("no route to target host", codes.Unavailable)
+	NewNoTargetError, IsNoTargetError                                   = NewErrorClass("no target specified", codes.InvalidArgument)
+	NewNoUpstreamError, IsNoUpstreamError                               = NewErrorClass("no upstream configured for this repository", codes.FailedPrecondition)
+	NewNotSupportedError, IsNotSupportedError                           = NewErrorClass("not supported", codes.Unimplemented)
+	NewOnlyOnMasterError, IsOnlyOnMasterError                           = NewErrorClass("command is only available on master", codes.FailedPrecondition)
+	NewTimedOutError, IsTimedOutError                                   = NewErrorClass("timed out", codes.DeadlineExceeded)
+	NewUnmergedChangesExistError, IsUnmergedChangesExistError           = NewErrorClass("working directory has unmerged changes", codes.FailedPrecondition)
+	NewUnrecognizedResponseError, IsUnrecognizedResponseError           = NewErrorClass("server sent an unrecognized response", codes.Internal)
+	NewWorkTreeDirtyError, IsWorkTreeDirtyError                         = NewErrorClass("working directory is dirty", codes.FailedPrecondition)
+	NewFailedToPushGitBranchError, IsFailedToPushGitBranchError         = NewErrorClass("failed to push git branch", codes.Aborted)
+	NewEndpointNotFoundError, IsEndpointNotFoundError                   = NewErrorClass("endpoint not found", codes.NotFound)
+	NewNothingToDoError, IsNothingToDoError                             = NewErrorClass("nothing to do", codes.FailedPrecondition)
+	NewConnectionError, IsConnectionError                               = NewErrorClass("connection failed", codes.Unavailable)
+	NewPermissionDeniedError, IsPermissionDeniedError                   = NewErrorClass("permission denied", codes.PermissionDenied)
 )
@@ -2,6 +2,7 @@ package stonesthrow
 
 import (
 	"context"
+	"crypto/tls"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"io"
@@ -18,33 +19,44 @@ func wrapConnectError(c *grpc.ClientConn, e error) (*grpc.ClientConn, error) {
 	return c, e
 }
 
+// getCredentialsForClient returns the TLS credentials for connecting from
+// |client_config|. The client identifies itself using its client certificate
+// if it has one. Whether a certificate is required is up to the server.
+// Servers that don't authorize clients don't ask for one.
 func getCredentialsForClient(client_config Config) (credentials.TransportCredentials, error) {
-	if client_config.Host.Certificates == nil || client_config.Host.Certificates.RootCert == nil {
+	certificates := client_config.Host.Certificates
+	if certificates == nil || certificates.RootCert == nil {
 		return nil, NewConfigIncompleteError("Client does not specify a CA certificate")
 	}
 
-	return credentials.NewClientTLSFromFile(client_config.Host.Certificates.RootCert.CertificateFile, "")
-}
-
-func connectToLocalEndpoint(ctx context.Context, client_config, server_config Config, endpoint Endpoint) (*grpc.ClientConn, error) {
-	creds, err := getCredentialsForClient(client_config)
+	root_cas, err := loadCertPool(certificates.RootCert.CertificateFile)
 	if err != nil {
 		return nil, err
 	}
-
-	return wrapConnectError(
-		grpc.DialContext(ctx, endpoint.Address,
-			grpc.WithAuthority(server_config.Host.Name),
-			grpc.WithTransportCredentials(creds)))
+	tls_config := &tls.Config{RootCAs: root_cas}
+	if certificates.ClientCert != nil {
+		client_cert, err := tls.LoadX509KeyPair(certificates.ClientCert.CertificateFile, certificates.ClientCert.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tls_config.Certificates = []tls.Certificate{client_cert}
+	}
+	return credentials.NewTLS(tls_config), nil
 }
 
-func connectViaSsh(ctx context.Context, client_config, server_config Config, remote RemoteTransportConfig) (*grpc.ClientConn, error) {
-	creds, err := getCredentialsForClient(client_config)
-	if err != nil {
-		return nil, err
+func connectToLocalEndpoint(ctx context.Context, server_config Config, endpoint Endpoint, security grpc.DialOption) (*grpc.ClientConn, error) {
+	options := append(RpcDialOptions(), grpc.WithAuthority(server_config.Host.Name), security)
+	if endpoint.Network == "unix" {
+		options = append(options, grpc.WithDialer(func(address string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", address, timeout)
+		}))
 	}
+	return wrapConnectError(grpc.DialContext(ctx, endpoint.Address, options...))
+}
 
-	command_line := remote.GetSshPassthroughCommand(&server_config)
+// startSshPassthrough runs |command_line|, which is an ssh passthrough
+// command, and returns a connection via its stdin and stdout.
+func startSshPassthrough(ctx context.Context, command_line []string) (net.Conn, error) {
 	cmd := exec.CommandContext(ctx, command_line[0], command_line[1:]...)
 
 	writeEnd, err := cmd.StdinPipe()
@@ -56,15 +68,31 @@ func connectViaSsh(ctx context.Context, client_config, server_config Config, rem
 		return nil, err
 	}
 	cmd.Stderr = os.Stderr
-	cmd.Start()
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	// ssh exits once the connection is closed, which closes its stdin.
+	go cmd.Wait()
+	return PipeConnection{reader: readEnd, writer: writeEnd}, nil
+}
 
+func connectViaSsh(ctx context.Context, server_config Config, remote RemoteTransportConfig, security grpc.DialOption) (*grpc.ClientConn, error) {
+	// gRPC dials again when the connection is lost. Each dial starts a new
+	// ssh process, or opens a new channel if using the native transport.
+	// Hence the connection recovers if ssh dies.
+	command_line := remote.GetSshPassthroughCommand(&server_config)
 	return wrapConnectError(
-		grpc.DialContext(ctx, server_config.Host.Name+":"+server_config.Platform.Name,
+		grpc.DialContext(ctx, server_config.Host.Name+":"+server_config.Platform.Name, append(RpcDialOptions(),
 			grpc.WithAuthority(server_config.Host.Name),
-			grpc.WithTransportCredentials(creds),
+			security,
 			grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
-				return PipeConnection{reader: readEnd, writer: writeEnd}, nil
-			})))
+				if remote.NativeSsh {
+					return dialNativeSsh(ctx, &remote, &server_config)
+				}
+				return startSshPassthrough(ctx, command_line)
+			}))...))
 }
 
 func ConnectTo(ctx context.Context, client_config, server_config Config) (*grpc.ClientConn, error) {
@@ -72,39 +100,48 @@ func ConnectTo(ctx context.Context, client_config, server_config Config) (*grpc.
 		return nil, NewConfigIncompleteError("Client or server configuration is invalid")
 	}
 
-	// If the server has an endpoint on the client machine, then talk to that endpoint.
-	if endpoint := server_config.Host.GetEndpointOnHost(client_config.Host); endpoint != nil {
-		return connectToLocalEndpoint(ctx, client_config, server_config, *endpoint)
+	// Each host along the route runs a passthrough client that connects to
+	// the next one.
+	route, err := FindRoute(client_config.Host, server_config.Host)
+	if err != nil {
+		return nil, err
 	}
 
-	// If we can ssh directly to the server, then do so.
-	for _, remote := range client_config.Host.Remotes {
-		if remote.Host == server_config.Host {
-			return connectViaSsh(ctx, client_config, server_config, *remote)
+	// Connections that end at a unix socket are authorized based on the
+	// user at the other end of the socket instead of using TLS. For routes
+	// with ssh hops, the user is the one that ssh logs in as.
+	security := grpc.WithInsecure()
+	if route.Endpoint.Network != "unix" {
+		creds, err := getCredentialsForClient(client_config)
+		if err != nil {
+			return nil, err
 		}
+		security = grpc.WithTransportCredentials(creds)
 	}
 
-	// Finally, if we can ssh to a host that has an endpoint for the target server, do so.
-	for _, remote := range client_config.Host.Remotes {
-		if endpoint := server_config.Host.GetEndpointOnHost(remote.Host); endpoint != nil {
-			return connectViaSsh(ctx, client_config, server_config, *remote)
-		}
+	if len(route.Hops) == 0 {
+		return connectToLocalEndpoint(ctx, server_config, *route.Endpoint, security)
 	}
-
-	// We don't try any harder than this.
-	return nil, NewNoRouteToTargetError("Target server %s", server_config.Host.Name)
+	return connectViaSsh(ctx, server_config, *route.Hops[0], security)
 }
 
+// RunPassthroughClient connects stdin and stdout to the server. If the server
+// doesn't have an endpoint on this host, the connection goes via the next hop
+// on the route to the server, which in turn runs a passthrough client.
 func RunPassthroughClient(client_config, server_config Config) error {
-	endpoint := server_config.Host.GetEndpointOnHost(server_config.Host)
-
-	if endpoint == nil {
-		return NewInvalidPlatformError(
-			"Currently we only support a single hop for SSH passthrough. " +
-				"Hence the server must host the endpoint.")
+	route, err := FindRoute(client_config.Host, server_config.Host)
+	if err != nil {
+		return err
 	}
 
-	conn, err := net.Dial(endpoint.Network, endpoint.Address)
+	var conn net.Conn
+	if len(route.Hops) == 0 {
+		conn, err = net.Dial(route.Endpoint.Network, route.Endpoint.Address)
+	} else if route.Hops[0].NativeSsh {
+		conn, err = dialNativeSsh(context.Background(), route.Hops[0], &server_config)
+	} else {
+		conn, err = startSshPassthrough(context.Background(), route.Hops[0].GetSshPassthroughCommand(&server_config))
+	}
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package stonesthrow
 
 import (
+	"path/filepath"
 	"strings"
 )
 
@@ -9,9 +10,19 @@ type CertificateLocator struct {
 	KeyFile         string `json:"key,omitempty"`
 }
 
+// CertificateConfig locates the certificates used for TLS. |RootCert| is the
+// CA that issues both server and client certificates. The server on the host
+// uses |ServerCert|, and clients on the host, including the server when it
+// connects to its peers, use |ClientCert|.
 type CertificateConfig struct {
 	RootCert   *CertificateLocator `json:"root,omitempty"`
 	ServerCert *CertificateLocator `json:"server,omitempty"`
+	ClientCert *CertificateLocator `json:"client,omitempty"`
+}
+
+type JobHistoryConfig struct {
+	MaxJobs    int `json:"max_jobs,omitempty"`
+	MaxAgeDays int `json:"max_age_days,omitempty"`
 }
 
 type HostConfig struct {
@@ -21,14 +32,26 @@ type HostConfig struct {
 	GoPath          string                            `json:"go_path,omitempty"`
 	StonesthrowPath string                            `json:"stonesthrow,omitempty"`
 	MaxBuildJobs    int                               `json:"max_build_jobs,omitempty"`
+	MaxHeavyJobs    int                               `json:"max_heavy_jobs,omitempty"`
 	Remotes         map[string]*RemoteTransportConfig `json:"remotes,omitempty"`
 	Certificates    *CertificateConfig                `json:"certificates,omitempty"`
 	ScriptPath      string                            `json:"scripts"`
 	EndpointStrings map[string]string                 `json:"endpoints"`
+	StatePath       string                            `json:"state_path,omitempty"`
+	JobHistory      *JobHistoryConfig                 `json:"job_history,omitempty"`
+	Env             map[string]string                 `json:"env,omitempty"`
+
+	// Connections to peers are closed after this many minutes without use.
+	ConnectionIdleMinutes int `json:"connection_idle_minutes,omitempty"`
+
+	// Maps the common names of client certificates to the role that the
+	// client has on this host. "*" matches any other client.
+	Authorization map[string]string `json:"authorization,omitempty"`
 
-	Name        string              `json:"-"`
-	HostsConfig *HostsConfig        `json:"-"`
-	Endpoints   map[string]Endpoint `json:"-"`
+	Name        string                `json:"-"`
+	HostsConfig *HostsConfig          `json:"-"`
+	Endpoints   map[string][]Endpoint `json:"-"`
+	Roles       map[string]Role       `json:"-"`
 }
 
 func (h *HostConfig) IsWildcard() bool {
@@ -54,24 +77,38 @@ func (h *HostConfig) Normalize(hosts *HostsConfig) error {
 		}
 	}
 
-	h.Endpoints = make(map[string]Endpoint)
-	for host, ep_string := range h.EndpointStrings {
-		components := strings.Split(ep_string, ",")
-		if len(components) == 2 {
-			h.Endpoints[host] = Endpoint{
+	// EndpointStrings maps each host to the endpoints that it can reach the
+	// server at. E.g. "tcp,127.0.0.1:9761" or "unix,/tmp/st.sock". Multiple
+	// endpoints are separated by ';'.
+	h.Endpoints = make(map[string][]Endpoint)
+	for host, ep_strings := range h.EndpointStrings {
+		host_config := hosts.HostByName(host)
+		if host_config == nil {
+			return NewConfigurationError("%s: Endpoint host %s can't be resolved",
+				h.Name, host)
+		}
+		for _, ep_string := range strings.Split(ep_strings, ";") {
+			components := strings.SplitN(strings.TrimSpace(ep_string), ",", 2)
+			if len(components) != 2 {
+				return NewConfigurationError("Address \"%s\" was invalid. Should be of the form <network>,<address>", ep_string)
+			}
+			h.Endpoints[host] = append(h.Endpoints[host], Endpoint{
 				Network:  components[0],
 				Address:  components[1],
 				HostName: host,
-				Host:     hosts.HostByName(host)}
-			if h.Endpoints[host].Host == nil {
-				return NewConfigurationError("%s: Endpoint host %s can't be resolved",
-					h.Name, host)
-			}
-		} else {
-			return NewConfigurationError("Address \"%s\" was invalid. Should be of the form <network>,<address>", ep_string)
+				Host:     host_config})
 		}
 	}
 
+	h.Roles = make(map[string]Role)
+	for identity, role_name := range h.Authorization {
+		role, err := ParseRole(role_name)
+		if err != nil {
+			return NewConfigurationError("%s: authorization for %s: %s", h.Name, identity, err.Error())
+		}
+		h.Roles[identity] = role
+	}
+
 	return h.Validate()
 }
 
@@ -121,16 +158,62 @@ func (h *HostConfig) IsSameHost(hostname string) bool {
 	return false
 }
 
-func (p *HostConfig) GetEndpointOnHost(host *HostConfig) *Endpoint {
-	ep, ok := p.Endpoints[host.Name]
-	if ok && ep.Host == host {
-		return &ep
+// GetStatePath returns the directory where the host keeps persistent state
+// like its job history. Unless overridden in the configuration, each host uses
+// a directory named after the host under a "state" directory next to the
+// configuration file.
+func (h *HostConfig) GetStatePath() string {
+	if h.StatePath != "" {
+		return h.StatePath
+	}
+	config_file := ""
+	if h.HostsConfig != nil && h.HostsConfig.ConfigurationFile != nil {
+		config_file = h.HostsConfig.ConfigurationFile.FileName
 	}
+	return filepath.Join(filepath.Dir(config_file), "state", h.Name)
+}
+
+// GetMaxHeavyJobs returns the number of heavy jobs that can run concurrently
+// on the host, or zero if there's no limit. Each build already runs up to
+// |MaxBuildJobs| processes, so hosts that set it run one heavy job at a time
+// unless the configuration says otherwise. A negative limit means no limit.
+func (h *HostConfig) GetMaxHeavyJobs() int {
+	switch {
+	case h.MaxHeavyJobs > 0:
+		return h.MaxHeavyJobs
+	case h.MaxHeavyJobs == 0 && h.MaxBuildJobs > 0:
+		return kDefaultMaxHeavyJobs
+	}
+	return 0
+}
 
-	for _, ep = range p.Endpoints {
-		if ep.Host == host {
+// GetEndpointsOnHost returns the endpoints via which the server on |p| can be
+// reached from |host|.
+func (p *HostConfig) GetEndpointsOnHost(host *HostConfig) []Endpoint {
+	endpoints, ok := p.Endpoints[host.Name]
+	if ok && len(endpoints) != 0 && endpoints[0].Host == host {
+		return endpoints
+	}
+
+	for _, endpoints = range p.Endpoints {
+		if len(endpoints) != 0 && endpoints[0].Host == host {
+			return endpoints
+		}
+	}
+	return nil
+}
+
+// GetEndpointOnHost returns the endpoint that clients on |host| should use.
+// Unix sockets are preferred since they don't need TLS.
+func (p *HostConfig) GetEndpointOnHost(host *HostConfig) *Endpoint {
+	endpoints := p.GetEndpointsOnHost(host)
+	for _, ep := range endpoints {
+		if ep.Network == "unix" {
 			return &ep
 		}
 	}
+	if len(endpoints) != 0 {
+		return &endpoints[0]
+	}
 	return nil
 }
@@ -0,0 +1,241 @@
+package stonesthrow
+
+import (
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const kRefsBundleChunkSize = 32 * 1024
+
+// HostServerImpl implements the Host service using the implementations of the
+// BuildHost, RepositoryHost and ServiceHost services.
+type HostServerImpl struct {
+	Service    *ServiceHostServerImpl
+	Repository *RepositoryHostServerImpl
+	Build      *BuildHostServerImpl
+}
+
+func (h *HostServerImpl) Hello(ctx context.Context, ho *HelloOptions) (*HelloResult, error) {
+	return h.Service.Hello(ctx, ho)
+}
+
+func (h *HostServerImpl) Ping(ctx context.Context, po *PingOptions) (*PingResult, error) {
+	return h.Service.Ping(ctx, po)
+}
+
+func (h *HostServerImpl) ListJobs(l *ListJobsOptions, s Host_ListJobsServer) error {
+	builder_jobs, err := h.Service.ListJobs(s.Context(), l)
+	if err != nil {
+		return err
+	}
+	result := &ListJobsResult{}
+	for _, job := range builder_jobs.GetJobs() {
+		result.Task = append(result.Task, NewTaskFromBuilderJob(job))
+	}
+	return s.Send(result)
+}
+
+func (h *HostServerImpl) KillJobs(ko *KillJobsOptions, s Host_KillJobsServer) error {
+	return h.Service.KillJobs(ko, convertedJobEventServer{s, func(je *JobEvent) error {
+		result := &KillJobsResult{}
+		if je.GetJob() != nil {
+			result.Task = []*Task{NewTaskFromBuilderJob(je.GetJob())}
+		}
+		if je.GetLogEvent() != nil {
+			result.Log = []*LogEvent{je.GetLogEvent()}
+		}
+		return s.Send(result)
+	}})
+}
+
+func (h *HostServerImpl) GetAuditLog(ctx context.Context, ao *AuditLogOptions) (*AuditRecords, error) {
+	return h.Service.GetAuditLog(ctx, ao)
+}
+
+// Shutdown returns right away. The host stops once the RPCs that are in
+// progress are done.
+func (h *HostServerImpl) Shutdown(ctx context.Context, o *ShutdownOptions) (*ShutdownResult, error) {
+	err := h.Service.Shutdown(o, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ShutdownResult{State: h.Service.stoppedState()}, nil
+}
+
+func (h *HostServerImpl) SelfUpdate(ctx context.Context, o *SelfUpdateOptions) (*SelfUpdateResult, error) {
+	err := h.Service.SelfUpdate(o, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SelfUpdateResult{State: h.Service.stoppedState()}, nil
+}
+
+func (h *HostServerImpl) RunCommand(s Host_RunCommandServer) error {
+	ro, stdin, err := ReceiveCommandInput(s)
+	if err != nil {
+		return err
+	}
+	if ro.GetShell() {
+		err = RequireRole(s.Context(), RoleAdmin)
+		if err != nil {
+			return err
+		}
+	}
+
+	host_name := h.Build.Host.Name
+	server := convertedJobEventServer{s, func(je *JobEvent) error {
+		te := NewTaskEventFromJobEvent(host_name, je)
+		if te == nil {
+			return nil
+		}
+		return s.Send(te)
+	}}
+
+	switch {
+	case ro.GetPlatform() == "" && ro.GetShell():
+		return h.Repository.runShellCommand(ro, stdin, server)
+
+	case ro.GetPlatform() == "":
+		return h.Repository.runScriptCommand(ro, stdin, server)
+
+	case ro.GetShell():
+		return h.Build.runShellCommand(ro, stdin, server)
+
+	default:
+		return h.Build.runScriptCommand(ro, stdin, server)
+	}
+}
+
+func (h *HostServerImpl) ListCommands(ctx context.Context, l *ListCommandsOptions) (*CommandList, error) {
+	if l.GetPlatform() == "" && l.GetRepository() != "" {
+		return h.Repository.ListScriptCommands(ctx, l)
+	}
+	return h.Build.ListScriptCommands(ctx, l)
+}
+
+func (h *HostServerImpl) FetchFile(fo *FetchFileOptions, s Host_FetchFileServer) error {
+	server := convertedJobEventServer{s, func(je *JobEvent) error {
+		result := &FetchFileResult{Data: je.GetZippedContent().GetData()}
+		if je.GetLogEvent() != nil {
+			result.Log = []*LogEvent{je.GetLogEvent()}
+		}
+		return s.Send(result)
+	}}
+
+	if fo.GetPlatform() == "" && fo.GetRepository() != "" {
+		return h.Repository.FetchFile(fo, server)
+	}
+	return h.Build.FetchFile(fo, server)
+}
+
+func (h *HostServerImpl) getRepositoryCommands(repository string) (RepositoryCommands, error) {
+	repo, err := h.Repository.getRepository(&GetKnownRefsOptions{Repository: repository})
+	if err != nil {
+		return RepositoryCommands{}, err
+	}
+	_, commands := h.Repository.GetGitCommandsForJobEventSender(nil, repo)
+	return commands, nil
+}
+
+func (h *HostServerImpl) ExtractRefs(eo *ExtractRefsOptions, s Host_ExtractRefsServer) error {
+	commands, err := h.getRepositoryCommands(eo.GetRepository())
+	if err != nil {
+		return err
+	}
+
+	bundle_file, err := ioutil.TempFile("", "st-bundle")
+	if err != nil {
+		return err
+	}
+	bundle_file.Close()
+	defer os.Remove(bundle_file.Name())
+
+	base_revisions := []string{}
+	for _, revision := range eo.GetBaseRevision() {
+		base_revisions = append(base_revisions, revision.GetId())
+	}
+	err = commands.GitCreateBundle(s.Context(), bundle_file.Name(), eo.GetRevision().GetName(), base_revisions)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := os.Open(bundle_file.Name())
+	if err != nil {
+		return err
+	}
+	defer bundle.Close()
+
+	buffer := make([]byte, kRefsBundleChunkSize)
+	for {
+		count, err := bundle.Read(buffer)
+		if count > 0 {
+			chunk := make([]byte, count)
+			copy(chunk, buffer[:count])
+			send_err := s.Send(&ExtractRefsResult{Bundle: chunk})
+			if send_err != nil {
+				return send_err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (h *HostServerImpl) ApplyRefs(s Host_ApplyRefsServer) error {
+	bundle_file, err := ioutil.TempFile("", "st-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(bundle_file.Name())
+	defer bundle_file.Close()
+
+	repository := ""
+	for {
+		options, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if repository == "" {
+			repository = options.GetRepository()
+		}
+		_, err = bundle_file.Write(options.GetBundle())
+		if err != nil {
+			return err
+		}
+	}
+	err = bundle_file.Close()
+	if err != nil {
+		return err
+	}
+
+	commands, err := h.getRepositoryCommands(repository)
+	if err != nil {
+		return err
+	}
+	revisions, err := commands.GitFetchBundle(s.Context(), bundle_file.Name())
+	if err != nil {
+		return err
+	}
+	return s.SendAndClose(&ApplyRefsResult{Revision: revisions})
+}
+
+func (h *HostServerImpl) GetKnownRefs(ctx context.Context, ko *GetKnownRefsOptions) (*GetKnownRefsResult, error) {
+	commands, err := h.getRepositoryCommands(ko.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := commands.GitListRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GetKnownRefsResult{Revision: revisions}, nil
+}
@@ -0,0 +1,266 @@
+package stonesthrow
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	kDefaultMaxJobHistory    = 200
+	kDefaultMaxJobHistoryAge = 30 * 24 * time.Hour
+)
+
+// JobHistory persists the metadata and the complete event stream for each job
+// that's run on a host. For a job with ID 12, the metadata is stored in
+// 12.json and the events are stored as JSON lines in 12.log. Both use the
+// protobuf JSON mapping.
+type JobHistory struct {
+	Path    string
+	MaxJobs int
+	MaxAge  time.Duration
+
+	mutex sync.Mutex
+}
+
+// OpenJobHistory opens or creates the job history in |path|. Jobs that were
+// still marked as running are assumed to have been interrupted when the
+// server went away, and are recorded as cancelled at the time the history is
+// opened.
+func OpenJobHistory(path string, config *JobHistoryConfig) (*JobHistory, error) {
+	h := &JobHistory{
+		Path:    path,
+		MaxJobs: kDefaultMaxJobHistory,
+		MaxAge:  kDefaultMaxJobHistoryAge}
+	if config != nil && config.MaxJobs != 0 {
+		h.MaxJobs = config.MaxJobs
+	}
+	if config != nil && config.MaxAgeDays != 0 {
+		h.MaxAge = time.Duration(config.MaxAgeDays) * 24 * time.Hour
+	}
+
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := h.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if job.GetState().GetRunning() {
+			job.State.Running = false
+			job.State.EndTime = TimestampNow()
+			job.State.ReturnCode = -1
+			job.State.Cancelled = true
+			err = h.writeMetadata(job)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h, h.Prune()
+}
+
+func (h *JobHistory) metadataFile(id int32) string {
+	return filepath.Join(h.Path, fmt.Sprintf("%d.json", id))
+}
+
+func (h *JobHistory) logFile(id int32) string {
+	return filepath.Join(h.Path, fmt.Sprintf("%d.log", id))
+}
+
+func (h *JobHistory) writeMetadata(job *BuilderJob) error {
+	var buffer bytes.Buffer
+	err := kJsonMarshaler.Marshal(&buffer, job)
+	if err != nil {
+		return err
+	}
+	temp_file := h.metadataFile(job.Id) + ".tmp"
+	err = ioutil.WriteFile(temp_file, buffer.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp_file, h.metadataFile(job.Id))
+}
+
+func (h *JobHistory) readMetadata(id int32) (*BuilderJob, error) {
+	data, err := ioutil.ReadFile(h.metadataFile(id))
+	if os.IsNotExist(err) {
+		return nil, NewJobNotFoundError("no job with ID %d in history", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job BuilderJob
+	err = kJsonUnmarshaler.Unmarshal(bytes.NewReader(data), &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// LastJobId returns the largest job ID in the history. New jobs should be
+// assigned IDs larger than this to avoid overwriting older jobs.
+func (h *JobHistory) LastJobId() int32 {
+	ids, _ := h.jobIds()
+	if len(ids) == 0 {
+		return 0
+	}
+	return ids[len(ids)-1]
+}
+
+// jobIds returns the IDs of the jobs in the history in ascending order.
+func (h *JobHistory) jobIds() ([]int32, error) {
+	files, err := ioutil.ReadDir(h.Path)
+	if err != nil {
+		return nil, err
+	}
+	ids := []int32{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(file.Name(), ".json"), 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, int32(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// ListJobs returns the jobs in the history ordered by ID.
+func (h *JobHistory) ListJobs() ([]*BuilderJob, error) {
+	ids, err := h.jobIds()
+	if err != nil {
+		return nil, err
+	}
+	jobs := []*BuilderJob{}
+	for _, id := range ids {
+		job, err := h.readMetadata(id)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Prune removes jobs that are older than MaxAge as well as the oldest jobs in
+// excess of MaxJobs.
+func (h *JobHistory) Prune() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	jobs, err := h.ListJobs()
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-h.MaxAge)
+	for index, job := range jobs {
+		if job.GetState().GetRunning() {
+			continue
+		}
+		if len(jobs)-index <= h.MaxJobs && TimeFromTimestamp(job.GetState().GetStartTime()).After(cutoff) {
+			continue
+		}
+		os.Remove(h.logFile(job.Id))
+		os.Remove(h.metadataFile(job.Id))
+	}
+	return nil
+}
+
+// ReadJobLog sends all the recorded events for the job identified by |id| to
+// |s|.
+func (h *JobHistory) ReadJobLog(id int32, s JobEventSender) error {
+	_, err := h.readMetadata(id)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(h.logFile(id))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			break
+		}
+		var je JobEvent
+		if kJsonUnmarshaler.Unmarshal(bytes.NewReader(line), &je) != nil {
+			continue
+		}
+		err = s.Send(&je)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewJobLog creates the on-disk record for |job|. Events for the job should
+// be sent to the returned JobLog which must be closed when the job completes.
+func (h *JobHistory) NewJobLog(job *BuilderJob) (*JobLog, error) {
+	err := h.writeMetadata(job)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(h.logFile(job.Id), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &JobLog{history: h, file: file}, nil
+}
+
+type JobLog struct {
+	history *JobHistory
+	file    *os.File
+	mutex   sync.Mutex
+}
+
+func (l *JobLog) Send(e *JobEvent) error {
+	if e.Time == nil {
+		e.Time = TimestampNow()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	line, err := kJsonMarshaler.MarshalToString(e)
+	if err != nil {
+		return err
+	}
+	_, err = l.file.WriteString(line + "\n")
+	return err
+}
+
+// Close records the final state of |job| and prunes the history.
+func (l *JobLog) Close(job *BuilderJob) error {
+	l.mutex.Lock()
+	l.file.Close()
+	l.file = nil
+	l.mutex.Unlock()
+
+	err := l.history.writeMetadata(job)
+	if err != nil {
+		return err
+	}
+	return l.history.Prune()
+}
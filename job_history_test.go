@@ -0,0 +1,78 @@
+package stonesthrow
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestJobHistory_RecordAndPrune(t *testing.T) {
+	path, err := ioutil.TempDir("", "job_history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	history, err := OpenJobHistory(path, &JobHistoryConfig{MaxJobs: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewJobRegistry()
+	r.SetHistory(history)
+	for i := 0; i < 3; i++ {
+		handle := r.AddJob(&BuilderJob{
+			Command: &ShellCommand{Command: []string{"ninja"}}})
+		handle.Send(outputEvent("output"))
+		handle.Finish(nil)
+	}
+
+	jobs, err := history.ListJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].Id != 2 || jobs[1].Id != 3 || jobs[1].State.Running {
+		t.Fatalf("unexpected jobs %v", jobs)
+	}
+
+	var events recordingJobEventSender
+	err = history.ReadJobLog(3, &events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.events) != 1 || events.events[0].GetCommandOutputEvent().GetOutput() != "output" {
+		t.Fatalf("unexpected events %v", events.events)
+	}
+
+	err = history.ReadJobLog(1, &events)
+	if !IsJobNotFoundError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Job IDs continue where the history left off.
+	r = NewJobRegistry()
+	r.SetHistory(history)
+	if id := r.AddJob(&BuilderJob{}).Id(); id != 4 {
+		t.Fatalf("unexpected job ID %d", id)
+	}
+
+	// Jobs that were running when the history was last opened are no longer
+	// considered to be running. They are recorded as having been cancelled.
+	history, err = OpenJobHistory(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, err = history.ListJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 || jobs[2].Id != 4 || jobs[2].State.Running {
+		t.Fatalf("unexpected jobs %v", jobs)
+	}
+	if state := jobs[2].State; !state.Cancelled || state.ReturnCode == 0 || state.EndTime == nil {
+		t.Fatalf("unexpected state for interrupted job %v", state)
+	}
+	if state := jobs[1].State; state.Cancelled || state.ReturnCode != 0 {
+		t.Fatalf("unexpected state for completed job %v", state)
+	}
+}
@@ -0,0 +1,319 @@
+package stonesthrow
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	kDefaultMaxCompletedJobs   = 50
+	kDefaultMaxCompletedJobAge = time.Hour
+	kJobTerminationGracePeriod = 10 * time.Second
+)
+
+type jobEntry struct {
+	job      *BuilderJob
+	process  *os.Process
+	events   *JobEventBuffer
+	log      *JobLog
+	children []*jobEntry
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// JobRegistry keeps track of the jobs that were started on behalf of clients.
+// Each job is assigned an ID that is unique for the lifetime of the server. A
+// job may have child jobs, e.g. the commands run by a script. Completed jobs
+// are retained for a while so that clients can look at recently finished
+// jobs. All methods are safe to call concurrently.
+type JobRegistry struct {
+	MaxCompletedJobs   int
+	MaxCompletedJobAge time.Duration
+
+	mutex     sync.Mutex
+	history   *JobHistory
+	lastId    int32
+	jobs      map[int32]*jobEntry
+	completed []int32
+}
+
+// JobHandle is used by whoever is running a job to report its progress to
+// the JobRegistry. Events for the job should be sent to the handle so that
+// they can be replayed by AttachJob.
+type JobHandle struct {
+	registry *JobRegistry
+	entry    *jobEntry
+	sender   JobEventSender
+}
+
+func NewJobRegistry() *JobRegistry {
+	return &JobRegistry{
+		MaxCompletedJobs:   kDefaultMaxCompletedJobs,
+		MaxCompletedJobAge: kDefaultMaxCompletedJobAge,
+		jobs:               make(map[int32]*jobEntry)}
+}
+
+// SetHistory causes the registry to record all subsequent jobs in |history|.
+func (r *JobRegistry) SetHistory(history *JobHistory) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.history = history
+	if last_id := history.LastJobId(); last_id > r.lastId {
+		r.lastId = last_id
+	}
+}
+
+func (r *JobRegistry) History() *JobHistory {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.history
+}
+
+// AddJob registers a new running job. The repository, platform, command and
+// parent are taken from |job|. The job's ID and run state are assigned by the
+// registry. Jobs should be added before their process is started so that the
+// ID is available right away.
+func (r *JobRegistry) AddJob(job *BuilderJob) *JobHandle {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastId += 1
+	job.Id = r.lastId
+	job.State = &RunState{
+		StartTime: TimestampNow(),
+		Running:   true}
+	entry := &jobEntry{
+		job:    job,
+		events: NewJobEventBuffer(kDefaultJobEventBufferSize),
+		done:   make(chan struct{})}
+	r.jobs[job.Id] = entry
+
+	if parent, ok := r.jobs[job.ParentId]; ok && parent.job.GetState().GetRunning() {
+		parent.children = append(parent.children, entry)
+	}
+
+	handle := &JobHandle{registry: r, entry: entry, sender: entry.events}
+	if r.history == nil {
+		return handle
+	}
+	// Failing to record the job in the history shouldn't prevent the job
+	// from running.
+	job_log, err := r.history.NewJobLog(proto.Clone(job).(*BuilderJob))
+	if err != nil {
+		return handle
+	}
+	entry.log = job_log
+	handle.sender = MultiJobEventSender{entry.events, entry.log}
+	return handle
+}
+
+// finish marks |entry| and any of its children that are still running as
+// completed. Must be called with |mutex| held. Returns the job logs that need
+// to be closed along with the final state of the corresponding jobs. These
+// should be closed after releasing |mutex|.
+func (r *JobRegistry) finish(entry *jobEntry, end_event *EndCommandEvent, logs map[*JobLog]*BuilderJob) {
+	if !entry.job.GetState().GetRunning() {
+		return
+	}
+	for _, child := range entry.children {
+		r.finish(child, nil, logs)
+	}
+	entry.children = nil
+
+	job := entry.job
+	job.State.Running = false
+	job.State.EndTime = TimestampNow()
+	if end_event != nil {
+		job.SystemTime = end_event.SystemTime
+		job.UserTime = end_event.UserTime
+		job.State.ReturnCode = end_event.ReturnCode
+		job.State.Signal = end_event.Signal
+		job.State.Cancelled = end_event.Cancelled
+	}
+	entry.events.Close()
+	close(entry.done)
+
+	r.completed = append(r.completed, job.Id)
+	if entry.log != nil {
+		logs[entry.log] = proto.Clone(job).(*BuilderJob)
+	}
+}
+
+// evict forgets completed jobs in excess of MaxCompletedJobs or older than
+// MaxCompletedJobAge. Must be called with |mutex| held.
+func (r *JobRegistry) evict() {
+	cutoff := time.Now().Add(-r.MaxCompletedJobAge)
+	for len(r.completed) > 0 {
+		entry, ok := r.jobs[r.completed[0]]
+		if ok && len(r.completed) <= r.MaxCompletedJobs &&
+			TimeFromTimestamp(entry.job.GetState().GetEndTime()).After(cutoff) {
+			break
+		}
+		delete(r.jobs, r.completed[0])
+		r.completed = r.completed[1:]
+	}
+}
+
+// Id returns the ID assigned to the job.
+func (h *JobHandle) Id() int32 {
+	return h.entry.job.Id
+}
+
+func (h *JobHandle) Send(e *JobEvent) error {
+	return h.sender.Send(e)
+}
+
+// SetProcess associates |process| with the job. Jobs without a process or a
+// cancel function can't be killed on their own.
+func (h *JobHandle) SetProcess(process *os.Process) {
+	h.registry.mutex.Lock()
+	defer h.registry.mutex.Unlock()
+	h.entry.process = process
+}
+
+// SetCancel causes KillJob to call |cancel| while the job doesn't have a
+// process, e.g. while the job is waiting for the JobScheduler.
+func (h *JobHandle) SetCancel(cancel context.CancelFunc) {
+	h.registry.mutex.Lock()
+	defer h.registry.mutex.Unlock()
+	h.entry.cancel = cancel
+}
+
+// Finish marks the job as completed. |end_event| describes how the job ended
+// and can be nil if the job couldn't be started. Children that are still
+// running are assumed to have ended along with the job.
+func (h *JobHandle) Finish(end_event *EndCommandEvent) {
+	r := h.registry
+	logs := make(map[*JobLog]*BuilderJob)
+
+	r.mutex.Lock()
+	r.finish(h.entry, end_event, logs)
+	r.evict()
+	r.mutex.Unlock()
+
+	for job_log, job := range logs {
+		job_log.Close(job)
+	}
+}
+
+// ListJobs returns snapshots of known jobs ordered by ID. Completed jobs are
+// only included if |include_completed| is true.
+func (r *JobRegistry) ListJobs(include_completed bool) *BuilderJobs {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.evict()
+
+	builder_jobs := &BuilderJobs{}
+	for _, entry := range r.jobs {
+		if !include_completed && !entry.job.GetState().GetRunning() {
+			continue
+		}
+		builder_jobs.Jobs = append(builder_jobs.Jobs, proto.Clone(entry.job).(*BuilderJob))
+	}
+	sort.Slice(builder_jobs.Jobs, func(i, j int) bool {
+		return builder_jobs.Jobs[i].Id < builder_jobs.Jobs[j].Id
+	})
+	return builder_jobs
+}
+
+// GetJob returns a snapshot of the job identified by |id|.
+func (r *JobRegistry) GetJob(id int32) (*BuilderJob, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, ok := r.jobs[id]
+	if !ok {
+		return nil, NewJobNotFoundError("no job with ID %d", id)
+	}
+	return proto.Clone(entry.job).(*BuilderJob), nil
+}
+
+// KillJob terminates the job identified by |id| along with all of its
+// descendants including child jobs. The job's process group is first sent a
+// SIGTERM. If the job hasn't exited after |grace_period|, then it gets a
+// SIGKILL. Jobs that don't have a process yet are cancelled instead. Returns
+// the final state of the job.
+func (r *JobRegistry) KillJob(ctx context.Context, id int32, grace_period time.Duration) (*BuilderJob, error) {
+	r.mutex.Lock()
+	entry, ok := r.jobs[id]
+	r.mutex.Unlock()
+	if !ok {
+		return nil, NewJobNotFoundError("no job with ID %d", id)
+	}
+
+	select {
+	case <-entry.done:
+		return nil, NewNothingToDoError("job %d is not running", id)
+	default:
+	}
+
+	r.mutex.Lock()
+	process := entry.process
+	cancel := entry.cancel
+	parent_id := entry.job.ParentId
+	r.mutex.Unlock()
+	switch {
+	case process != nil:
+		err := StopProcessGroup(process, grace_period, entry.done)
+		if err != nil {
+			return nil, err
+		}
+
+	case cancel != nil:
+		cancel()
+
+	case parent_id != 0:
+		return nil, NewInvalidArgumentError("job %d is part of job %d and can't be killed on its own", id, parent_id)
+
+	default:
+		return nil, NewNothingToDoError("job %d hasn't started", id)
+	}
+
+	select {
+	case <-entry.done:
+		return r.GetJob(id)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type parentJobContextKey struct{}
+
+// WithParentJob returns a context for running the commands of the job
+// identified by |id|. Commands that are run with the context are registered
+// as child jobs of the latter.
+func WithParentJob(ctx context.Context, id int32) context.Context {
+	return context.WithValue(ctx, parentJobContextKey{}, id)
+}
+
+// ParentJobFromContext returns the ID of the job that commands run with |ctx|
+// belong to, or zero if there isn't one.
+func ParentJobFromContext(ctx context.Context) int32 {
+	id, _ := ctx.Value(parentJobContextKey{}).(int32)
+	return id
+}
+
+// AttachJob replays the retained events for the job identified by |id| and
+// then follows its output until the job completes or |ctx| is done.
+func (r *JobRegistry) AttachJob(ctx context.Context, id int32, s JobEventSender) error {
+	r.mutex.Lock()
+	entry, ok := r.jobs[id]
+	r.mutex.Unlock()
+	if !ok {
+		return NewJobNotFoundError("no job with ID %d", id)
+	}
+	return entry.events.Follow(ctx, s)
+}
+
+func RunTimeOfJob(job *BuilderJob) time.Duration {
+	start_time := TimeFromTimestamp(job.GetState().GetStartTime())
+	if job.GetState().GetRunning() || job.GetState().GetEndTime() == nil {
+		return time.Since(start_time)
+	}
+	return TimeFromTimestamp(job.GetState().GetEndTime()).Sub(start_time)
+}
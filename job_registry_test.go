@@ -0,0 +1,170 @@
+package stonesthrow
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestJobRegistry_ListJobs(t *testing.T) {
+	r := NewJobRegistry()
+	r.MaxCompletedJobs = 1
+
+	handles := []*JobHandle{}
+	for i := 0; i < 3; i++ {
+		handles = append(handles, r.AddJob(&BuilderJob{
+			Command:    &ShellCommand{Command: []string{"ninja"}},
+			Repository: "chrome",
+			Platform:   "linux"}))
+	}
+
+	jobs := r.ListJobs(false).GetJobs()
+	if len(jobs) != 3 {
+		t.Fatalf("expected 3 running jobs. got %d", len(jobs))
+	}
+	for i, job := range jobs {
+		if job.Id != int32(i+1) || handles[i].Id() != job.Id {
+			t.Fatalf("unexpected job ID %d at index %d", job.Id, i)
+		}
+		if !job.State.Running || job.Repository != "chrome" || job.Platform != "linux" {
+			t.Fatalf("unexpected job %#v", job)
+		}
+	}
+
+	handles[0].Finish(nil)
+	handles[1].Finish(&EndCommandEvent{ReturnCode: 3})
+
+	if len(r.ListJobs(false).GetJobs()) != 1 {
+		t.Fatal("expected one running job")
+	}
+
+	// Only the most recently completed job should be retained.
+	jobs = r.ListJobs(true).GetJobs()
+	if len(jobs) != 2 || jobs[0].Id != 2 || jobs[0].State.Running ||
+		jobs[0].State.EndTime == nil || jobs[0].State.ReturnCode != 3 {
+		t.Fatalf("unexpected jobs %v", jobs)
+	}
+
+	// Completed jobs are also evicted once they are too old.
+	r.MaxCompletedJobAge = 0
+	if len(r.ListJobs(true).GetJobs()) != 1 {
+		t.Fatal("expected old jobs to be evicted")
+	}
+}
+
+func TestJobRegistry_ChildJobs(t *testing.T) {
+	r := NewJobRegistry()
+
+	parent := r.AddJob(&BuilderJob{Command: &ShellCommand{Command: []string{"cr.py"}}})
+	child := r.AddJob(&BuilderJob{
+		Command:  &ShellCommand{Command: []string{"ninja"}},
+		ParentId: parent.Id()})
+
+	job, err := r.GetJob(child.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ParentId != parent.Id() {
+		t.Fatalf("unexpected parent %d", job.ParentId)
+	}
+
+	_, err = r.KillJob(context.Background(), child.Id(), 0)
+	if !IsInvalidArgumentError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Finishing the parent also finishes the child.
+	parent.Finish(&EndCommandEvent{ReturnCode: 1})
+	if len(r.ListJobs(false).GetJobs()) != 0 {
+		t.Fatal("expected no running jobs")
+	}
+	job, err = r.GetJob(child.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State.Running || job.State.EndTime == nil {
+		t.Fatalf("child job is still running: %v", job)
+	}
+}
+
+func TestJobRegistry_KillJob(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	r := NewJobRegistry()
+	// The child ignores SIGTERM, so the registry should fall back to SIGKILL
+	// after the grace period.
+	cmd := exec.Command("sh", "-c", "trap '' TERM; echo ready; sleep 30 & wait")
+	setNewProcessGroup(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bufio.NewReader(stdout).ReadString('\n')
+	handle := r.AddJob(&BuilderJob{Command: &ShellCommand{Command: cmd.Args}})
+	handle.SetProcess(cmd.Process)
+	go func() {
+		cmd.Wait()
+		handle.Finish(NewEndCommandEventFromProcessState(cmd.ProcessState))
+	}()
+
+	jobs := r.ListJobs(false).GetJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected one running job. got %d", len(jobs))
+	}
+
+	job, err := r.KillJob(context.Background(), jobs[0].Id, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State.Running {
+		t.Fatal("job is still running")
+	}
+
+	_, err = r.KillJob(context.Background(), jobs[0].Id, 0)
+	if !IsNothingToDoError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, err = r.KillJob(context.Background(), 100, 0)
+	if !IsJobNotFoundError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestJobRegistry_KillQueuedJob(t *testing.T) {
+	r := NewJobRegistry()
+	s := NewJobScheduler(1)
+	release, err := s.Acquire(context.Background(), "linux", 0, NilJobEventSender{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// The job waits for the scheduler and hasn't got a process.
+	handle := r.AddJob(&BuilderJob{Command: &ShellCommand{Command: []string{"ninja"}}})
+	ctx, cancel := context.WithCancel(context.Background())
+	handle.SetCancel(cancel)
+	go func() {
+		_, err := s.Acquire(ctx, "linux", 0, NilJobEventSender{})
+		handle.Finish(&EndCommandEvent{ReturnCode: -1, Cancelled: err != nil})
+	}()
+	waitForQueueLength(t, s, 1)
+
+	job, err := r.KillJob(context.Background(), handle.Id(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State.Running || !job.State.Cancelled {
+		t.Fatalf("unexpected state %v", job.State)
+	}
+	waitForQueueLength(t, s, 0)
+}
@@ -0,0 +1,143 @@
+package stonesthrow
+
+import (
+	"context"
+	"sync"
+)
+
+// kDefaultMaxHeavyJobs is the number of heavy jobs that can run at once on a
+// host that limits the jobs of each build via max_build_jobs.
+const kDefaultMaxHeavyJobs = 1
+
+// JobScheduler limits the number of heavy jobs like builds that can run
+// concurrently on a host as well as on each platform. A limit of zero means
+// no limit. Jobs that can't start right away wait in a queue. A queued job can
+// start ahead of jobs that are earlier in the queue only if the latter are
+// blocked by their platform limit.
+type JobScheduler struct {
+	MaxHostJobs int
+
+	mutex           sync.Mutex
+	running         int
+	runningPlatform map[string]int
+	queue           []*scheduledJob
+	changed         chan struct{}
+}
+
+type scheduledJob struct {
+	platform        string
+	maxPlatformJobs int
+	ready           chan struct{}
+}
+
+func NewJobScheduler(max_host_jobs int) *JobScheduler {
+	return &JobScheduler{
+		MaxHostJobs:     max_host_jobs,
+		runningPlatform: make(map[string]int),
+		changed:         make(chan struct{})}
+}
+
+func (s *JobScheduler) canRun(j *scheduledJob) bool {
+	if s.MaxHostJobs > 0 && s.running >= s.MaxHostJobs {
+		return false
+	}
+	return j.maxPlatformJobs <= 0 || s.runningPlatform[j.platform] < j.maxPlatformJobs
+}
+
+// dispatch starts all queued jobs that can run. Must be called with |mutex|
+// held.
+func (s *JobScheduler) dispatch() {
+	remaining := []*scheduledJob{}
+	for _, j := range s.queue {
+		if !s.canRun(j) {
+			remaining = append(remaining, j)
+			continue
+		}
+		s.running += 1
+		s.runningPlatform[j.platform] += 1
+		close(j.ready)
+	}
+	if len(remaining) != len(s.queue) {
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	s.queue = remaining
+}
+
+func (s *JobScheduler) positionOf(j *scheduledJob) int {
+	for index, queued := range s.queue {
+		if queued == j {
+			return index + 1
+		}
+	}
+	return 0
+}
+
+func (s *JobScheduler) release(platform string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.running -= 1
+	s.runningPlatform[platform] -= 1
+	s.dispatch()
+}
+
+func (s *JobScheduler) remove(j *scheduledJob) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	select {
+	case <-j.ready:
+		// Started after all. Give up the slot.
+		s.running -= 1
+		s.runningPlatform[j.platform] -= 1
+	default:
+		position := s.positionOf(j)
+		s.queue = append(s.queue[:position-1], s.queue[position:]...)
+	}
+	s.dispatch()
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Acquire waits until a heavy job for |platform| can run. At most
+// |max_platform_jobs| heavy jobs can run for a single platform, unless the
+// limit is zero. While waiting, the position of the job in the queue is
+// reported to |sender|. The returned function must be called once the job
+// is done.
+func (s *JobScheduler) Acquire(ctx context.Context, platform string, max_platform_jobs int, sender JobEventSender) (func(), error) {
+	j := &scheduledJob{
+		platform:        platform,
+		maxPlatformJobs: max_platform_jobs,
+		ready:           make(chan struct{})}
+	release := func() { s.release(platform) }
+
+	s.mutex.Lock()
+	s.queue = append(s.queue, j)
+	s.dispatch()
+	s.mutex.Unlock()
+
+	last_position := 0
+	for {
+		s.mutex.Lock()
+		position := s.positionOf(j)
+		changed := s.changed
+		s.mutex.Unlock()
+
+		if position != 0 && position != last_position {
+			SendLog(sender, LogEvent_INFO,
+				"waiting for a heavy job slot. position %d in queue.", position)
+			last_position = position
+		}
+
+		select {
+		case <-j.ready:
+			return release, nil
+
+		case <-ctx.Done():
+			s.remove(j)
+			return nil, ctx.Err()
+
+		case <-changed:
+		}
+	}
+}
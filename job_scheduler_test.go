@@ -0,0 +1,160 @@
+package stonesthrow
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func acquireInBackground(s *JobScheduler, platform string, max_platform_jobs int) (chan func(), chan error) {
+	released := make(chan func(), 1)
+	failed := make(chan error, 1)
+	go func() {
+		release, err := s.Acquire(context.Background(), platform, max_platform_jobs, NilJobEventSender{})
+		if err != nil {
+			failed <- err
+			return
+		}
+		released <- release
+	}()
+	return released, failed
+}
+
+func waitForQueueLength(t *testing.T, s *JobScheduler, length int) {
+	for i := 0; i < 100; i++ {
+		s.mutex.Lock()
+		current := len(s.queue)
+		s.mutex.Unlock()
+		if current == length {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("queue length didn't reach %d", length)
+}
+
+func TestJobScheduler_HostLimit(t *testing.T) {
+	s := NewJobScheduler(1)
+
+	release, err := s.Acquire(context.Background(), "linux", 0, NilJobEventSender{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := acquireInBackground(s, "android", 0)
+	waitForQueueLength(t, s, 1)
+
+	select {
+	case <-second:
+		t.Fatal("second job started while the host was at its limit")
+	default:
+	}
+
+	release()
+	select {
+	case release = <-second:
+		release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second job didn't start")
+	}
+}
+
+func TestJobScheduler_NoLimit(t *testing.T) {
+	s := NewJobScheduler(0)
+
+	// Without limits, jobs never wait.
+	releases := []func(){}
+	for i := 0; i < 3; i++ {
+		release, err := s.Acquire(context.Background(), "linux", 0, NilJobEventSender{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+	if s.running != 0 {
+		t.Fatalf("%d jobs still running", s.running)
+	}
+}
+
+func TestJobScheduler_PlatformLimit(t *testing.T) {
+	s := NewJobScheduler(2)
+
+	release_linux, err := s.Acquire(context.Background(), "linux", 1, NilJobEventSender{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second linux job is blocked by the platform limit. The android job
+	// that's queued after it can still start.
+	second_linux, _ := acquireInBackground(s, "linux", 1)
+	waitForQueueLength(t, s, 1)
+
+	release_android, err := s.Acquire(context.Background(), "android", 1, NilJobEventSender{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release_android()
+
+	select {
+	case <-second_linux:
+		t.Fatal("second linux job ignored the platform limit")
+	default:
+	}
+
+	release_linux()
+	select {
+	case release := <-second_linux:
+		release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second linux job didn't start")
+	}
+}
+
+func TestJobScheduler_Cancel(t *testing.T) {
+	s := NewJobScheduler(1)
+
+	release, err := s.Acquire(context.Background(), "linux", 0, NilJobEventSender{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	failed := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(ctx, "linux", 0, NilJobEventSender{})
+		failed <- err
+	}()
+	waitForQueueLength(t, s, 1)
+	cancel()
+
+	select {
+	case err = <-failed:
+		if err != context.Canceled {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled job is still waiting")
+	}
+	waitForQueueLength(t, s, 0)
+
+	release()
+	if s.running != 0 {
+		t.Fatalf("%d jobs still running", s.running)
+	}
+}
+
+func TestHostConfig_GetMaxHeavyJobs(t *testing.T) {
+	for _, c := range []struct {
+		max_build_jobs int
+		max_heavy_jobs int
+		expected       int
+	}{{0, 0, 0}, {500, 0, kDefaultMaxHeavyJobs}, {500, 3, 3}, {500, -1, 0}, {0, 2, 2}} {
+		host := &HostConfig{MaxBuildJobs: c.max_build_jobs, MaxHeavyJobs: c.max_heavy_jobs}
+		if max_jobs := host.GetMaxHeavyJobs(); max_jobs != c.expected {
+			t.Fatalf("unexpected limit %d for %+v", max_jobs, c)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package stonesthrow
+
+import (
+	"context"
+	"sync"
+)
+
+const kDefaultJobEventBufferSize = 4096
+
+// JobEventBuffer retains the most recent JobEvents for a job. Any number of
+// readers can replay the retained events and then follow new events as they
+// arrive. Writers are never blocked by slow readers. Instead a reader that
+// falls behind skips over the events that it missed.
+type JobEventBuffer struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	events []*JobEvent
+	next   int64
+	closed bool
+}
+
+func NewJobEventBuffer(size int) *JobEventBuffer {
+	b := &JobEventBuffer{events: make([]*JobEvent, size)}
+	b.cond = sync.NewCond(&b.mutex)
+	return b
+}
+
+func (b *JobEventBuffer) Send(e *JobEvent) error {
+	if e.Time == nil {
+		e.Time = TimestampNow()
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.events[b.next%int64(len(b.events))] = e
+	b.next += 1
+	b.cond.Broadcast()
+	return nil
+}
+
+// Close marks the end of the event stream. Readers return once they have
+// caught up.
+func (b *JobEventBuffer) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.closed = true
+	b.cond.Broadcast()
+}
+
+func (b *JobEventBuffer) oldest() int64 {
+	if b.next < int64(len(b.events)) {
+		return 0
+	}
+	return b.next - int64(len(b.events))
+}
+
+// Follow sends all retained events to |s| followed by any new events until
+// the buffer is closed or |ctx| is done.
+func (b *JobEventBuffer) Follow(ctx context.Context, s JobEventSender) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.mutex.Lock()
+			b.cond.Broadcast()
+			b.mutex.Unlock()
+		case <-stop:
+		}
+	}()
+
+	var position int64
+	for {
+		b.mutex.Lock()
+		for position == b.next && !b.closed && ctx.Err() == nil {
+			b.cond.Wait()
+		}
+		if ctx.Err() != nil {
+			b.mutex.Unlock()
+			return ctx.Err()
+		}
+
+		var skipped int64
+		if position < b.oldest() {
+			skipped = b.oldest() - position
+			position = b.oldest()
+		}
+		pending := []*JobEvent{}
+		for ; position < b.next; position++ {
+			pending = append(pending, b.events[position%int64(len(b.events))])
+		}
+		closed := b.closed
+		b.mutex.Unlock()
+
+		if skipped != 0 {
+			err := SendLog(s, LogEvent_INFO, "%d earlier events are no longer available", skipped)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, e := range pending {
+			err := s.Send(e)
+			if err != nil {
+				return err
+			}
+		}
+
+		if closed && len(pending) == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package stonesthrow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type recordingJobEventSender struct {
+	events []*JobEvent
+}
+
+func (r *recordingJobEventSender) Send(e *JobEvent) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func outputEvent(s string) *JobEvent {
+	return &JobEvent{CommandOutputEvent: &CommandOutputEvent{Output: s}}
+}
+
+func TestJobEventBuffer_Follow(t *testing.T) {
+	b := NewJobEventBuffer(4)
+	b.Send(outputEvent("a"))
+	b.Send(outputEvent("b"))
+
+	var r recordingJobEventSender
+	done := make(chan error)
+	go func() {
+		done <- b.Follow(context.Background(), &r)
+	}()
+
+	b.Send(outputEvent("c"))
+	b.Close()
+	b.Send(outputEvent("not sent"))
+
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := ""
+	for _, e := range r.events {
+		output += e.GetCommandOutputEvent().GetOutput()
+	}
+	if output != "abc" {
+		t.Fatalf("unexpected output %q", output)
+	}
+}
+
+func TestJobEventBuffer_Overflow(t *testing.T) {
+	b := NewJobEventBuffer(4)
+	for i := 0; i < 10; i++ {
+		b.Send(outputEvent(fmt.Sprintf("%d", i)))
+	}
+	b.Close()
+
+	var r recordingJobEventSender
+	err := b.Follow(context.Background(), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.events) != 5 || r.events[0].GetLogEvent() == nil {
+		t.Fatalf("expected a log event followed by 4 events. got %v", r.events)
+	}
+	if r.events[1].GetCommandOutputEvent().GetOutput() != "6" {
+		t.Fatalf("unexpected first event %v", r.events[1])
+	}
+}
+
+func TestJobEventBuffer_Cancel(t *testing.T) {
+	b := NewJobEventBuffer(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		var r recordingJobEventSender
+		done <- b.Follow(ctx, &r)
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Follow didn't return after the context was cancelled")
+	}
+}
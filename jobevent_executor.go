@@ -1,26 +1,40 @@
 package stonesthrow
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 )
 
 type JobEventExecutor struct {
 	host         string
+	repository   string
+	platform     string
 	workdir      string
 	processAdder ProcessAdder
 	sender       JobEventSender
+	stdin        io.Reader
+	terminal     *WindowSize
+	env          map[string]string
+	timeout      time.Duration
+}
+
+type windowSizeSource interface {
+	WindowSizeChanges() <-chan *WindowSize
 }
 
 func NewJobEventExecutor(
 	host string,
+	repository string,
+	platform string,
 	workdir string,
 	processAdder ProcessAdder,
 	sender JobEventSender) *JobEventExecutor {
@@ -29,11 +43,80 @@ func NewJobEventExecutor(
 	}
 	return &JobEventExecutor{
 		host:         host,
+		repository:   repository,
+		platform:     platform,
 		workdir:      workdir,
 		processAdder: processAdder,
 		sender:       sender}
 }
 
+// childJobSender registers the commands that a tracked process reports via
+// BeginCommandEvent and EndCommandEvent as child jobs of |parent|. Events that
+// arrive while a child job is running are recorded for both jobs.
+type childJobSender struct {
+	processAdder ProcessAdder
+	parent       *JobHandle
+	repository   string
+	platform     string
+	sender       JobEventSender
+
+	mutex sync.Mutex
+	child *JobHandle
+}
+
+func (c *childJobSender) Send(e *JobEvent) error {
+	c.mutex.Lock()
+	if e.BeginCommandEvent != nil {
+		if c.child != nil {
+			c.child.Finish(nil)
+		}
+		c.child = c.processAdder.AddJob(&BuilderJob{
+			Command:    e.BeginCommandEvent.Command,
+			Repository: c.repository,
+			Platform:   c.platform,
+			ParentId:   c.parent.Id()})
+		e.BeginCommandEvent.JobId = c.child.Id()
+	}
+	child := c.child
+	if e.EndCommandEvent != nil {
+		c.child = nil
+	}
+	c.mutex.Unlock()
+
+	if child != nil {
+		child.Send(e)
+		if e.EndCommandEvent != nil {
+			child.Finish(e.EndCommandEvent)
+		}
+	}
+	return c.sender.Send(e)
+}
+
+// SetStdin causes commands to read their standard input from |stdin|.
+// Otherwise commands run with no stdin.
+func (e *JobEventExecutor) SetStdin(stdin io.Reader) {
+	e.stdin = stdin
+}
+
+// SetTerminal causes passthrough commands to run in a pseudo-terminal of size
+// |size|. If the stdin of the executor also reports window size changes, then
+// the terminal is resized accordingly.
+func (e *JobEventExecutor) SetTerminal(size *WindowSize) {
+	e.terminal = size
+}
+
+// SetEnvironment sets additional environment variables for commands. Commands
+// otherwise inherit the environment of the host.
+func (e *JobEventExecutor) SetEnvironment(env map[string]string) {
+	e.env = env
+}
+
+// SetDefaultTimeout causes commands to be stopped after |timeout| unless the
+// context they run in has a deadline.
+func (e *JobEventExecutor) SetDefaultTimeout(timeout time.Duration) {
+	e.timeout = timeout
+}
+
 func (e JobEventExecutor) handleControlSequence(text string) error {
 	index := strings.Index(text, ":")
 	if index < 0 {
@@ -65,26 +148,109 @@ func (e JobEventExecutor) handleControlSequence(text string) error {
 }
 
 func (e JobEventExecutor) stream(stream CommandOutputEvent_Stream, reader io.Reader) {
-	scanner := bufio.NewScanner(reader)
-	for scanner.Scan() {
-		text := scanner.Text()
-
-		if strings.HasPrefix(text, "@@@") && strings.HasSuffix(text, "@@@") {
-			err := e.handleControlSequence(strings.Trim(text, "@"))
-			if err != nil {
-				e.sender.Send(&JobEvent{
-					LogEvent: &LogEvent{
-						Host:     e.host,
-						Msg:      fmt.Sprintf("couldn't parse control sequence", err.Error()),
-						Severity: LogEvent_ERROR}})
-			}
-			continue
+	writer := newCommandOutputWriter(stream, e.sender, func(text string) {
+		err := e.handleControlSequence(text)
+		if err != nil {
+			e.sender.Send(&JobEvent{
+				LogEvent: &LogEvent{
+					Host:     e.host,
+					Msg:      fmt.Sprintf("couldn't parse control sequence: %s", err.Error()),
+					Severity: LogEvent_ERROR}})
 		}
+	})
+	io.CopyBuffer(writer, reader, make([]byte, kCommandOutputChunkSize))
+	writer.Close()
+}
 
+// stopOnCancel waits for |ctx| to be cancelled and stops |process| unless
+// |exited| is signalled first. exec.CommandContext isn't used for this since
+// it only kills the immediate child. For commands like the script host, the
+// child is usually a wrapper for the process that's doing the actual work.
+//
+// The context of an RPC is also cancelled when the connection to the client
+// is lost. Hence only detached jobs, whose context isn't tied to the RPC,
+// survive a disconnect.
+func (e JobEventExecutor) stopOnCancel(ctx context.Context, process *os.Process, exited <-chan struct{}) {
+	select {
+	case <-exited:
+		return
+	case <-ctx.Done():
+	}
+
+	if e.processAdder == nil {
+		process.Kill()
+		return
+	}
+
+	err := StopProcessGroup(process, kJobTerminationGracePeriod, exited)
+	if err != nil {
 		e.sender.Send(&JobEvent{
-			CommandOutputEvent: &CommandOutputEvent{
-				Stream: stream,
-				Output: scanner.Text()}})
+			LogEvent: &LogEvent{
+				Host:     e.host,
+				Msg:      fmt.Sprintf("Can't stop process %d: %s", process.Pid, err.Error()),
+				Severity: LogEvent_ERROR}})
+	}
+}
+
+// trackJob registers the command described by |begin_event| with the
+// ProcessAdder, if there is one. Events for tracked jobs are also recorded by
+// the ProcessAdder so that they can be replayed later. Commands reported by
+// the process itself become child jobs, as does the command itself if |ctx|
+// belongs to a job. Returns the sender for the events generated by the
+// executor itself.
+func (e *JobEventExecutor) trackJob(ctx context.Context, begin_event *BeginCommandEvent) (JobEventSender, *JobHandle) {
+	if e.processAdder == nil {
+		return e.sender, nil
+	}
+	handle := e.processAdder.AddJob(&BuilderJob{
+		Command:    begin_event.Command,
+		Repository: e.repository,
+		Platform:   e.platform,
+		ParentId:   ParentJobFromContext(ctx)})
+	begin_event.JobId = handle.Id()
+	sender := MultiJobEventSender{handle, e.sender}
+	e.sender = &childJobSender{
+		processAdder: e.processAdder,
+		parent:       handle,
+		repository:   e.repository,
+		platform:     e.platform,
+		sender:       sender}
+	return sender, handle
+}
+
+// endCommand reports the outcome of |cmd| once it has been waited on. |err| is
+// the error returned by Wait().
+func (e JobEventExecutor) endCommand(ctx context.Context, cmd *exec.Cmd, sender JobEventSender, err error) (*EndCommandEvent, error) {
+	// The command couldn't be waited on.
+	if cmd.ProcessState == nil {
+		return nil, err
+	}
+
+	end_event := NewEndCommandEventFromProcessState(cmd.ProcessState)
+	end_event.Cancelled = ctx.Err() != nil
+	end_event.TimedOut = ctx.Err() == context.DeadlineExceeded
+	sender.Send(&JobEvent{EndCommandEvent: end_event})
+
+	switch {
+	case end_event.TimedOut:
+		return end_event, NewTimedOutError("%s timed out", cmd.Args[0])
+
+	case end_event.Cancelled:
+		return end_event, ctx.Err()
+
+	case end_event.Signal != 0:
+		return end_event, NewExternalCommandFailedError("%s terminated by signal: %s", cmd.Args[0], end_event.SignalName)
+
+	case end_event.ReturnCode != 0:
+		return end_event, NewExternalCommandFailedError("%s exited with return code %d", cmd.Args[0], end_event.ReturnCode)
+	}
+	return end_event, err
+}
+
+// closeStdin stops the delivery of input once the command is done.
+func (e JobEventExecutor) closeStdin() {
+	if closer, ok := e.stdin.(io.Closer); ok {
+		closer.Close()
 	}
 }
 
@@ -94,23 +260,29 @@ func (e JobEventExecutor) execute(ctx context.Context, workdir string, captureSt
 		return "", NewEmptyCommandError("")
 	}
 
-	err := e.sender.Send(&JobEvent{
-		BeginCommandEvent: &BeginCommandEvent{
-			Command: &ShellCommand{
-				Command:   command,
-				Directory: workdir,
-				Host:      e.host}}})
+	env, err := environmentForCommand(e.env)
 	if err != nil {
 		return "", err
 	}
 
-	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
-	cmd.Env = nil // inherit
-	cmd.Dir = workdir
+	ctx, cancel := withCommandDeadline(ctx, e.timeout)
+	defer cancel()
 
-	quitter := make(chan int)
+	// Output from stdout and stderr is sent from separate goroutines.
+	e.sender = NewSynchronizedJobEventSender(e.sender)
+
+	if e.terminal != nil && !captureStdout {
+		return "", e.executeInTerminal(ctx, workdir, env, command...)
+	}
 
-	stderrPipe, err := cmd.StderrPipe()
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Dir = workdir
+
+	// The pipes aren't created with exec.Cmd.StdoutPipe() and friends since
+	// Wait() closes those. Here the output is still read while the command is
+	// being waited on.
+	stderrReader, stderrWriter, err := os.Pipe()
 	if err != nil {
 		e.sender.Send(&JobEvent{
 			LogEvent: &LogEvent{
@@ -119,72 +291,208 @@ func (e JobEventExecutor) execute(ctx context.Context, workdir string, captureSt
 				Severity: LogEvent_ERROR}})
 		return "", err
 	}
-	go func() {
-		e.stream(CommandOutputEvent_ERR, stderrPipe)
-		quitter <- 2
-	}()
+	defer stderrReader.Close()
+	defer stderrWriter.Close()
+	cmd.Stderr = stderrWriter
 
-	var output bytes.Buffer
-	var stdoutPipe io.ReadCloser
-	if captureStdout {
-		cmd.Stdout = &output
-	} else {
-		stdoutPipe, err = cmd.StdoutPipe()
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		e.sender.Send(&JobEvent{
+			LogEvent: &LogEvent{
+				Host:     e.host,
+				Msg:      fmt.Sprintf("Can't open stdout pipe: %s", err.Error()),
+				Severity: LogEvent_ERROR}})
+		return "", err
+	}
+	defer stdoutReader.Close()
+	defer stdoutWriter.Close()
+	cmd.Stdout = stdoutWriter
+
+	// exec.Cmd.Stdin isn't used since Wait() would then block until |stdin|
+	// reaches EOF, even if the process has exited. The pipe is closed by
+	// Wait() which causes the copy to stop.
+	var stdinPipe io.WriteCloser
+	if e.stdin != nil {
+		stdinPipe, err = cmd.StdinPipe()
 		if err != nil {
-			e.sender.Send(&JobEvent{
-				LogEvent: &LogEvent{
-					Host:     e.host,
-					Msg:      fmt.Sprintf("Can't open stdout pipe: %s", err.Error()),
-					Severity: LogEvent_ERROR}})
 			return "", err
 		}
-		go func() {
-			e.stream(CommandOutputEvent_OUT, stdoutPipe)
-			quitter <- 1
+		defer e.closeStdin()
+	}
+
+	// Jobs that are tracked by a ProcessAdder run in their own process group
+	// so that they can be killed along with any of their descendants.
+	if e.processAdder != nil {
+		setNewProcessGroup(cmd)
+	}
+
+	begin_event := &BeginCommandEvent{
+		Command: &ShellCommand{
+			Command:   command,
+			Directory: workdir,
+			Host:      e.host,
+			Env:       redactEnvironment(e.env)}}
+
+	// Note that |e| is a copy, so replacing the sender only affects this
+	// command.
+	sender, handle := e.trackJob(ctx, begin_event)
+	var end_event *EndCommandEvent
+	if handle != nil {
+		defer func() {
+			handle.Finish(end_event)
 		}()
 	}
 
 	err = cmd.Start()
-	if err == nil {
-		if e.processAdder != nil {
-			e.processAdder.AddProcess(command, cmd.Process)
-		}
-		err = cmd.Wait()
+	sender.Send(&JobEvent{BeginCommandEvent: begin_event})
+	if err != nil {
+		// Same as os.ProcessState.ExitCode() for a process that didn't exit.
+		end_event = &EndCommandEvent{ReturnCode: -1}
+		return "", err
 	}
+	if handle != nil {
+		handle.SetProcess(cmd.Process)
+	}
+	if stdinPipe != nil {
+		go func() {
+			io.Copy(stdinPipe, e.stdin)
+			stdinPipe.Close()
+		}()
+	}
+
+	// The write ends now belong to the command.
+	stderrWriter.Close()
+	stdoutWriter.Close()
+
+	exited := make(chan struct{})
+	go e.stopOnCancel(ctx, cmd.Process, exited)
+
+	var output bytes.Buffer
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		e.stream(CommandOutputEvent_ERR, stderrReader)
+		readers.Done()
+	}()
+	go func() {
+		if captureStdout {
+			io.Copy(&output, stdoutReader)
+		} else {
+			e.stream(CommandOutputEvent_OUT, stdoutReader)
+		}
+		readers.Done()
+	}()
+	read_all := make(chan struct{})
+	go func() {
+		readers.Wait()
+		close(read_all)
+	}()
 
-	stderrPipe.Close()
-	<-quitter
+	err = cmd.Wait()
+	close(exited)
+
+	// Processes that the command left behind, e.g. daemons, may still hold on
+	// to the pipes. Whatever they write after the command has exited is only
+	// read for a little while. Otherwise the job wouldn't finish until they
+	// exit.
+	select {
+	case <-read_all:
+	case <-time.After(kCommandOutputDrainTimeout):
+		stderrReader.Close()
+		stdoutReader.Close()
+		<-read_all
+	}
 
 	var outputString string
 	if captureStdout {
 		e.stream(CommandOutputEvent_OUT, bytes.NewReader(output.Bytes()))
 		outputString = strings.TrimSpace(output.String())
-	} else {
-		stdoutPipe.Close()
-		<-quitter
 	}
 
+	end_event, err = e.endCommand(ctx, cmd, sender, err)
+	return outputString, err
+}
+
+// executeInTerminal runs |command| in a new pseudo-terminal. Output is passed
+// along as raw bytes and control sequences aren't interpreted.
+func (e JobEventExecutor) executeInTerminal(ctx context.Context, workdir string, env []string, command ...string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Dir = workdir
+	if e.stdin != nil {
+		defer e.closeStdin()
+	}
+
+	begin_event := &BeginCommandEvent{
+		Command: &ShellCommand{
+			Command:   command,
+			Directory: workdir,
+			Host:      e.host,
+			Env:       redactEnvironment(e.env),
+			Tty:       true}}
+
+	sender, handle := e.trackJob(ctx, begin_event)
+	var end_event *EndCommandEvent
+	if handle != nil {
+		defer func() {
+			handle.Finish(end_event)
+		}()
+	}
+
+	// The command becomes a session leader. Hence it doesn't need a separate
+	// process group.
+	tty, err := startInTerminal(cmd, e.terminal)
+	sender.Send(&JobEvent{BeginCommandEvent: begin_event})
 	if err != nil {
-		return outputString, err
+		end_event = &EndCommandEvent{ReturnCode: -1}
+		return err
 	}
-	if e.processAdder != nil {
-		e.processAdder.RemoveProcess(cmd.Process, cmd.ProcessState)
+	defer tty.Close()
+	if handle != nil {
+		handle.SetProcess(cmd.Process)
 	}
-	var fake_return_code int32
-	if !cmd.ProcessState.Success() {
-		fake_return_code = 1
+
+	exited := make(chan struct{})
+	go e.stopOnCancel(ctx, cmd.Process, exited)
+	if e.stdin != nil {
+		go io.Copy(tty, e.stdin)
+	}
+	if source, ok := e.stdin.(windowSizeSource); ok {
+		go func() {
+			for {
+				select {
+				case size := <-source.WindowSizeChanges():
+					resizeTerminal(tty, size)
+				case <-exited:
+					return
+				}
+			}
+		}()
 	}
-	e.sender.Send(&JobEvent{
-		EndCommandEvent: &EndCommandEvent{
-			ReturnCode: fake_return_code,
-			SystemTime: NewDurationFromDuration(cmd.ProcessState.SystemTime()),
-			UserTime:   NewDurationFromDuration(cmd.ProcessState.UserTime())}})
 
-	if cmd.ProcessState.Success() {
-		return outputString, nil
+	// Reading from the terminal fails once all processes attached to it have
+	// exited.
+	buffer := make([]byte, kCommandOutputChunkSize)
+	for {
+		count, err := tty.Read(buffer)
+		if count > 0 {
+			data := make([]byte, count)
+			copy(data, buffer[:count])
+			e.sender.Send(&JobEvent{
+				CommandOutputEvent: &CommandOutputEvent{
+					Stream: CommandOutputEvent_OUT,
+					Data:   data}})
+		}
+		if err != nil {
+			break
+		}
 	}
 
-	return outputString, NewExternalCommandFailedError("")
+	err = cmd.Wait()
+	close(exited)
+
+	end_event, err = e.endCommand(ctx, cmd, sender, err)
+	return err
 }
 
 func (e JobEventExecutor) ExecuteInWorkDirNoStream(workdir string, ctx context.Context, command ...string) (string, error) {
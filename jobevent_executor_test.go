@@ -0,0 +1,53 @@
+package stonesthrow
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobEventExecutor_BackgroundProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	// The background process inherits stdout and stderr, but the command is
+	// done once the shell exits.
+	for _, capture := range []bool{false, true} {
+		var events recordingJobEventSender
+		executor := NewJobEventExecutor("host", "", "", "", nil, &events)
+		start := time.Now()
+		var output string
+		var err error
+		if capture {
+			output, err = executor.Execute(context.Background(), "sh", "-c", "sleep 30 & echo $!")
+		} else {
+			err = executor.ExecutePassthrough(context.Background(), "sh", "-c", "sleep 30 & echo $!")
+			for _, e := range events.events {
+				output += string(e.GetCommandOutputEvent().GetData())
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pid, err := strconv.Atoi(strings.TrimSpace(output))
+		if err != nil {
+			t.Fatalf("unexpected output %q", output)
+		}
+		if process, err := os.FindProcess(pid); err == nil {
+			defer process.Kill()
+		}
+
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Fatalf("command took %v", elapsed)
+		}
+		if events.events[len(events.events)-1].GetEndCommandEvent() == nil {
+			t.Fatalf("expected an EndCommandEvent. got %v", events.events)
+		}
+	}
+}
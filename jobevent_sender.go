@@ -2,6 +2,7 @@ package stonesthrow
 
 import (
 	"fmt"
+	"sync"
 )
 
 type JobEventSender interface {
@@ -32,6 +33,38 @@ func (t TimestampingJobEventSender) Send(e *JobEvent) error {
 	return t.sender.Send(e)
 }
 
+// MultiJobEventSender sends each event to all of its senders. Returns the first
+// error that was encountered, if any.
+type MultiJobEventSender []JobEventSender
+
+func (m MultiJobEventSender) Send(e *JobEvent) error {
+	var first_error error
+	for _, sender := range m {
+		err := sender.Send(e)
+		if err != nil && first_error == nil {
+			first_error = err
+		}
+	}
+	return first_error
+}
+
+// SynchronizedJobEventSender serializes calls to Send so that a single sender
+// can be shared between multiple goroutines.
+type SynchronizedJobEventSender struct {
+	sender JobEventSender
+	mutex  sync.Mutex
+}
+
+func NewSynchronizedJobEventSender(sender JobEventSender) *SynchronizedJobEventSender {
+	return &SynchronizedJobEventSender{sender: sender}
+}
+
+func (s *SynchronizedJobEventSender) Send(e *JobEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sender.Send(e)
+}
+
 func DrainJobEventPipe(receiver JobEventReceiver, sender JobEventSender) error {
 	for {
 		je, err := receiver.Recv()
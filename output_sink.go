@@ -8,6 +8,8 @@ type OutputSink interface {
 	OnBuilderJobs(*BuilderJobs) error
 	OnJobEvent(*JobEvent) error
 	OnPong(*PingResult) error
+	OnHello(*HelloResult) error
+	OnAuditRecords(*AuditRecords) error
 
 	Drain(JobEventReceiver) error
 	DrainReader(CommandOutputEvent, io.Reader) error
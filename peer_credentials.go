@@ -0,0 +1,89 @@
+package stonesthrow
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"net"
+	"os"
+)
+
+// PeerCredentials identify the process at the other end of a unix socket.
+type PeerCredentials struct {
+	Uid uint32
+	Gid uint32
+	Pid int32
+}
+
+func (p PeerCredentials) AuthType() string {
+	return "peercred"
+}
+
+// authorizePeer returns an error unless |peer| is run by the same user as the
+// server.
+func authorizePeer(peer *PeerCredentials) error {
+	if int(peer.Uid) != os.Getuid() {
+		return NewConnectionError("unix socket peer with uid %d (pid %d) isn't allowed", peer.Uid, peer.Pid)
+	}
+	return nil
+}
+
+// localTransportCredentials secures connections using |tls| except for those
+// that arrive via unix sockets. The latter are authorized based on the
+// credentials of the peer instead.
+type localTransportCredentials struct {
+	tls credentials.TransportCredentials
+}
+
+// NewLocalTransportCredentials returns the credentials for a server that
+// accepts TLS connections using |tls|, which can be nil if the server only
+// listens on unix sockets, and unix socket connections from processes run by
+// the same user.
+func NewLocalTransportCredentials(tls credentials.TransportCredentials) credentials.TransportCredentials {
+	return localTransportCredentials{tls: tls}
+}
+
+func (l localTransportCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if l.tls == nil {
+		return nil, nil, NewNotSupportedError("client handshake")
+	}
+	return l.tls.ClientHandshake(ctx, authority, conn)
+}
+
+func (l localTransportCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if unix_conn, ok := conn.(*net.UnixConn); ok {
+		peer, err := getPeerCredentials(unix_conn)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = authorizePeer(peer)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, *peer, nil
+	}
+	if l.tls == nil {
+		return nil, nil, NewConfigIncompleteError("can't locate server key and certificate")
+	}
+	return l.tls.ServerHandshake(conn)
+}
+
+func (l localTransportCredentials) Info() credentials.ProtocolInfo {
+	if l.tls == nil {
+		return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+	}
+	return l.tls.Info()
+}
+
+func (l localTransportCredentials) Clone() credentials.TransportCredentials {
+	if l.tls == nil {
+		return localTransportCredentials{}
+	}
+	return localTransportCredentials{tls: l.tls.Clone()}
+}
+
+func (l localTransportCredentials) OverrideServerName(name string) error {
+	if l.tls == nil {
+		return nil
+	}
+	return l.tls.OverrideServerName(name)
+}
@@ -0,0 +1,29 @@
+package stonesthrow
+
+import (
+	"golang.org/x/sys/unix"
+	"net"
+)
+
+func getPeerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	raw_conn, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var xucred *unix.Xucred
+	var xucred_err error
+	err = raw_conn.Control(func(fd uintptr) {
+		xucred, xucred_err = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if xucred_err != nil {
+		return nil, xucred_err
+	}
+	peer := &PeerCredentials{Uid: xucred.Uid}
+	if xucred.Ngroups > 0 {
+		peer.Gid = xucred.Groups[0]
+	}
+	return peer, nil
+}
@@ -0,0 +1,25 @@
+package stonesthrow
+
+import (
+	"golang.org/x/sys/unix"
+	"net"
+)
+
+func getPeerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	raw_conn, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var ucred *unix.Ucred
+	var ucred_err error
+	err = raw_conn.Control(func(fd uintptr) {
+		ucred, ucred_err = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ucred_err != nil {
+		return nil, ucred_err
+	}
+	return &PeerCredentials{Uid: ucred.Uid, Gid: ucred.Gid, Pid: ucred.Pid}, nil
+}
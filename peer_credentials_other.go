@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package stonesthrow
+
+import (
+	"net"
+)
+
+func getPeerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	return nil, NewNotSupportedError("unix socket peer credentials aren't available on this platform")
+}
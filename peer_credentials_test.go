@@ -0,0 +1,69 @@
+package stonesthrow
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestAuthorizePeer(t *testing.T) {
+	if err := authorizePeer(&PeerCredentials{Uid: uint32(os.Getuid())}); err != nil {
+		t.Fatal(err)
+	}
+	if err := authorizePeer(&PeerCredentials{Uid: uint32(os.Getuid() + 1)}); !IsConnectionError(err) {
+		t.Fatalf("expected ConnectionError. got %v", err)
+	}
+}
+
+func TestUnixSocketEndpoint(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("peer credentials aren't supported")
+	}
+
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	endpoint := Endpoint{Network: "unix", Address: filepath.Join(dir, "st.sock")}
+	listener, err := listenOnEndpoint(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(endpoint.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("unexpected mode %v", info.Mode())
+	}
+
+	server := grpc.NewServer(append(RpcServerOptions(nil, nil), grpc.Creds(NewLocalTransportCredentials(nil)))...)
+	RegisterServiceHostServer(server, &ServiceHostServerImpl{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	if _, err = listenOnEndpoint(endpoint); !IsConnectionError(err) {
+		t.Fatalf("expected ConnectionError for a socket that's in use. got %v", err)
+	}
+
+	// No certificates are needed.
+	server_config := Config{Host: &HostConfig{Name: "a"}}
+	conn, err := connectToLocalEndpoint(context.Background(), server_config, endpoint, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	pong, err := NewServiceHostClient(conn).Ping(context.Background(), &PingOptions{Ping: "Ping!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pong.GetPong() != "Ping!" {
+		t.Fatalf("unexpected pong %v", pong)
+	}
+}
@@ -2,6 +2,7 @@ package stonesthrow
 
 import (
 	"golang.org/x/net/context"
+	"io"
 )
 
 type RepositoryPlatformGetter interface {
@@ -12,6 +13,8 @@ type RepositoryPlatformGetter interface {
 type BuildHostServerImpl struct {
 	Host         *HostConfig
 	ProcessAdder ProcessAdder
+	Scheduler    *JobScheduler
+	Connections  *ConnectionPool
 }
 
 func (p *BuildHostServerImpl) GetRepositoryAndPlatform(g RepositoryPlatformGetter) (*RepositoryConfig, *PlatformConfig) {
@@ -29,25 +32,70 @@ func (p *BuildHostServerImpl) GetRepositoryAndPlatform(g RepositoryPlatformGette
 }
 
 func (p *BuildHostServerImpl) GetExecutor(s JobEventSender, platform_config *PlatformConfig) Executor {
-	return NewJobEventExecutor(p.Host.Name, platform_config.BuildPath, p.ProcessAdder, s)
+	return p.GetExecutorWithInput(s, platform_config, nil, nil)
+}
+
+// GetExecutorWithInput returns an Executor whose commands read their stdin
+// from |stdin|. Passthrough commands run in a pseudo-terminal if requested
+// by |ro|. Commands see the environment variables configured for the host
+// and the platform as well as those specified in |ro|. Commands requested via
+// |ro| are subject to the platform's default timeout.
+func (p *BuildHostServerImpl) GetExecutorWithInput(s JobEventSender, platform_config *PlatformConfig, ro *RunOptions, stdin io.Reader) Executor {
+	executor := NewJobEventExecutor(p.Host.Name, platform_config.Repository.Name, platform_config.Name,
+		platform_config.BuildPath, p.ProcessAdder, s)
+	executor.SetStdin(stdin)
+	executor.SetEnvironment(MergeEnvironment(
+		p.GetScriptHostRunner(platform_config.Repository, platform_config).GetTokenReplacer(),
+		p.Host.Env, platform_config.Env, ro.GetCommand().GetEnv()))
+	if ro.GetCommand() != nil {
+		executor.SetDefaultTimeout(platform_config.DefaultTimeout())
+	}
+	if ro.GetTty() {
+		executor.SetTerminal(TerminalSizeOrDefault(ro.GetWindowSize()))
+	}
+	return executor
 }
 
 func (p *BuildHostServerImpl) GetRepositoryHostServer() RepositoryHostServer {
-	return &RepositoryHostServerImpl{Host: p.Host, ProcessAdder: p.ProcessAdder}
+	return &RepositoryHostServerImpl{Host: p.Host, ProcessAdder: p.ProcessAdder, Scheduler: p.Scheduler, Connections: p.Connections}
 }
 
 func (p *BuildHostServerImpl) GetScriptHostRunner(repo *RepositoryConfig, platform *PlatformConfig) ScriptHost {
-	var runner ScriptHost
+	runner := ScriptHost{ProcessAdder: p.ProcessAdder, Scheduler: p.Scheduler, Connections: p.Connections}
 	runner.Config.Set(p.Host, repo, platform)
 	return runner
 }
 
 func (p *BuildHostServerImpl) RunScriptCommand(ro *RunOptions, s BuildHost_RunScriptCommandServer) error {
+	return p.runScriptCommand(ro, nil, s)
+}
+
+func (p *BuildHostServerImpl) RunScriptCommandWithInput(s BuildHost_RunScriptCommandWithInputServer) error {
+	ro, stdin, err := ReceiveCommandInput(s)
+	if err != nil {
+		return err
+	}
+	return p.runScriptCommand(ro, stdin, s)
+}
+
+func (p *BuildHostServerImpl) runScriptCommand(ro *RunOptions, stdin io.Reader, s JobEventServer) error {
 	repo, platform := p.GetRepositoryAndPlatform(ro)
 	if repo == nil {
 		return NewInvalidPlatformError("repository %s and platform %s are invalid", ro.GetRepository(), ro.GetPlatform())
 	}
-	return p.GetScriptHostRunner(repo, platform).RunScriptCommand(ro, p.GetExecutor(s, platform), s)
+	script_host := p.GetScriptHostRunner(repo, platform)
+	if !ro.GetDetach() {
+		return script_host.RunScriptCommand(ro, p.GetExecutorWithInput(s, platform, ro, stdin), s)
+	}
+
+	err := script_host.PrepareToRunScriptCommand(ro, p.GetExecutor(s, platform), s)
+	if err != nil {
+		return err
+	}
+	job := &BuilderJob{Command: ro.GetCommand(), Repository: repo.Name, Platform: platform.Name}
+	return RunDetached(s, p.ProcessAdder, job, func(ctx context.Context, sender JobEventSender) error {
+		return script_host.ExecuteScriptCommand(ctx, ro, p.GetExecutorWithInput(sender, platform, ro, stdin), sender)
+	})
 }
 
 func (p *BuildHostServerImpl) ListScriptCommands(ctx context.Context, l *ListCommandsOptions) (*CommandList, error) {
@@ -68,17 +116,35 @@ func (p *BuildHostServerImpl) ListTargets(context.Context, *ListTargetsOptions)
 }
 
 func (p *BuildHostServerImpl) RunShellCommand(ro *RunOptions, s BuildHost_RunShellCommandServer) error {
+	return p.runShellCommand(ro, nil, s)
+}
+
+func (p *BuildHostServerImpl) RunShellCommandWithInput(s BuildHost_RunShellCommandWithInputServer) error {
+	ro, stdin, err := ReceiveCommandInput(s)
+	if err != nil {
+		return err
+	}
+	return p.runShellCommand(ro, stdin, s)
+}
+
+func (p *BuildHostServerImpl) runShellCommand(ro *RunOptions, stdin io.Reader, s JobEventServer) error {
 	repo, platform := p.GetRepositoryAndPlatform(ro)
 	if repo == nil {
 		return NewInvalidPlatformError("repository %s and platform %s are invalid", ro.GetRepository(), ro.GetPlatform())
 	}
 
-	e := p.GetExecutor(s, platform)
 	script_runner := p.GetScriptHostRunner(repo, platform)
-	return e.ExecuteInWorkDirPassthrough(
-		script_runner.ExpandTokens(ro.GetCommand().GetDirectory()),
-		s.Context(),
-		script_runner.ExpandTokensInArray(ro.GetCommand().GetCommand())...)
+	run := func(ctx context.Context, sender JobEventSender) error {
+		return p.GetExecutorWithInput(sender, platform, ro, stdin).ExecuteInWorkDirPassthrough(
+			script_runner.ExpandTokens(ro.GetCommand().GetDirectory()),
+			ctx,
+			script_runner.ExpandTokensInArray(ro.GetCommand().GetCommand())...)
+	}
+	if ro.GetDetach() {
+		job := &BuilderJob{Command: ro.GetCommand(), Repository: repo.Name, Platform: platform.Name}
+		return RunDetached(s, p.ProcessAdder, job, run)
+	}
+	return run(s.Context(), s)
 }
 
 func (r *BuildHostServerImpl) FetchFile(fo *FetchFileOptions, s BuildHost_FetchFileServer) error {
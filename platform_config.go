@@ -3,6 +3,7 @@ package stonesthrow
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 )
 
 type Endpoint struct {
@@ -14,8 +15,11 @@ type Endpoint struct {
 }
 
 type PlatformConfig struct {
-	RelativeBuildPath string `json:"out,omitempty"`
-	MbConfigName      string `json:"mb_config,omitempty"`
+	RelativeBuildPath string            `json:"out,omitempty"`
+	MbConfigName      string            `json:"mb_config,omitempty"`
+	MaxHeavyJobs      int               `json:"max_heavy_jobs,omitempty"`
+	TimeoutMinutes    int               `json:"timeout_minutes,omitempty"`
+	Env               map[string]string `json:"env,omitempty"`
 
 	Name       string            `json:"-"`
 	BuildPath  string            `json:"-"`
@@ -42,6 +46,12 @@ func (p *PlatformConfig) Validate() error {
 	return nil
 }
 
+// DefaultTimeout returns how long commands are allowed to run on the platform
+// if the client didn't specify a timeout. Zero means no limit.
+func (p *PlatformConfig) DefaultTimeout() time.Duration {
+	return time.Duration(p.TimeoutMinutes) * time.Minute
+}
+
 func (p *PlatformConfig) RelativePath(paths ...string) string {
 	paths = append([]string{p.BuildPath}, paths...)
 	return filepath.Join(paths...)
@@ -0,0 +1,24 @@
+package stonesthrow
+
+import (
+	"os"
+	"time"
+)
+
+// StopProcessGroup terminates the process group led by |process|. The group is
+// first asked to terminate. If |exited| isn't signalled within |grace_period|,
+// then the group is killed.
+func StopProcessGroup(process *os.Process, grace_period time.Duration, exited <-chan struct{}) error {
+	err := TerminateProcessGroup(process)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(grace_period):
+	}
+
+	return KillProcessGroup(process)
+}
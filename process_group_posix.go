@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package stonesthrow
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setNewProcessGroup arranges for |cmd| to be started as the leader of a new
+// process group so that it can be signalled along with all its descendants.
+func setNewProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func TerminateProcessGroup(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGTERM)
+}
+
+func KillProcessGroup(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGKILL)
+}
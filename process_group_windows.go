@@ -0,0 +1,20 @@
+package stonesthrow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+func setNewProcessGroup(cmd *exec.Cmd) {
+}
+
+// There are no process groups to speak of on Windows. Instead taskkill is used
+// to walk the process tree rooted at |process|.
+func TerminateProcessGroup(process *os.Process) error {
+	return exec.Command("taskkill", "/T", "/PID", fmt.Sprintf("%d", process.Pid)).Run()
+}
+
+func KillProcessGroup(process *os.Process) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", fmt.Sprintf("%d", process.Pid)).Run()
+}
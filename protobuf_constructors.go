@@ -1,11 +1,22 @@
 package stonesthrow
 
 import (
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"os"
+	"syscall"
 	"time"
 )
 
+// kJsonMarshaler and kJsonUnmarshaler convert messages that are stored on
+// disk, e.g. job history and audit records, to and from JSON. Fields that are
+// unknown to older builds are ignored.
+var (
+	kJsonMarshaler   = jsonpb.Marshaler{OrigName: true}
+	kJsonUnmarshaler = jsonpb.Unmarshaler{AllowUnknownFields: true}
+)
+
 func NewTimestampFromTime(t time.Time) *timestamp.Timestamp {
 	t = t.UTC()
 	return &timestamp.Timestamp{
@@ -24,6 +35,39 @@ func TimestampNow() *timestamp.Timestamp {
 	return NewTimestampFromTime(time.Now())
 }
 
+func TimeFromTimestamp(t *timestamp.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Unix(t.GetSeconds(), int64(t.GetNanos()))
+}
+
+// NewEndCommandEventFromProcessState describes how a process terminated. If the
+// process was terminated by a signal, then the return code follows the shell
+// convention of 128 + the signal number.
+func NewEndCommandEventFromProcessState(state *os.ProcessState) *EndCommandEvent {
+	e := &EndCommandEvent{
+		SystemTime: NewDurationFromDuration(state.SystemTime()),
+		UserTime:   NewDurationFromDuration(state.UserTime())}
+
+	wait_status, ok := state.Sys().(syscall.WaitStatus)
+	switch {
+	case !ok:
+		if !state.Success() {
+			e.ReturnCode = 1
+		}
+
+	case wait_status.Signaled():
+		e.Signal = int32(wait_status.Signal())
+		e.SignalName = wait_status.Signal().String()
+		e.ReturnCode = 128 + e.Signal
+
+	default:
+		e.ReturnCode = int32(wait_status.ExitStatus())
+	}
+	return e
+}
+
 func BranchListFromGitRepositoryInfo_Branch(r []*GitRepositoryInfo_Branch) []string {
 	s := []string{}
 	for _, b := range r {
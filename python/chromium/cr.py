@@ -46,6 +46,21 @@ def CommandNeedsSource(func):
     return func
 
 
+def LightCommand(func):
+    """Decorator @LightCommand annotates a command handler indicating that
+    the command is cheap enough to run alongside builds. Commands that aren't
+    light wait for a heavy job slot on the builder host before running.
+
+    Usage:
+      @LightCommand
+      def foo_Command():
+        pass
+
+    """
+    vars(func)['light'] = True
+    return func
+
+
 def InvokeMb(options, *args):
     if len(args) == 0 or not isinstance(options, stonesthrow.Options):
         raise ValueError('first argument should be an Options object')
@@ -128,6 +143,7 @@ class Commands:
     parsed arguments.
     """
 
+    @LightCommand
     def Prepare_Command(self, options):
         """prepare build directory."""
 
@@ -280,6 +296,7 @@ def _GetCommandDescriptor(command_name, command):
     description = doc[0]
     depends_on_source = hasattr(command,
                                 'needs_source') and command.needs_source
+    light = hasattr(command, 'light') and command.light
 
     usage = '\n'.join(doc[2:])
 
@@ -296,6 +313,7 @@ def _GetCommandDescriptor(command_name, command):
         "description": description,
         "usage": usage,
         "depends_on_source": depends_on_source,
+        "light": light,
         "visible": True
     }
 
@@ -8,6 +8,11 @@ type RemoteTransportConfig struct {
 	SshHost    string   `json:"ssh_config,omitempty"`
 	SshCommand []string `json:"ssh_command,omitempty"`
 
+	// Use the built-in ssh client instead of running ssh. |SshHost| is
+	// looked up in ~/.ssh/config. Authenticates using the ssh agent and
+	// the configured identity files.
+	NativeSsh bool `json:"native_ssh,omitempty"`
+
 	HostName string      `json:"-"`
 	Host     *HostConfig `json:"-"`
 }
@@ -22,21 +27,32 @@ func (r *RemoteTransportConfig) Validate() error {
 		fmt.Printf("%#v", r)
 		return fmt.Errorf("no remote connection specified")
 	}
+
+	if r.NativeSsh && r.SshHost == "" {
+		return fmt.Errorf("native_ssh requires ssh_config")
+	}
 	return nil
 }
 
+// GetPassthroughCommand returns the command that runs a passthrough client
+// to |server| on the remote host.
+func (r *RemoteTransportConfig) GetPassthroughCommand(server *Config) []string {
+	return []string{
+		fmt.Sprintf("%s/bin/st_client", r.Host.GoPath),
+		"--platform", server.Platform.Name,
+		"--repository", server.Repository.Name,
+		"passthrough"}
+}
+
 func (r *RemoteTransportConfig) GetSshPassthroughCommand(server *Config) []string {
 	command := []string{}
 
 	if r.SshCommand != nil {
-		command = r.SshCommand
+		command = append(command, r.SshCommand...)
 	} else {
 		command = []string{"ssh", r.SshHost}
 	}
 
-	return append(command, "-T",
-		fmt.Sprintf("%s/bin/st_client", r.Host.GoPath),
-		"--platform", server.Platform.Name,
-		"--repository", server.Repository.Name,
-		"passthrough")
+	command = append(command, "-T")
+	return append(command, r.GetPassthroughCommand(server)...)
 }
@@ -239,6 +239,76 @@ func (r RepositoryCommands) GitFetch(ctx context.Context, branches []string) err
 	return r.ExecutePassthrough(ctx, command...)
 }
 
+// parseRevisions parses lines of the form "<id> <name>" as output by
+// git for-each-ref and git bundle list-heads.
+func parseRevisions(output string) []*Revision {
+	revisions := []*Revision{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		revisions = append(revisions, &Revision{Id: fields[0], Name: fields[1]})
+	}
+	return revisions
+}
+
+// GitListRefs returns the local and remote-tracking branches of the
+// repository along with the revisions they point to.
+func (r RepositoryCommands) GitListRefs(ctx context.Context) ([]*Revision, error) {
+	output, err := r.ExecuteNoStream(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)",
+		"refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	return parseRevisions(output), nil
+}
+
+// GitCreateBundle writes a bundle containing |revision| to |path|. Objects
+// that are reachable from any of |base_revisions| are left out, as long as
+// the repository has them.
+func (r RepositoryCommands) GitCreateBundle(ctx context.Context, path string, revision string, base_revisions []string) error {
+	command := []string{"git", "bundle", "create", path, revision, "--not"}
+	for _, base_revision := range base_revisions {
+		_, err := r.ExecuteNoStream(ctx, "git", "cat-file", "-e", base_revision)
+		if err != nil {
+			continue
+		}
+		command = append(command, base_revision)
+	}
+	_, err := r.ExecuteNoStream(ctx, command...)
+	return err
+}
+
+// GitFetchBundle updates the refs in the repository to match those in the
+// bundle at |path|. Refs are updated even if the update isn't a
+// fast-forward. Returns the refs that were updated.
+func (r RepositoryCommands) GitFetchBundle(ctx context.Context, path string) ([]*Revision, error) {
+	output, err := r.ExecuteNoStream(ctx, "git", "bundle", "list-heads", path)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []*Revision{}
+	command := []string{"git", "fetch", "--no-tags", path}
+	for _, revision := range parseRevisions(output) {
+		if !strings.HasPrefix(revision.Name, "refs/") {
+			continue
+		}
+		revisions = append(revisions, revision)
+		command = append(command, fmt.Sprintf("+%s:%s", revision.Name, revision.Name))
+	}
+	if len(revisions) == 0 {
+		return nil, NewNothingToDoError("bundle doesn't contain any refs")
+	}
+
+	_, err = r.ExecuteNoStream(ctx, command...)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
 func (r RepositoryCommands) GitHashObject(ctx context.Context, path string) (string, error) {
 	return r.ExecuteNoStream(ctx, "git", "hash-object", path)
 }
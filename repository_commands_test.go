@@ -0,0 +1,93 @@
+package stonesthrow
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func gitCommitForTest(t *testing.T, dir string, message string) {
+	output, err := exec.Command("git", "-C", dir, "-c", "user.name=st", "-c", "user.email=st@example.com",
+		"commit", "-q", "--allow-empty", "-m", message).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git commit failed: %s: %s", err, output)
+	}
+}
+
+func newTestRepository(t *testing.T, dir string) RepositoryCommands {
+	output, err := exec.Command("git", "init", "-q", dir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git init failed: %s: %s", err, output)
+	}
+	gitCommitForTest(t, dir, "initial")
+	return RepositoryCommands{
+		Repository: &RepositoryConfig{SourcePath: dir},
+		Executor:   NewJobEventExecutor("", "", "", dir, nil, nil)}
+}
+
+func TestRepositoryCommands_Bundle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("requires git")
+	}
+
+	dir, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	source := newTestRepository(t, filepath.Join(dir, "source"))
+
+	// Only the commit on the feature branch needs to be sent to a clone that
+	// already has the initial commit.
+	target_dir := filepath.Join(dir, "target")
+	output, err := exec.Command("git", "clone", "-q", source.Repository.SourcePath, target_dir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git clone failed: %s: %s", err, output)
+	}
+	target := RepositoryCommands{
+		Repository: &RepositoryConfig{SourcePath: target_dir},
+		Executor:   NewJobEventExecutor("", "", "", target_dir, nil, nil)}
+	target_head, err := target.GitRevision(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = source.ExecuteNoStream(ctx, "git", "checkout", "-q", "-b", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitCommitForTest(t, source.Repository.SourcePath, "feature")
+	source_refs, err := source.GitListRefs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(source_refs) != 2 || source_refs[0].Name != "refs/heads/feature" {
+		t.Fatalf("unexpected refs %v", source_refs)
+	}
+
+	bundle := filepath.Join(dir, "bundle")
+	err = source.GitCreateBundle(ctx, bundle, "refs/heads/feature", []string{target_head})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisions, err := target.GitFetchBundle(ctx, bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 || revisions[0].Name != "refs/heads/feature" || revisions[0].Id != source_refs[0].Id {
+		t.Fatalf("unexpected revisions %v", revisions)
+	}
+
+	revision, err := target.GitRevision(ctx, "refs/heads/feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision != source_refs[0].Id {
+		t.Fatalf("feature is at %s. expected %s", revision, source_refs[0].Id)
+	}
+}
@@ -3,6 +3,7 @@ package stonesthrow
 import (
 	"fmt"
 	"golang.org/x/net/context"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -11,6 +12,8 @@ import (
 type RepositoryHostServerImpl struct {
 	Host         *HostConfig
 	ProcessAdder ProcessAdder
+	Scheduler    *JobScheduler
+	Connections  *ConnectionPool
 }
 
 type RepositoryGetter interface {
@@ -27,13 +30,25 @@ func (r *RepositoryHostServerImpl) getRepository(rg RepositoryGetter) (*Reposito
 }
 
 func (r *RepositoryHostServerImpl) getExecutor(s JobEventSender, repo *RepositoryConfig) Executor {
-	return NewJobEventExecutor(repo.Host.Name, repo.SourcePath, r.ProcessAdder, s)
+	return r.getExecutorWithInput(s, repo, nil, nil)
+}
+
+func (r *RepositoryHostServerImpl) getExecutorWithInput(s JobEventSender, repo *RepositoryConfig, ro *RunOptions, stdin io.Reader) Executor {
+	executor := NewJobEventExecutor(repo.Host.Name, repo.Name, "", repo.SourcePath, r.ProcessAdder, s)
+	executor.SetStdin(stdin)
+	// Platform environments don't apply to the repository. Neither does {out}.
+	replacer := strings.NewReplacer("{src}", repo.SourcePath, "{st}", r.Host.StonesthrowPath)
+	executor.SetEnvironment(MergeEnvironment(replacer, r.Host.Env, ro.GetCommand().GetEnv()))
+	if ro.GetTty() {
+		executor.SetTerminal(TerminalSizeOrDefault(ro.GetWindowSize()))
+	}
+	return executor
 }
 
 func (r *RepositoryHostServerImpl) getScriptHostRunner(repo *RepositoryConfig) ScriptHost {
 	var config Config
 	config.Set(r.Host, repo, repo.AnyPlatform())
-	return ScriptHost{Config: config, ProcessAdder: r.ProcessAdder}
+	return ScriptHost{Config: config, ProcessAdder: r.ProcessAdder, Scheduler: r.Scheduler, Connections: r.Connections}
 }
 
 func (r *RepositoryHostServerImpl) GetGitCommandsForJobEventSender(s JobEventSender, repo *RepositoryConfig) (Executor, RepositoryCommands) {
@@ -42,19 +57,22 @@ func (r *RepositoryHostServerImpl) GetGitCommandsForJobEventSender(s JobEventSen
 	return executor, commands
 }
 
-func (r *RepositoryHostServerImpl) GetRepositoryUpstreamPeer(ctx context.Context, repo *RepositoryConfig) (RepositoryHostClient, error) {
+// GetRepositoryUpstreamPeer returns a client for the host of the upstream
+// repository of |repo|. The connection comes from the connection pool. Call
+// the returned function once done with the client.
+func (r *RepositoryHostServerImpl) GetRepositoryUpstreamPeer(ctx context.Context, repo *RepositoryConfig) (RepositoryHostClient, func(), error) {
 	var remote_config Config
 	remote_config.SetFromRepository(repo.GitConfig.RemoteHost.Repositories[repo.Name])
 
 	var local_config Config
 	local_config.SetFromRepository(repo)
 
-	rpc_connection, err := ConnectTo(ctx, local_config, remote_config)
+	rpc_connection, release, err := r.Connections.Get(ctx, local_config, remote_config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	remote_repo_client := NewRepositoryHostClient(rpc_connection)
-	return remote_repo_client, nil
+	return remote_repo_client, release, nil
 }
 
 func SelectMatchingBranchConfigs(branches []string, branch_configs []*GitRepositoryInfo_Branch) []*GitRepositoryInfo_Branch {
@@ -254,10 +272,11 @@ func (r *RepositoryHostServerImpl) PullFromUpstream(list *BranchList, s Reposito
 		return nil
 	}
 
-	remote_repo_client, err := r.GetRepositoryUpstreamPeer(s.Context(), repo)
+	remote_repo_client, release, err := r.GetRepositoryUpstreamPeer(s.Context(), repo)
 	if err != nil {
 		return err
 	}
+	defer release()
 
 	options := BranchConfigOptions{IncludeGitConfig: true}
 
@@ -306,10 +325,11 @@ func (r *RepositoryHostServerImpl) PushToUpstream(list *BranchList, s Repository
 		return err
 	}
 
-	remote_repo_client, err := r.GetRepositoryUpstreamPeer(s.Context(), repo)
+	remote_repo_client, release, err := r.GetRepositoryUpstreamPeer(s.Context(), repo)
 	if err != nil {
 		return err
 	}
+	defer release()
 
 	jobevent_receiver, err := remote_repo_client.PrepareForReceive(s.Context(), repo_state)
 	if err != nil {
@@ -381,11 +401,35 @@ func (r *RepositoryHostServerImpl) FetchFile(fo *FetchFileOptions, s RepositoryH
 }
 
 func (r *RepositoryHostServerImpl) RunScriptCommand(ro *RunOptions, s RepositoryHost_RunScriptCommandServer) error {
+	return r.runScriptCommand(ro, nil, s)
+}
+
+func (r *RepositoryHostServerImpl) RunScriptCommandWithInput(s RepositoryHost_RunScriptCommandWithInputServer) error {
+	ro, stdin, err := ReceiveCommandInput(s)
+	if err != nil {
+		return err
+	}
+	return r.runScriptCommand(ro, stdin, s)
+}
+
+func (r *RepositoryHostServerImpl) runScriptCommand(ro *RunOptions, stdin io.Reader, s JobEventServer) error {
 	repo, err := r.getRepository(ro)
 	if err != nil {
 		return err
 	}
-	return r.getScriptHostRunner(repo).RunScriptCommand(ro, r.getExecutor(s, repo), s)
+	script_host := r.getScriptHostRunner(repo)
+	if !ro.GetDetach() {
+		return script_host.RunScriptCommand(ro, r.getExecutorWithInput(s, repo, ro, stdin), s)
+	}
+
+	err = script_host.PrepareToRunScriptCommand(ro, r.getExecutor(s, repo), s)
+	if err != nil {
+		return err
+	}
+	job := &BuilderJob{Command: ro.GetCommand(), Repository: repo.Name}
+	return RunDetached(s, r.ProcessAdder, job, func(ctx context.Context, sender JobEventSender) error {
+		return script_host.ExecuteScriptCommand(ctx, ro, r.getExecutorWithInput(sender, repo, ro, stdin), sender)
+	})
 }
 
 func (r *RepositoryHostServerImpl) ListScriptCommands(ctx context.Context, l *ListCommandsOptions) (*CommandList, error) {
@@ -398,16 +442,34 @@ func (r *RepositoryHostServerImpl) ListScriptCommands(ctx context.Context, l *Li
 }
 
 func (r *RepositoryHostServerImpl) RunShellCommand(ro *RunOptions, s RepositoryHost_RunShellCommandServer) error {
+	return r.runShellCommand(ro, nil, s)
+}
+
+func (r *RepositoryHostServerImpl) RunShellCommandWithInput(s RepositoryHost_RunShellCommandWithInputServer) error {
+	ro, stdin, err := ReceiveCommandInput(s)
+	if err != nil {
+		return err
+	}
+	return r.runShellCommand(ro, stdin, s)
+}
+
+func (r *RepositoryHostServerImpl) runShellCommand(ro *RunOptions, stdin io.Reader, s JobEventServer) error {
 	repo, err := r.getRepository(ro)
 	if err != nil {
 		return err
 	}
-	e := r.getExecutor(s, repo)
 	script_host_runner := r.getScriptHostRunner(repo)
-	return e.ExecuteInWorkDirPassthrough(
-		script_host_runner.ExpandTokens(ro.GetCommand().GetDirectory()),
-		s.Context(),
-		script_host_runner.ExpandTokensInArray(ro.GetCommand().GetCommand())...)
+	run := func(ctx context.Context, sender JobEventSender) error {
+		return r.getExecutorWithInput(sender, repo, ro, stdin).ExecuteInWorkDirPassthrough(
+			script_host_runner.ExpandTokens(ro.GetCommand().GetDirectory()),
+			ctx,
+			script_host_runner.ExpandTokensInArray(ro.GetCommand().GetCommand())...)
+	}
+	if ro.GetDetach() {
+		job := &BuilderJob{Command: ro.GetCommand(), Repository: repo.Name}
+		return RunDetached(s, r.ProcessAdder, job, run)
+	}
+	return run(s.Context(), s)
 }
 
 func GetRepositoryState(ctx context.Context, r *RepositoryConfig, e Executor, push_builder_head bool) (*RepositoryState, error) {
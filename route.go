@@ -0,0 +1,79 @@
+package stonesthrow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Route is a path from a client host to a server. The client connects to
+// each host in |Hops| in turn via ssh and runs a passthrough client there.
+// The last host, or the client if there are no hops, connects to |Endpoint|.
+type Route struct {
+	Client   *HostConfig
+	Server   *HostConfig
+	Hops     []*RemoteTransportConfig
+	Endpoint *Endpoint
+}
+
+func (r *Route) String() string {
+	parts := []string{r.Client.Name}
+	for _, hop := range r.Hops {
+		parts = append(parts, fmt.Sprintf("(ssh) %s", hop.Host.Name))
+	}
+	parts = append(parts, fmt.Sprintf("(%s %s) %s", r.Endpoint.Network, r.Endpoint.Address, r.Server.Name))
+	return strings.Join(parts, " -> ")
+}
+
+// sortedRemotes returns the remotes of |host| sorted by name so that routes
+// are chosen consistently by each host along the way.
+func sortedRemotes(host *HostConfig) []*RemoteTransportConfig {
+	names := []string{}
+	for name := range host.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	remotes := []*RemoteTransportConfig{}
+	for _, name := range names {
+		if host.Remotes[name].Host != nil {
+			remotes = append(remotes, host.Remotes[name])
+		}
+	}
+	return remotes
+}
+
+// FindRoute returns the route from |client| to |server| that has the fewest
+// ssh hops. A host can be used as a hop if the previous host lists it in its
+// remotes. The route ends at the first host that the server has an endpoint
+// on.
+func FindRoute(client *HostConfig, server *HostConfig) (*Route, error) {
+	type visit struct {
+		host     *HostConfig
+		previous *visit
+		hop      *RemoteTransportConfig
+	}
+
+	visited := map[*HostConfig]bool{client: true}
+	queue := []*visit{&visit{host: client}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if endpoint := server.GetEndpointOnHost(current.host); endpoint != nil {
+			route := &Route{Client: client, Server: server, Endpoint: endpoint}
+			for v := current; v.previous != nil; v = v.previous {
+				route.Hops = append([]*RemoteTransportConfig{v.hop}, route.Hops...)
+			}
+			return route, nil
+		}
+
+		for _, remote := range sortedRemotes(current.host) {
+			if visited[remote.Host] {
+				continue
+			}
+			visited[remote.Host] = true
+			queue = append(queue, &visit{host: remote.Host, previous: current, hop: remote})
+		}
+	}
+	return nil, NewNoRouteToTargetError("%s can't reach %s", client.Name, server.Name)
+}
@@ -0,0 +1,59 @@
+package stonesthrow
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFindRoute(t *testing.T) {
+	var hosts HostsConfig
+	err := hosts.ReadFrom(filepath.Join("testdata", "config-multihop.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	laptop := hosts.HostByName("laptop")
+	jump := hosts.HostByName("jump")
+	build := hosts.HostByName("build")
+
+	// The archive host is a dead end.
+	route, err := FindRoute(laptop, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Hops) != 2 || route.Hops[0].Host != hosts.HostByName("gateway") || route.Hops[1].Host != jump {
+		t.Fatalf("unexpected route %s", route)
+	}
+	if route.Endpoint.Address != "10.0.0.5:9761" {
+		t.Fatalf("unexpected endpoint %v", route.Endpoint)
+	}
+	expected := "laptop.example.com -> (ssh) gateway.example.com -> (ssh) jump.example.com -> (tcp 10.0.0.5:9761) build.example.com"
+	if route.String() != expected {
+		t.Fatalf("expected %s. got %s", expected, route)
+	}
+
+	// The passthrough client on the jump host connects directly.
+	route, err = FindRoute(jump, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Hops) != 0 || route.Endpoint.Address != "10.0.0.5:9761" {
+		t.Fatalf("unexpected route %s", route)
+	}
+
+	// Local clients prefer the unix socket.
+	route, err = FindRoute(build, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Hops) != 0 || route.Endpoint.Network != "unix" || route.Endpoint.Address != "/tmp/st-build.sock" {
+		t.Fatalf("unexpected route %s", route)
+	}
+	if len(build.GetEndpointsOnHost(build)) != 2 {
+		t.Fatalf("unexpected endpoints %v", build.GetEndpointsOnHost(build))
+	}
+
+	_, err = FindRoute(build, laptop)
+	if !IsNoRouteToTargetError(err) {
+		t.Fatalf("expected NoRouteToTargetError. got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package stonesthrow
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Clients that want the server's stack traces in errors set this key in the
+// metadata of their RPCs.
+const kErrorStackMetadataKey = "st-error-stack"
+
+// WithErrorStacks returns a context for making RPCs whose errors include the
+// stack on the server where the error occurred.
+func WithErrorStacks(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs(kErrorStackMetadataKey, "1"))
+}
+
+func wantsErrorStacks(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md[kErrorStackMetadataKey]) != 0
+}
+
+// ToRpcError converts an ErrorWithDetails into a gRPC status error with the
+// error's code. The error class and details are attached as an ErrorDetails
+// message. The stack is only attached if |include_stack| is true. Other errors
+// are returned as is.
+func ToRpcError(err error, include_stack bool) error {
+	e, ok := err.(ErrorWithDetails)
+	if !ok {
+		return err
+	}
+	details := &ErrorDetails{ErrorClass: e.ErrorClass, Details: e.Details}
+	if include_stack {
+		details.Stack = e.Stack
+	}
+	s, details_err := status.New(e.Code, e.Error()).WithDetails(details)
+	if details_err != nil {
+		return status.Error(e.Code, e.Error())
+	}
+	return s.Err()
+}
+
+// FromRpcError is the inverse of ToRpcError. The resulting ErrorWithDetails
+// matches the same Is*Error function as the error returned by the server.
+func FromRpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, detail := range s.Details() {
+		if details, ok := detail.(*ErrorDetails); ok {
+			return ErrorWithDetails{
+				ErrorClass: details.GetErrorClass(),
+				Details:    details.GetDetails(),
+				Stack:      details.GetStack(),
+				Code:       s.Code()}
+		}
+	}
+	return err
+}
+
+func unaryServerErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, ToRpcError(err, wantsErrorStacks(ctx))
+}
+
+func streamServerErrorInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return ToRpcError(handler(srv, ss), wantsErrorStacks(ss.Context()))
+}
+
+func unaryClientErrorInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return FromRpcError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+type rpcErrorClientStream struct {
+	grpc.ClientStream
+}
+
+func (s rpcErrorClientStream) SendMsg(m interface{}) error {
+	return FromRpcError(s.ClientStream.SendMsg(m))
+}
+
+func (s rpcErrorClientStream) RecvMsg(m interface{}) error {
+	return FromRpcError(s.ClientStream.RecvMsg(m))
+}
+
+func streamClientErrorInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, FromRpcError(err)
+	}
+	return rpcErrorClientStream{stream}, nil
+}
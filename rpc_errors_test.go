@@ -0,0 +1,58 @@
+package stonesthrow
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"testing"
+)
+
+func TestRpcError_RoundTrip(t *testing.T) {
+	rpc_err := ToRpcError(NewNoUpstreamError("chrome"), false)
+	if grpc.Code(rpc_err) != codes.FailedPrecondition {
+		t.Fatalf("unexpected code for %v", rpc_err)
+	}
+
+	err := FromRpcError(rpc_err)
+	if !IsNoUpstreamError(err) || IsInvalidPlatformError(err) {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if err.(ErrorWithDetails).Details != "chrome" || len(err.(ErrorWithDetails).Stack) != 0 {
+		t.Fatalf("unexpected details %#v", err)
+	}
+
+	err = FromRpcError(ToRpcError(NewInvalidPlatformError("mac"), true))
+	if !IsInvalidPlatformError(err) || len(err.(ErrorWithDetails).Stack) == 0 {
+		t.Fatalf("expected an InvalidPlatformError with a stack. got %#v", err)
+	}
+
+	if ToRpcError(io.EOF, true) != io.EOF || FromRpcError(io.EOF) != io.EOF || FromRpcError(nil) != nil {
+		t.Fatal("other errors should be returned as is")
+	}
+
+	plain_err := grpc.Errorf(codes.Unimplemented, "unknown service")
+	if FromRpcError(plain_err) != plain_err {
+		t.Fatal("errors without details should be returned as is")
+	}
+}
+
+func TestRpcError_ServerInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, NewJobNotFoundError("3")
+	}
+
+	_, err := unaryServerErrorInterceptor(context.Background(), nil, nil, handler)
+	err = FromRpcError(err)
+	if !IsJobNotFoundError(err) || len(err.(ErrorWithDetails).Stack) != 0 {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kErrorStackMetadataKey, "1"))
+	_, err = unaryServerErrorInterceptor(ctx, nil, nil, handler)
+	err = FromRpcError(err)
+	if !IsJobNotFoundError(err) || len(err.(ErrorWithDetails).Stack) == 0 {
+		t.Fatalf("expected a stack. got %#v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package stonesthrow
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"time"
+)
+
+const (
+	// Clients ping idle connections so that connections that have been
+	// lost, e.g. due to a flaky network, are detected and re-established.
+	kClientKeepaliveTime    = 30 * time.Second
+	kClientKeepaliveTimeout = 10 * time.Second
+
+	kServerKeepaliveTime    = time.Minute
+	kServerKeepaliveTimeout = 20 * time.Second
+
+	// Must not exceed kClientKeepaliveTime. Otherwise the server closes
+	// the connections of clients that ping too often.
+	kServerKeepaliveMinTime = 20 * time.Second
+
+	kMaxReconnectBackoff = 10 * time.Second
+)
+
+// RpcServerOptions are the options for gRPC servers. Errors returned by the
+// server can be converted back using FromRpcError. RPCs are only allowed if
+// |authorizer| allows them. A nil |authorizer| allows all RPCs. Each RPC,
+// including those that aren't allowed, is recorded in |audit_log| unless it's
+// nil.
+func RpcServerOptions(authorizer *Authorizer, audit_log *AuditLog) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(audit_log.unaryServerInterceptor(authorizer.unaryServerInterceptor)),
+		grpc.StreamInterceptor(audit_log.streamServerInterceptor(authorizer.streamServerInterceptor)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    kServerKeepaliveTime,
+			Timeout: kServerKeepaliveTimeout}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             kServerKeepaliveMinTime,
+			PermitWithoutStream: true})}
+}
+
+// RpcDialOptions are the options for gRPC client connections. RPCs made via
+// the connection return the errors that the server returned as
+// ErrorWithDetails. Idempotent RPCs are retried if the server can't be
+// reached.
+func RpcDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(retryingUnaryClientInterceptor),
+		grpc.WithStreamInterceptor(retryingStreamClientInterceptor),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                kClientKeepaliveTime,
+			Timeout:             kClientKeepaliveTimeout,
+			PermitWithoutStream: true}),
+		grpc.WithBackoffMaxDelay(kMaxReconnectBackoff)}
+}
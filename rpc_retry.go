@@ -0,0 +1,143 @@
+package stonesthrow
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"time"
+)
+
+const (
+	kMaxRpcRetries          = 4
+	kRpcRetryInitialBackoff = 100 * time.Millisecond
+	kRpcRetryMaxBackoff     = 2 * time.Second
+)
+
+// kIdempotentMethods are the RPCs that can be retried safely if the
+// connection fails. Streaming RPCs in this list are only retried if nothing
+// has been received yet.
+var kIdempotentMethods = map[string]bool{
+	"/stonesthrow.BuildHost/ListScriptCommands":      true,
+	"/stonesthrow.BuildHost/ListTargets":             true,
+	"/stonesthrow.RepositoryHost/ListScriptCommands": true,
+	"/stonesthrow.RepositoryHost/GetBranchConfig":    true,
+	"/stonesthrow.ServiceHost/Hello":                 true,
+	"/stonesthrow.ServiceHost/Ping":                  true,
+	"/stonesthrow.ServiceHost/ListJobs":              true,
+	"/stonesthrow.ServiceHost/GetJobHistory":         true,
+	"/stonesthrow.Host/Hello":                        true,
+	"/stonesthrow.Host/Ping":                         true,
+	"/stonesthrow.Host/ListJobs":                     true,
+	"/stonesthrow.Host/ListCommands":                 true,
+	"/stonesthrow.Host/GetKnownRefs":                 true,
+}
+
+// isRetriableRpcError returns true if |err| indicates that the RPC failed
+// because the server couldn't be reached. Errors returned by the server
+// aren't retriable.
+func isRetriableRpcError(err error) bool {
+	if _, ok := err.(ErrorWithDetails); ok {
+		return false
+	}
+	return grpc.Code(err) == codes.Unavailable
+}
+
+// waitForRetry waits before retry number |attempt|. Returns false if |ctx|
+// is done first.
+func waitForRetry(ctx context.Context, attempt int) bool {
+	delay := kRpcRetryInitialBackoff << uint(attempt)
+	if delay > kRpcRetryMaxBackoff {
+		delay = kRpcRetryMaxBackoff
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func retryingUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := unaryClientErrorInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+	for attempt := 0; err != nil && kIdempotentMethods[method] && isRetriableRpcError(err) && attempt < kMaxRpcRetries; attempt++ {
+		if !waitForRetry(ctx, attempt) {
+			break
+		}
+		err = unaryClientErrorInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+	}
+	return err
+}
+
+// retryingClientStream restarts a server streaming RPC if it fails before
+// anything is received.
+type retryingClientStream struct {
+	grpc.ClientStream
+
+	start    func() (grpc.ClientStream, error)
+	request  interface{}
+	received bool
+}
+
+func (s *retryingClientStream) SendMsg(m interface{}) error {
+	s.request = m
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *retryingClientStream) restart() error {
+	stream, err := s.start()
+	if err != nil {
+		return err
+	}
+	err = stream.SendMsg(s.request)
+	if err != nil {
+		return err
+	}
+	err = stream.CloseSend()
+	if err != nil {
+		return err
+	}
+	s.ClientStream = stream
+	return nil
+}
+
+func (s *retryingClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	for attempt := 0; err != nil && !s.received && isRetriableRpcError(err) && attempt < kMaxRpcRetries; attempt++ {
+		if !waitForRetry(s.Context(), attempt) {
+			break
+		}
+		err = s.restart()
+		if err == nil {
+			err = s.ClientStream.RecvMsg(m)
+		}
+	}
+	if err == nil {
+		s.received = true
+	}
+	return err
+}
+
+func retryingStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := func() (grpc.ClientStream, error) {
+		return streamClientErrorInterceptor(ctx, desc, cc, method, streamer, opts...)
+	}
+	if !kIdempotentMethods[method] || desc.ClientStreams {
+		return start()
+	}
+
+	stream, err := start()
+	for attempt := 0; err != nil && isRetriableRpcError(err) && attempt < kMaxRpcRetries; attempt++ {
+		if !waitForRetry(ctx, attempt) {
+			break
+		}
+		stream, err = start()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &retryingClientStream{ClientStream: stream, start: start}, nil
+}
@@ -0,0 +1,119 @@
+package stonesthrow
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"testing"
+)
+
+func TestRetryingUnaryClientInterceptor(t *testing.T) {
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		if calls < 3 {
+			return grpc.Errorf(codes.Unavailable, "connection lost")
+		}
+		return nil
+	}
+	err := retryingUnaryClientInterceptor(context.Background(), "/stonesthrow.Host/Ping", nil, nil, nil, invoker)
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success after 3 calls. got %v after %d", err, calls)
+	}
+
+	calls = 0
+	err = retryingUnaryClientInterceptor(context.Background(), "/stonesthrow.Host/Shutdown", nil, nil, nil, invoker)
+	if grpc.Code(err) != codes.Unavailable || calls != 1 {
+		t.Fatalf("non-idempotent RPCs shouldn't be retried. got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return ToRpcError(NewConnectionError("peer"), false)
+	}
+	err = retryingUnaryClientInterceptor(context.Background(), "/stonesthrow.Host/Ping", nil, nil, nil, invoker)
+	if !IsConnectionError(err) || calls != 1 {
+		t.Fatalf("errors returned by the server shouldn't be retried. got %v after %d calls", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return grpc.Errorf(codes.Unavailable, "connection lost")
+	}
+	retryingUnaryClientInterceptor(ctx, "/stonesthrow.Host/Ping", nil, nil, nil, invoker)
+	if calls != 1 {
+		t.Fatalf("cancelled RPCs shouldn't be retried. got %d calls", calls)
+	}
+}
+
+type fakeClientStream struct {
+	sent     []interface{}
+	received []error
+}
+
+func (s *fakeClientStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *fakeClientStream) Trailer() metadata.MD         { return nil }
+func (s *fakeClientStream) CloseSend() error             { return nil }
+func (s *fakeClientStream) Context() context.Context     { return context.Background() }
+func (s *fakeClientStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	err := s.received[0]
+	s.received = s.received[1:]
+	return err
+}
+
+func TestRetryingStreamClientInterceptor(t *testing.T) {
+	unavailable := grpc.Errorf(codes.Unavailable, "connection lost")
+	streams := []*fakeClientStream{
+		&fakeClientStream{received: []error{unavailable}},
+		&fakeClientStream{received: []error{nil, unavailable}}}
+	started := 0
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		started++
+		return streams[started-1], nil
+	}
+
+	desc := &grpc.StreamDesc{ServerStreams: true}
+	stream, err := retryingStreamClientInterceptor(context.Background(), desc, nil, "/stonesthrow.Host/ListJobs", streamer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := &ListJobsOptions{}
+	stream.SendMsg(request)
+	stream.CloseSend()
+
+	if err = stream.RecvMsg(&ListJobsResult{}); err != nil || started != 2 {
+		t.Fatalf("expected the stream to be restarted. got %v after %d starts", err, started)
+	}
+	if len(streams[1].sent) != 1 || streams[1].sent[0] != request {
+		t.Fatal("the request wasn't sent again")
+	}
+
+	// Once something has been received, the stream can't be restarted.
+	if err = stream.RecvMsg(&ListJobsResult{}); grpc.Code(err) != codes.Unavailable || started != 2 {
+		t.Fatalf("unexpected retry. got %v after %d starts", err, started)
+	}
+
+	started = 0
+	streams = []*fakeClientStream{&fakeClientStream{received: []error{unavailable}}}
+	stream, _ = retryingStreamClientInterceptor(context.Background(), desc, nil, "/stonesthrow.Host/KillJobs", streamer)
+	if err = stream.RecvMsg(&KillJobsResult{}); grpc.Code(err) != codes.Unavailable || started != 1 {
+		t.Fatalf("non-idempotent RPCs shouldn't be retried. got %v after %d starts", err, started)
+	}
+
+	started = 0
+	streams = []*fakeClientStream{&fakeClientStream{received: []error{io.EOF}}}
+	stream, _ = retryingStreamClientInterceptor(context.Background(), desc, nil, "/stonesthrow.Host/ListJobs", streamer)
+	if err = stream.RecvMsg(&ListJobsResult{}); err != io.EOF || started != 1 {
+		t.Fatalf("unexpected retry. got %v after %d starts", err, started)
+	}
+}
@@ -3,14 +3,19 @@ package stonesthrow
 import (
 	"context"
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
 type ScriptHost struct {
 	Config       Config
 	Script       Script
 	ProcessAdder ProcessAdder
+	Scheduler    *JobScheduler
+	Connections  *ConnectionPool
 }
 
 type ScriptConfig struct {
@@ -110,7 +115,9 @@ func (h ScriptHost) ListScriptCommands(ctx context.Context, e Executor) (*Comman
 	return command_list, nil
 }
 
-func (h ScriptHost) RunScriptCommand(ro *RunOptions, e Executor, s JobEventServer) error {
+// PrepareToRunScriptCommand validates |ro| and synchronizes the source
+// directory if the command depends on it.
+func (h ScriptHost) PrepareToRunScriptCommand(ro *RunOptions, e Executor, s JobEventServer) error {
 	if len(ro.GetCommand().GetCommand()) == 0 {
 		return NewInvalidArgumentError("no arguments specified for command")
 	}
@@ -126,16 +133,110 @@ func (h ScriptHost) RunScriptCommand(ro *RunOptions, e Executor, s JobEventServe
 	}
 
 	if needs_source {
-		repository_host := RepositoryHostServerImpl{Host: h.Config.Host, ProcessAdder: h.ProcessAdder}
+		repository_host := RepositoryHostServerImpl{
+			Host: h.Config.Host, ProcessAdder: h.ProcessAdder, Scheduler: h.Scheduler, Connections: h.Connections}
 		repository_state := RepositoryState{Repository: ro.Repository, Revision: ro.Revision}
-		err = repository_host.SyncRemote(&repository_state, s)
+		return repository_host.SyncRemote(&repository_state, s)
+	}
+	return nil
+}
+
+// scriptCommandCache remembers the commands supported by each script so that
+// the script doesn't need to be run every time a command is scheduled.
+// Entries are keyed by the command line that runs the script, and are
+// discarded once the script is modified, e.g. by a sync.
+type scriptCommandCache struct {
+	mutex   sync.Mutex
+	entries map[string]scriptCommandCacheEntry
+}
+
+type scriptCommandCacheEntry struct {
+	modified    time.Time
+	commandList *CommandList
+}
+
+var scriptCommands = scriptCommandCache{entries: make(map[string]scriptCommandCacheEntry)}
+
+// listCachedScriptCommands is like ListScriptCommands, but only runs the
+// script if it has changed since the commands were last listed.
+func (h ScriptHost) listCachedScriptCommands(ctx context.Context, e Executor) (*CommandList, error) {
+	runner, err := h.GetScriptRunner(e, nil)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.Join(runner.GetScriptRunnerCommand(), "\x00")
+	var modified time.Time
+	if info, err := os.Stat(filepath.Join(runner.ScriptPath, runner.ScriptName+".py")); err == nil {
+		modified = info.ModTime()
+	}
+
+	scriptCommands.mutex.Lock()
+	entry, ok := scriptCommands.entries[key]
+	scriptCommands.mutex.Unlock()
+	if ok && entry.modified.Equal(modified) {
+		return entry.commandList, nil
+	}
+
+	command_list, err := runner.ListCommands(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scriptCommands.mutex.Lock()
+	scriptCommands.entries[key] = scriptCommandCacheEntry{modified: modified, commandList: command_list}
+	scriptCommands.mutex.Unlock()
+	return command_list, nil
+}
+
+// IsHeavyScriptCommand returns true unless the script describes the command
+// named in |ro| as being light.
+func (h ScriptHost) IsHeavyScriptCommand(ctx context.Context, ro *RunOptions, e Executor) (bool, error) {
+	command_list, err := h.listCachedScriptCommands(ctx, e)
+	if err != nil {
+		return true, err
+	}
+	command_name := ro.GetCommand().GetCommand()[0]
+	for _, command := range command_list.GetCommand() {
+		for _, name := range command.GetName() {
+			if name == command_name {
+				return !command.GetLight(), nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// ExecuteScriptCommand runs the script command described by |ro|. Heavy
+// commands wait for the scheduler to admit them before they are started.
+func (h ScriptHost) ExecuteScriptCommand(ctx context.Context, ro *RunOptions, e Executor, s JobEventSender) error {
+	runner, err := h.GetScriptRunner(e, nil)
+	if err != nil {
+		return err
+	}
+
+	if h.Scheduler != nil {
+		heavy, err := h.IsHeavyScriptCommand(ctx, ro, e)
 		if err != nil {
 			return err
 		}
+		if heavy {
+			release, err := h.Scheduler.Acquire(ctx, h.Config.Platform.Name, h.Config.Platform.MaxHeavyJobs, s)
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
 	}
 
 	return runner.ExecuteInWorkDirPassthrough(
 		h.ExpandTokens(ro.GetCommand().GetDirectory()),
-		s.Context(),
+		ctx,
 		h.ExpandTokensInArray(ro.GetCommand().GetCommand())...)
 }
+
+func (h ScriptHost) RunScriptCommand(ro *RunOptions, e Executor, s JobEventServer) error {
+	err := h.PrepareToRunScriptCommand(ro, e, s)
+	if err != nil {
+		return err
+	}
+	return h.ExecuteScriptCommand(s.Context(), ro, e, s)
+}
@@ -1,44 +1,162 @@
 package stonesthrow
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"io/ioutil"
+	"log"
 	"net"
+	"os"
+	"path/filepath"
+	"time"
 )
 
+// loadCertPool returns a pool containing the certificates in |cert_file|.
+func loadCertPool(cert_file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(cert_file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, NewConfigurationError("no certificates found in %s", cert_file)
+	}
+	return pool, nil
+}
+
+// getCredentialsForHost returns the TLS credentials for the server on
+// |host_config|. Clients must present a certificate issued by the root CA.
 func getCredentialsForHost(host_config *HostConfig) (credentials.TransportCredentials, error) {
 	if host_config.Certificates == nil || host_config.Certificates.ServerCert == nil {
 		return nil, NewConfigIncompleteError("can't locate server key and certificate")
 	}
+	if host_config.Certificates.RootCert == nil {
+		return nil, NewConfigIncompleteError("can't locate the CA certificate for verifying clients")
+	}
 
-	return credentials.NewServerTLSFromFile(host_config.Certificates.ServerCert.CertificateFile,
+	server_cert, err := tls.LoadX509KeyPair(host_config.Certificates.ServerCert.CertificateFile,
 		host_config.Certificates.ServerCert.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	client_cas, err := loadCertPool(host_config.Certificates.RootCert.CertificateFile)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{server_cert},
+		ClientCAs:    client_cas,
+		ClientAuth:   tls.RequireAndVerifyClientCert}), nil
 }
 
 func RunServer(Config Config) error {
-	service_host_server := ServiceHostServerImpl{Config: Config}
-	repository_host_server := RepositoryHostServerImpl{Host: Config.Host, ProcessAdder: &service_host_server}
-	platform_build_server := BuildHostServerImpl{Host: Config.Host, ProcessAdder: &service_host_server}
-
-	creds, err := getCredentialsForHost(Config.Host)
+	job_registry := NewJobRegistry()
+	job_history, err := OpenJobHistory(Config.Host.GetStatePath(), Config.Host.JobHistory)
 	if err != nil {
 		return err
 	}
+	job_registry.SetHistory(job_history)
 
-	endpoint := Config.Host.GetEndpointOnHost(Config.Host)
-	if endpoint == nil {
-		return NewInvalidPlatformError("platform has no endpoint here")
+	err = os.MkdirAll(Config.Host.GetStatePath(), 0700)
+	if err != nil {
+		return err
 	}
-	listener, err := net.Listen(endpoint.Network, endpoint.Address)
+	audit_log, err := OpenAuditLog(filepath.Join(Config.Host.GetStatePath(), kAuditLogFile))
 	if err != nil {
 		return err
 	}
+	defer audit_log.Close()
 
-	server := grpc.NewServer(grpc.Creds(creds))
+	service_host_server := ServiceHostServerImpl{
+		Config: Config, Jobs: job_registry, Audit: audit_log,
+		Revision: GetBuildRevision(context.Background(), Config.Host)}
+	job_scheduler := NewJobScheduler(Config.Host.GetMaxHeavyJobs())
+	connection_pool := NewConnectionPool(time.Duration(Config.Host.ConnectionIdleMinutes) * time.Minute)
+	defer connection_pool.Close()
+	repository_host_server := RepositoryHostServerImpl{
+		Host: Config.Host, ProcessAdder: job_registry, Scheduler: job_scheduler, Connections: connection_pool}
+	platform_build_server := BuildHostServerImpl{
+		Host: Config.Host, ProcessAdder: job_registry, Scheduler: job_scheduler, Connections: connection_pool}
+
+	endpoints := Config.Host.GetEndpointsOnHost(Config.Host)
+	if len(endpoints) == 0 {
+		return NewInvalidPlatformError("platform has no endpoint here")
+	}
+
+	// TLS is only used for endpoints other than unix sockets.
+	var tls_creds credentials.TransportCredentials
+	for _, endpoint := range endpoints {
+		if endpoint.Network != "unix" {
+			tls_creds, err = getCredentialsForHost(Config.Host)
+			if err != nil {
+				return err
+			}
+			for _, warning := range GetCertificateExpiryWarnings(Config.Host.Certificates) {
+				log.Printf("Warning: %s", warning)
+			}
+			break
+		}
+	}
+
+	listeners := []net.Listener{}
+	for _, endpoint := range endpoints {
+		listener, err := listenOnEndpoint(endpoint)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	server := grpc.NewServer(append(RpcServerOptions(NewAuthorizer(Config.Host), audit_log), grpc.Creds(NewLocalTransportCredentials(tls_creds)))...)
 	RegisterServiceHostServer(server, &service_host_server)
 	RegisterRepositoryHostServer(server, &repository_host_server)
 	RegisterBuildHostServer(server, &platform_build_server)
+	RegisterHostServer(server, &HostServerImpl{
+		Service:    &service_host_server,
+		Repository: &repository_host_server,
+		Build:      &platform_build_server})
 	service_host_server.Server = server
 
-	return server.Serve(listener)
+	serve_errors := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			serve_errors <- server.Serve(listener)
+		}(listener)
+	}
+	return <-serve_errors
+}
+
+// listenOnEndpoint listens on |endpoint|. A unix socket left behind by a
+// server that's no longer running is replaced. Unix sockets are only
+// accessible to the user running the server.
+func listenOnEndpoint(endpoint Endpoint) (net.Listener, error) {
+	if endpoint.Network == "unix" {
+		if conn, err := net.Dial("unix", endpoint.Address); err == nil {
+			conn.Close()
+			return nil, NewConnectionError("%s is in use by another server", endpoint.Address)
+		}
+		if info, err := os.Lstat(endpoint.Address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(endpoint.Address)
+		}
+	}
+
+	listener, err := net.Listen(endpoint.Network, endpoint.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Network == "unix" {
+		err = os.Chmod(endpoint.Address, 0600)
+		if err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+	return listener, nil
 }
@@ -6,18 +6,28 @@ import (
 	"google.golang.org/grpc"
 	"os"
 	"os/exec"
-	"time"
+	"sync"
 )
 
+// ProcessAdder keeps track of jobs that are run on behalf of clients.
 type ProcessAdder interface {
-	AddProcess(command []string, process *os.Process)
-	RemoveProcess(process *os.Process, state *os.ProcessState)
+	AddJob(job *BuilderJob) *JobHandle
 }
 
 type ServiceHostServerImpl struct {
-	Config     Config
-	ProcessMap map[int]*BuilderJob
-	Server     *grpc.Server
+	Config   Config
+	Jobs     *JobRegistry
+	Server   *grpc.Server
+	Audit    *AuditLog
+	Revision string
+}
+
+func (h *ServiceHostServerImpl) Hello(ctx context.Context, ho *HelloOptions) (*HelloResult, error) {
+	err := CheckClientCompatibility(ho)
+	if err != nil {
+		return nil, err
+	}
+	return NewHelloResult(h.Config.Host, h.Revision), nil
 }
 
 func (h *ServiceHostServerImpl) Ping(ctx context.Context, po *PingOptions) (*PingResult, error) {
@@ -25,11 +35,99 @@ func (h *ServiceHostServerImpl) Ping(ctx context.Context, po *PingOptions) (*Pin
 }
 
 func (h *ServiceHostServerImpl) ListJobs(ctx context.Context, l *ListJobsOptions) (*BuilderJobs, error) {
-	return nil, NewNothingToDoError("not implemented")
+	return h.Jobs.ListJobs(l.GetIncludeCompleted()), nil
 }
 
-func (h *ServiceHostServerImpl) KillJobs(bj *KillJobsOptions, s ServiceHost_KillJobsServer) error {
-	return NewNothingToDoError("not implemented")
+func (h *ServiceHostServerImpl) KillJobs(ko *KillJobsOptions, s ServiceHost_KillJobsServer) error {
+	ids := ko.GetId()
+	if ko.GetAll() {
+		ids = nil
+		for _, job := range h.Jobs.ListJobs(false).GetJobs() {
+			// Child jobs are killed along with their parents.
+			if job.ParentId != 0 {
+				continue
+			}
+			ids = append(ids, job.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return NewNothingToDoError("no jobs to kill")
+	}
+
+	sender := NewSynchronizedJobEventSender(s)
+	var wait_group sync.WaitGroup
+	for _, id := range ids {
+		wait_group.Add(1)
+		go func(id int32) {
+			defer wait_group.Done()
+			job, err := h.Jobs.KillJob(s.Context(), id, kJobTerminationGracePeriod)
+			if err != nil {
+				sender.Send(&JobEvent{
+					Time: TimestampNow(),
+					LogEvent: &LogEvent{
+						Host:     h.Config.Host.Name,
+						Msg:      fmt.Sprintf("can't kill job %d: %s", id, err.Error()),
+						Severity: LogEvent_ERROR}})
+				return
+			}
+			sender.Send(&JobEvent{Time: TimestampNow(), Job: job})
+		}(id)
+	}
+	wait_group.Wait()
+	return nil
+}
+
+func (h *ServiceHostServerImpl) GetAuditLog(ctx context.Context, ao *AuditLogOptions) (*AuditRecords, error) {
+	if h.Audit == nil {
+		return nil, NewNothingToDoError("audit log is not available")
+	}
+	return h.Audit.Query(ao)
+}
+
+func (h *ServiceHostServerImpl) AttachJob(ao *AttachJobOptions, s ServiceHost_AttachJobServer) error {
+	return h.Jobs.AttachJob(s.Context(), ao.GetId(), s)
+}
+
+func (h *ServiceHostServerImpl) GetJobHistory(ctx context.Context, ho *JobHistoryOptions) (*BuilderJobs, error) {
+	history := h.Jobs.History()
+	if history == nil {
+		return nil, NewNothingToDoError("job history is not available")
+	}
+
+	jobs, err := history.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Most recent jobs first.
+	builder_jobs := &BuilderJobs{}
+	for i := len(jobs) - 1; i >= 0; i-- {
+		job := jobs[i]
+		if ho.GetRepository() != "" && job.GetRepository() != ho.GetRepository() {
+			continue
+		}
+		if ho.GetPlatform() != "" && job.GetPlatform() != ho.GetPlatform() {
+			continue
+		}
+		builder_jobs.Jobs = append(builder_jobs.Jobs, job)
+		if ho.GetMaxJobs() > 0 && len(builder_jobs.Jobs) >= int(ho.GetMaxJobs()) {
+			break
+		}
+	}
+	return builder_jobs, nil
+}
+
+func (h *ServiceHostServerImpl) GetJobLog(lo *JobLogOptions, s ServiceHost_GetJobLogServer) error {
+	history := h.Jobs.History()
+	if history == nil {
+		return NewNothingToDoError("job history is not available")
+	}
+	return history.ReadJobLog(lo.GetId(), s)
+}
+
+// stoppedState describes the server once it has been asked to stop.
+func (h *ServiceHostServerImpl) stoppedState() *RunState {
+	return &RunState{StartTime: NewTimestampFromTime(processStartTime), EndTime: TimestampNow()}
 }
 
 func (h *ServiceHostServerImpl) Shutdown(o *ShutdownOptions, s ServiceHost_ShutdownServer) error {
@@ -60,29 +158,3 @@ func (h *ServiceHostServerImpl) SelfUpdate(o *SelfUpdateOptions, s ServiceHost_S
 	}
 	return nil
 }
-
-func (h *ServiceHostServerImpl) AddProcess(command []string, process *os.Process) {
-	bj := BuilderJob{
-		Command: &ShellCommand{
-			Command:   command,
-			Directory: "",
-			Host:      h.Config.Host.Name},
-		State: &RunState{
-			StartTime: NewTimestampFromTime(time.Now()),
-			Running:   true}}
-	if h.ProcessMap == nil {
-		h.ProcessMap = make(map[int]*BuilderJob)
-	}
-	h.ProcessMap[process.Pid] = &bj
-}
-
-func (h *ServiceHostServerImpl) RemoveProcess(process *os.Process, state *os.ProcessState) {
-	p, ok := h.ProcessMap[process.Pid]
-	if !ok {
-		return
-	}
-	p.State.Running = false
-	p.State.EndTime = NewTimestampFromTime(time.Now())
-	p.SystemTime = NewDurationFromDuration(state.SystemTime())
-	p.UserTime = NewDurationFromDuration(state.UserTime())
-}
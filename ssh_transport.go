@@ -0,0 +1,346 @@
+package stonesthrow
+
+import (
+	"context"
+	"github.com/kevinburke/ssh_config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// kShellSafeCharacters are the characters that don't need to be quoted in
+// arguments to a POSIX shell.
+const kShellSafeCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.,/:=@+%"
+
+var kDefaultSshIdentityFiles = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+
+// SshHostConfig is the part of an ssh_config(5) host entry that the native
+// ssh transport understands.
+type SshHostConfig struct {
+	Address         string
+	User            string
+	IdentityFiles   []string
+	KnownHostsFiles []string
+}
+
+func expandHome(path string, home string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[1:])
+	}
+	return path
+}
+
+// ReadSshHostConfig looks up the settings for |alias| in the ssh config file
+// |config_file|. Paths starting with ~ are relative to |home|. Settings that
+// aren't specified have the same defaults as in ssh. The config file is
+// optional.
+func ReadSshHostConfig(alias string, config_file string, home string) (*SshHostConfig, error) {
+	config := &ssh_config.Config{}
+	data, err := ioutil.ReadFile(config_file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		config, err = ssh_config.DecodeBytes(data)
+		if err != nil {
+			return nil, NewConfigurationError("can't parse %s: %s", config_file, err.Error())
+		}
+	}
+
+	get := func(key string, default_value string) string {
+		value, _ := config.Get(alias, key)
+		if value == "" {
+			return default_value
+		}
+		return value
+	}
+
+	current_user := ""
+	if u, err := user.Current(); err == nil {
+		current_user = u.Username
+	}
+
+	host_config := &SshHostConfig{
+		Address: net.JoinHostPort(get("HostName", alias), get("Port", "22")),
+		User:    get("User", current_user)}
+
+	identity_files := kDefaultSshIdentityFiles
+	if identity_file := get("IdentityFile", ""); identity_file != "" {
+		identity_files = []string{identity_file}
+	}
+	for _, identity_file := range identity_files {
+		host_config.IdentityFiles = append(host_config.IdentityFiles, expandHome(identity_file, home))
+	}
+
+	for _, known_hosts := range strings.Fields(get("UserKnownHostsFile", "~/.ssh/known_hosts")) {
+		host_config.KnownHostsFiles = append(host_config.KnownHostsFiles, expandHome(known_hosts, home))
+	}
+	return host_config, nil
+}
+
+// placeholderHostKey is a host key that isn't known for any host.
+type placeholderHostKey struct{}
+
+func (placeholderHostKey) Type() string {
+	return "placeholder"
+}
+
+func (placeholderHostKey) Marshal() []byte {
+	return []byte("placeholder")
+}
+
+func (placeholderHostKey) Verify(data []byte, sig *ssh.Signature) error {
+	return NewInvalidArgumentError("placeholder keys can't verify signatures")
+}
+
+// hostKeyAlgorithms returns the algorithms of the keys that |callback| knows
+// for |address|, or nil if there aren't any. Otherwise the server may present
+// a key of another type which then can't be verified.
+func hostKeyAlgorithms(callback ssh.HostKeyCallback, address string) []string {
+	// Only |address| is used for matching as long as it's specified.
+	err := callback(address, &net.TCPAddr{IP: net.IPv4zero}, placeholderHostKey{})
+	key_error, ok := err.(*knownhosts.KeyError)
+	if !ok {
+		return nil
+	}
+
+	algorithms := []string{}
+	for _, known_key := range key_error.Want {
+		switch known_key.Key.Type() {
+		case ssh.KeyAlgoRSA:
+			// RSA keys are also used with SHA-2 signatures. Servers
+			// often don't allow SHA-1 anymore.
+			algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA)
+		default:
+			algorithms = append(algorithms, known_key.Key.Type())
+		}
+	}
+	if len(algorithms) == 0 {
+		return nil
+	}
+	return algorithms
+}
+
+// dialSshAgent returns a connection to the ssh agent, or nil if there isn't
+// one.
+func dialSshAgent() net.Conn {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil
+	}
+	agent_connection, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil
+	}
+	return agent_connection
+}
+
+// ClientConfig returns the configuration for connecting to the host. Keys are
+// taken from the ssh agent at |agent_connection|, if there is one, and the
+// identity files. |agent_connection| is only used during the handshake. Host
+// keys are verified against the known hosts files, and only keys of the types
+// listed there for the host are accepted.
+func (h *SshHostConfig) ClientConfig(agent_connection net.Conn) (*ssh.ClientConfig, error) {
+	auth_methods := []ssh.AuthMethod{}
+	if agent_connection != nil {
+		auth_methods = append(auth_methods, ssh.PublicKeysCallback(agent.NewClient(agent_connection).Signers))
+	}
+
+	signers := []ssh.Signer{}
+	for _, identity_file := range h.IdentityFiles {
+		key, err := ioutil.ReadFile(identity_file)
+		if err != nil {
+			continue
+		}
+		// Keys that are protected by a passphrase can only be used
+		// via the agent.
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) != 0 {
+		auth_methods = append(auth_methods, ssh.PublicKeys(signers...))
+	}
+	if len(auth_methods) == 0 {
+		return nil, NewConfigIncompleteError("no ssh agent or identity files for %s", h.Address)
+	}
+
+	known_hosts_files := []string{}
+	for _, known_hosts := range h.KnownHostsFiles {
+		if _, err := os.Stat(known_hosts); err == nil {
+			known_hosts_files = append(known_hosts_files, known_hosts)
+		}
+	}
+	if len(known_hosts_files) == 0 {
+		return nil, NewConfigIncompleteError("no known hosts file for verifying %s", h.Address)
+	}
+	host_key_callback, err := knownhosts.New(known_hosts_files...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:              h.User,
+		Auth:              auth_methods,
+		HostKeyCallback:   host_key_callback,
+		HostKeyAlgorithms: hostKeyAlgorithms(host_key_callback, h.Address)}, nil
+}
+
+// DialSsh establishes an ssh connection to the host.
+func (h *SshHostConfig) DialSsh(ctx context.Context) (*ssh.Client, error) {
+	agent_connection := dialSshAgent()
+	if agent_connection != nil {
+		defer agent_connection.Close()
+	}
+	client_config, err := h.ClientConfig(agent_connection)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", h.Address)
+	if err != nil {
+		return nil, NewConnectionError("%s: %s", h.Address, err.Error())
+	}
+	ssh_conn, channels, requests, err := ssh.NewClientConn(conn, h.Address, client_config)
+	if err != nil {
+		conn.Close()
+		return nil, NewConnectionError("%s: %s", h.Address, err.Error())
+	}
+	return ssh.NewClient(ssh_conn, channels, requests), nil
+}
+
+// sshClients holds the ssh connections established by the native ssh
+// transport. Each gRPC connection to a host uses a channel of the same ssh
+// connection.
+type sshClients struct {
+	mutex   sync.Mutex
+	clients map[string]*ssh.Client
+}
+
+var nativeSshClients = &sshClients{clients: make(map[string]*ssh.Client)}
+
+func (c *sshClients) get(ctx context.Context, ssh_host string) (*ssh.Client, error) {
+	c.mutex.Lock()
+	client, ok := c.clients[ssh_host]
+	c.mutex.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	// Connecting can take a while. Connections to other hosts shouldn't have
+	// to wait for it.
+	home := ""
+	if u, err := user.Current(); err == nil {
+		home = u.HomeDir
+	}
+	host_config, err := ReadSshHostConfig(ssh_host, filepath.Join(home, ".ssh", "config"), home)
+	if err != nil {
+		return nil, err
+	}
+	client, err = host_config.DialSsh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if existing, ok := c.clients[ssh_host]; ok {
+		// Another connection to the same host was established in the
+		// meantime.
+		client.Close()
+		return existing, nil
+	}
+	c.clients[ssh_host] = client
+
+	go func() {
+		client.Wait()
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if c.clients[ssh_host] == client {
+			delete(c.clients, ssh_host)
+		}
+	}()
+	return client, nil
+}
+
+// shellQuoteCommand returns |command| as a string that a POSIX shell splits
+// back into the same arguments.
+func shellQuoteCommand(command []string) string {
+	quoted := make([]string, 0, len(command))
+	for _, arg := range command {
+		if arg != "" && strings.Trim(arg, kShellSafeCharacters) == "" {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.Replace(arg, "'", `'\''`, -1)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// sshSessionConnection is a connection via the stdin and stdout of a command
+// running in an ssh session.
+type sshSessionConnection struct {
+	PipeConnection
+	session *ssh.Session
+}
+
+func (s sshSessionConnection) Close() error {
+	s.PipeConnection.Close()
+	return s.session.Close()
+}
+
+// DialViaSsh returns a connection to |server| via |client|. If |server| has
+// an endpoint on |remote|, the connection is a channel forwarded to it.
+// Otherwise a passthrough client is started on |remote|.
+func DialViaSsh(client *ssh.Client, remote *RemoteTransportConfig, server *Config) (net.Conn, error) {
+	if endpoint := server.Host.GetEndpointOnHost(remote.Host); endpoint != nil {
+		conn, err := client.Dial(endpoint.Network, endpoint.Address)
+		if err != nil {
+			return nil, NewConnectionError("%s can't reach %s: %s", remote.HostName, endpoint.Address, err.Error())
+		}
+		return conn, nil
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	writer, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	reader, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	session.Stderr = os.Stderr
+	err = session.Start(shellQuoteCommand(remote.GetPassthroughCommand(server)))
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	return sshSessionConnection{
+		PipeConnection: PipeConnection{reader: ioutil.NopCloser(reader), writer: writer},
+		session:        session}, nil
+}
+
+// dialNativeSsh returns a connection to |server| via |remote| using the
+// native ssh transport.
+func dialNativeSsh(ctx context.Context, remote *RemoteTransportConfig, server *Config) (net.Conn, error) {
+	client, err := nativeSshClients.get(ctx, remote.SshHost)
+	if err != nil {
+		return nil, err
+	}
+	return DialViaSsh(client, remote, server)
+}
@@ -0,0 +1,286 @@
+package stonesthrow
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestSigner(t *testing.T) (ssh.Signer, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func startEchoServer(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return listener
+}
+
+// testSshServer is a minimal sshd. It forwards direct-tcpip channels and runs
+// "passthrough" commands by echoing their stdin.
+type testSshServer struct {
+	listener net.Listener
+	config   *ssh.ServerConfig
+	commands chan string
+}
+
+func startTestSshServer(t *testing.T, host_key ssh.Signer, client_key ssh.PublicKey) *testSshServer {
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "tester" && bytes.Equal(key.Marshal(), client_key.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key for %s", conn.User())
+		}}
+	config.AddHostKey(host_key)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testSshServer{listener: listener, config: config, commands: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *testSshServer) serve(conn net.Conn) {
+	_, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for new_channel := range channels {
+		switch new_channel.ChannelType() {
+		case "direct-tcpip":
+			var target struct {
+				Host       string
+				Port       uint32
+				OriginHost string
+				OriginPort uint32
+			}
+			ssh.Unmarshal(new_channel.ExtraData(), &target)
+			target_conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+			if err != nil {
+				new_channel.Reject(ssh.ConnectionFailed, err.Error())
+				continue
+			}
+			channel, channel_requests, _ := new_channel.Accept()
+			go ssh.DiscardRequests(channel_requests)
+			go func() {
+				io.Copy(target_conn, channel)
+				target_conn.Close()
+			}()
+			go func() {
+				io.Copy(channel, target_conn)
+				channel.Close()
+			}()
+
+		case "session":
+			channel, channel_requests, _ := new_channel.Accept()
+			go func() {
+				for request := range channel_requests {
+					if request.Type != "exec" {
+						request.Reply(false, nil)
+						continue
+					}
+					var exec struct{ Command string }
+					ssh.Unmarshal(request.Payload, &exec)
+					request.Reply(true, nil)
+					s.commands <- exec.Command
+					go func() {
+						io.Copy(channel, channel)
+						channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+						channel.Close()
+					}()
+				}
+			}()
+
+		default:
+			new_channel.Reject(ssh.UnknownChannelType, "unsupported")
+		}
+	}
+}
+
+func expectEcho(t *testing.T, conn net.Conn) {
+	_, err := conn.Write([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := make([]byte, 5)
+	_, err = io.ReadFull(conn, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buffer) != "hello" {
+		t.Fatalf("unexpected response %q", buffer)
+	}
+}
+
+func TestSshTransport(t *testing.T) {
+	home, err := ioutil.TempDir("", "st-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if socket, ok := os.LookupEnv("SSH_AUTH_SOCK"); ok {
+		os.Unsetenv("SSH_AUTH_SOCK")
+		defer os.Setenv("SSH_AUTH_SOCK", socket)
+	}
+
+	host_key, _ := newTestSigner(t)
+	client_key, client_key_pem := newTestSigner(t)
+	sshd := startTestSshServer(t, host_key, client_key.PublicKey())
+	defer sshd.listener.Close()
+	echo := startEchoServer(t)
+	defer echo.Close()
+
+	sshd_address := sshd.listener.Addr().(*net.TCPAddr)
+	err = ioutil.WriteFile(filepath.Join(home, "id"), client_key_pem, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(home, "config"), []byte(fmt.Sprintf(`
+Host build
+  HostName 127.0.0.1
+  Port %d
+  User tester
+  IdentityFile ~/id
+  UserKnownHostsFile ~/known_hosts
+`, sshd_address.Port)), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host_config, err := ReadSshHostConfig("build", filepath.Join(home, "config"), home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host_config.Address != sshd_address.String() || host_config.User != "tester" ||
+		host_config.IdentityFiles[0] != filepath.Join(home, "id") {
+		t.Fatalf("unexpected config %#v", host_config)
+	}
+
+	// The host key must be known.
+	_, err = host_config.DialSsh(context.Background())
+	if !IsConfigIncompleteError(err) {
+		t.Fatalf("expected ConfigIncompleteError. got %v", err)
+	}
+	other_key, _ := newTestSigner(t)
+	known_hosts := filepath.Join(home, "known_hosts")
+	err = ioutil.WriteFile(known_hosts,
+		[]byte(knownhosts.Line([]string{knownhosts.Normalize(host_config.Address)}, other_key.PublicKey())+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = host_config.DialSsh(context.Background())
+	if !IsConnectionError(err) {
+		t.Fatalf("expected ConnectionError for a mismatched host key. got %v", err)
+	}
+
+	err = ioutil.WriteFile(known_hosts,
+		[]byte(knownhosts.Line([]string{knownhosts.Normalize(host_config.Address)}, host_key.PublicKey())+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only keys of the known type are accepted from the server.
+	client_config, err := host_config.ClientConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(client_config.HostKeyAlgorithms) != fmt.Sprint([]string{ssh.KeyAlgoECDSA256}) {
+		t.Fatalf("unexpected host key algorithms %v", client_config.HostKeyAlgorithms)
+	}
+	client, err := host_config.DialSsh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	remote_host := &HostConfig{Name: "build", GoPath: "/home/build/go path"}
+	remote := &RemoteTransportConfig{SshHost: "build", NativeSsh: true, HostName: "build", Host: remote_host}
+	server := &Config{
+		Host:       &HostConfig{Name: "server"},
+		Repository: &RepositoryConfig{Name: "chrome"},
+		Platform:   &PlatformConfig{Name: "linux"}}
+
+	// Without an endpoint on the remote host, a passthrough client is
+	// started there. The command is run by the remote shell.
+	conn, err := DialViaSsh(client, remote, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEcho(t, conn)
+	conn.Close()
+	command := <-sshd.commands
+	if command != "'/home/build/go path/bin/st_client' --platform linux --repository chrome passthrough" {
+		t.Fatalf("unexpected command %s", command)
+	}
+
+	// Otherwise the connection is forwarded to the endpoint. Both
+	// connections share the ssh connection.
+	server.Host.Endpoints = map[string][]Endpoint{
+		"build": []Endpoint{Endpoint{Network: "tcp", Address: echo.Addr().String(), HostName: "build", Host: remote_host}}}
+	for i := 0; i < 2; i++ {
+		conn, err = DialViaSsh(client, remote, server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		expectEcho(t, conn)
+	}
+}
+
+func TestShellQuoteCommand(t *testing.T) {
+	command := shellQuoteCommand([]string{"/go/bin/st_client", "--platform", "it's", "", "a;b"})
+	if command != `/go/bin/st_client --platform 'it'\''s' '' 'a;b'` {
+		t.Fatalf("unexpected command %s", command)
+	}
+}
@@ -23,6 +23,11 @@ It has these top-level messages:
 	JobEvent
 	BranchList
 	RunOptions
+	WindowSize
+	CommandInput
+	HelloOptions
+	HelloResult
+	ErrorDetails
 	PingOptions
 	PingResult
 	FetchFileOptions
@@ -34,8 +39,28 @@ It has these top-level messages:
 	TargetList
 	ListJobsOptions
 	KillJobsOptions
+	AttachJobOptions
+	JobHistoryOptions
+	JobLogOptions
 	ShutdownOptions
 	SelfUpdateOptions
+	AuditRecord
+	AuditRecords
+	AuditLogOptions
+	Task
+	TaskEvent
+	ListJobsResult
+	KillJobsResult
+	ShutdownResult
+	SelfUpdateResult
+	FetchFileResult
+	Revision
+	ExtractRefsOptions
+	ExtractRefsResult
+	ApplyRefsOptions
+	ApplyRefsResult
+	GetKnownRefsOptions
+	GetKnownRefsResult
 */
 package stonesthrow
 
@@ -64,20 +89,23 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type LogEvent_Severity int32
 
 const (
-	LogEvent_ERROR LogEvent_Severity = 0
-	LogEvent_INFO  LogEvent_Severity = 1
-	LogEvent_DEBUG LogEvent_Severity = 2
+	LogEvent_ERROR   LogEvent_Severity = 0
+	LogEvent_INFO    LogEvent_Severity = 1
+	LogEvent_DEBUG   LogEvent_Severity = 2
+	LogEvent_WARNING LogEvent_Severity = 3
 )
 
 var LogEvent_Severity_name = map[int32]string{
 	0: "ERROR",
 	1: "INFO",
 	2: "DEBUG",
+	3: "WARNING",
 }
 var LogEvent_Severity_value = map[string]int32{
-	"ERROR": 0,
-	"INFO":  1,
-	"DEBUG": 2,
+	"ERROR":   0,
+	"INFO":    1,
+	"DEBUG":   2,
+	"WARNING": 3,
 }
 
 func (x LogEvent_Severity) String() string {
@@ -133,6 +161,12 @@ type ShellCommand struct {
 	Command   []string `protobuf:"bytes,1,rep,name=command" json:"command,omitempty"`
 	Directory string   `protobuf:"bytes,2,opt,name=directory" json:"directory,omitempty"`
 	Host      string   `protobuf:"bytes,3,opt,name=host" json:"host,omitempty"`
+	// Environment variables to set for the command in addition to those
+	// inherited from the host.
+	Env map[string]string `protobuf:"bytes,4,rep,name=env" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Set if the command runs in a pseudo-terminal. Its output is then passed
+	// along as is.
+	Tty bool `protobuf:"varint,5,opt,name=tty" json:"tty,omitempty"`
 }
 
 func (m *ShellCommand) Reset()                    { *m = ShellCommand{} }
@@ -161,6 +195,20 @@ func (m *ShellCommand) GetHost() string {
 	return ""
 }
 
+func (m *ShellCommand) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *ShellCommand) GetTty() bool {
+	if m != nil {
+		return m.Tty
+	}
+	return false
+}
+
 type RepositoryState struct {
 	Repository string `protobuf:"bytes,2,opt,name=repository" json:"repository,omitempty"`
 	Revision   string `protobuf:"bytes,3,opt,name=revision" json:"revision,omitempty"`
@@ -186,9 +234,12 @@ func (m *RepositoryState) GetRevision() string {
 }
 
 type RunState struct {
-	StartTime *google_protobuf1.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
-	Running   bool                        `protobuf:"varint,2,opt,name=running" json:"running,omitempty"`
-	EndTime   *google_protobuf1.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime" json:"end_time,omitempty"`
+	StartTime  *google_protobuf1.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
+	Running    bool                        `protobuf:"varint,2,opt,name=running" json:"running,omitempty"`
+	EndTime    *google_protobuf1.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime" json:"end_time,omitempty"`
+	ReturnCode int32                       `protobuf:"varint,4,opt,name=return_code,json=returnCode" json:"return_code,omitempty"`
+	Signal     int32                       `protobuf:"varint,5,opt,name=signal" json:"signal,omitempty"`
+	Cancelled  bool                        `protobuf:"varint,6,opt,name=cancelled" json:"cancelled,omitempty"`
 }
 
 func (m *RunState) Reset()                    { *m = RunState{} }
@@ -217,12 +268,36 @@ func (m *RunState) GetEndTime() *google_protobuf1.Timestamp {
 	return nil
 }
 
+func (m *RunState) GetReturnCode() int32 {
+	if m != nil {
+		return m.ReturnCode
+	}
+	return 0
+}
+
+func (m *RunState) GetSignal() int32 {
+	if m != nil {
+		return m.Signal
+	}
+	return 0
+}
+
+func (m *RunState) GetCancelled() bool {
+	if m != nil {
+		return m.Cancelled
+	}
+	return false
+}
+
 type BuilderJob struct {
 	Id         int32                     `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
 	Command    *ShellCommand             `protobuf:"bytes,2,opt,name=command" json:"command,omitempty"`
 	State      *RunState                 `protobuf:"bytes,3,opt,name=state" json:"state,omitempty"`
 	SystemTime *google_protobuf.Duration `protobuf:"bytes,4,opt,name=system_time,json=systemTime" json:"system_time,omitempty"`
 	UserTime   *google_protobuf.Duration `protobuf:"bytes,5,opt,name=user_time,json=userTime" json:"user_time,omitempty"`
+	Repository string                    `protobuf:"bytes,6,opt,name=repository" json:"repository,omitempty"`
+	Platform   string                    `protobuf:"bytes,7,opt,name=platform" json:"platform,omitempty"`
+	ParentId   int32                     `protobuf:"varint,8,opt,name=parent_id,json=parentId" json:"parent_id,omitempty"`
 }
 
 func (m *BuilderJob) Reset()                    { *m = BuilderJob{} }
@@ -265,6 +340,27 @@ func (m *BuilderJob) GetUserTime() *google_protobuf.Duration {
 	return nil
 }
 
+func (m *BuilderJob) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *BuilderJob) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *BuilderJob) GetParentId() int32 {
+	if m != nil {
+		return m.ParentId
+	}
+	return 0
+}
+
 type BuilderJobs struct {
 	Jobs []*BuilderJob `protobuf:"bytes,1,rep,name=jobs" json:"jobs,omitempty"`
 }
@@ -427,6 +523,7 @@ func (m *LogEvent) GetSeverity() LogEvent_Severity {
 
 type BeginCommandEvent struct {
 	Command *ShellCommand `protobuf:"bytes,1,opt,name=command" json:"command,omitempty"`
+	JobId   int32         `protobuf:"varint,2,opt,name=job_id,json=jobId" json:"job_id,omitempty"`
 }
 
 func (m *BeginCommandEvent) Reset()                    { *m = BeginCommandEvent{} }
@@ -441,9 +538,20 @@ func (m *BeginCommandEvent) GetCommand() *ShellCommand {
 	return nil
 }
 
+func (m *BeginCommandEvent) GetJobId() int32 {
+	if m != nil {
+		return m.JobId
+	}
+	return 0
+}
+
 type CommandOutputEvent struct {
 	Stream CommandOutputEvent_Stream `protobuf:"varint,1,opt,name=stream,enum=stonesthrow.CommandOutputEvent_Stream" json:"stream,omitempty"`
-	Output string                    `protobuf:"bytes,2,opt,name=output" json:"output,omitempty"`
+	// A single line of output without the line terminator. Only sent by older
+	// hosts.
+	Output string `protobuf:"bytes,2,opt,name=output" json:"output,omitempty"`
+	// A chunk of raw output. Chunks don't necessarily end at line boundaries.
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 }
 
 func (m *CommandOutputEvent) Reset()                    { *m = CommandOutputEvent{} }
@@ -465,10 +573,21 @@ func (m *CommandOutputEvent) GetOutput() string {
 	return ""
 }
 
+func (m *CommandOutputEvent) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type EndCommandEvent struct {
 	ReturnCode int32                     `protobuf:"varint,1,opt,name=return_code,json=returnCode" json:"return_code,omitempty"`
 	SystemTime *google_protobuf.Duration `protobuf:"bytes,2,opt,name=system_time,json=systemTime" json:"system_time,omitempty"`
 	UserTime   *google_protobuf.Duration `protobuf:"bytes,3,opt,name=user_time,json=userTime" json:"user_time,omitempty"`
+	Cancelled  bool                      `protobuf:"varint,4,opt,name=cancelled" json:"cancelled,omitempty"`
+	Signal     int32                     `protobuf:"varint,5,opt,name=signal" json:"signal,omitempty"`
+	SignalName string                    `protobuf:"bytes,6,opt,name=signal_name,json=signalName" json:"signal_name,omitempty"`
+	TimedOut   bool                      `protobuf:"varint,7,opt,name=timed_out,json=timedOut" json:"timed_out,omitempty"`
 }
 
 func (m *EndCommandEvent) Reset()                    { *m = EndCommandEvent{} }
@@ -497,6 +616,34 @@ func (m *EndCommandEvent) GetUserTime() *google_protobuf.Duration {
 	return nil
 }
 
+func (m *EndCommandEvent) GetCancelled() bool {
+	if m != nil {
+		return m.Cancelled
+	}
+	return false
+}
+
+func (m *EndCommandEvent) GetSignal() int32 {
+	if m != nil {
+		return m.Signal
+	}
+	return 0
+}
+
+func (m *EndCommandEvent) GetSignalName() string {
+	if m != nil {
+		return m.SignalName
+	}
+	return ""
+}
+
+func (m *EndCommandEvent) GetTimedOut() bool {
+	if m != nil {
+		return m.TimedOut
+	}
+	return false
+}
+
 type GitBranchTaskEvent struct {
 	Branch   string                    `protobuf:"bytes,1,opt,name=branch" json:"branch,omitempty"`
 	Result   GitBranchTaskEvent_Result `protobuf:"varint,2,opt,name=result,enum=stonesthrow.GitBranchTaskEvent_Result" json:"result,omitempty"`
@@ -561,6 +708,7 @@ type JobEvent struct {
 	EndCommandEvent    *EndCommandEvent            `protobuf:"bytes,5,opt,name=end_command_event,json=endCommandEvent" json:"end_command_event,omitempty"`
 	BranchTaskEvent    *GitBranchTaskEvent         `protobuf:"bytes,6,opt,name=branch_task_event,json=branchTaskEvent" json:"branch_task_event,omitempty"`
 	ZippedContent      *ZippedContentEvent         `protobuf:"bytes,7,opt,name=zipped_content,json=zippedContent" json:"zipped_content,omitempty"`
+	Job                *BuilderJob                 `protobuf:"bytes,8,opt,name=job" json:"job,omitempty"`
 }
 
 func (m *JobEvent) Reset()                    { *m = JobEvent{} }
@@ -617,6 +765,13 @@ func (m *JobEvent) GetZippedContent() *ZippedContentEvent {
 	return nil
 }
 
+func (m *JobEvent) GetJob() *BuilderJob {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
 type BranchList struct {
 	Repository string   `protobuf:"bytes,1,opt,name=repository" json:"repository,omitempty"`
 	Branch     []string `protobuf:"bytes,2,rep,name=branch" json:"branch,omitempty"`
@@ -647,6 +802,13 @@ type RunOptions struct {
 	Platform     string        `protobuf:"bytes,3,opt,name=platform" json:"platform,omitempty"`
 	Dependencies *TargetList   `protobuf:"bytes,4,opt,name=dependencies" json:"dependencies,omitempty"`
 	Command      *ShellCommand `protobuf:"bytes,5,opt,name=command" json:"command,omitempty"`
+	Detach       bool          `protobuf:"varint,6,opt,name=detach" json:"detach,omitempty"`
+	// Run the command in a pseudo-terminal of size |window_size|.
+	Tty        bool        `protobuf:"varint,7,opt,name=tty" json:"tty,omitempty"`
+	WindowSize *WindowSize `protobuf:"bytes,8,opt,name=window_size,json=windowSize" json:"window_size,omitempty"`
+	// Run |command| as is instead of as a script command. Only used by
+	// Host.RunCommand.
+	Shell bool `protobuf:"varint,9,opt,name=shell" json:"shell,omitempty"`
 }
 
 func (m *RunOptions) Reset()                    { *m = RunOptions{} }
@@ -689,6 +851,258 @@ func (m *RunOptions) GetCommand() *ShellCommand {
 	return nil
 }
 
+func (m *RunOptions) GetDetach() bool {
+	if m != nil {
+		return m.Detach
+	}
+	return false
+}
+
+func (m *RunOptions) GetTty() bool {
+	if m != nil {
+		return m.Tty
+	}
+	return false
+}
+
+func (m *RunOptions) GetWindowSize() *WindowSize {
+	if m != nil {
+		return m.WindowSize
+	}
+	return nil
+}
+
+func (m *RunOptions) GetShell() bool {
+	if m != nil {
+		return m.Shell
+	}
+	return false
+}
+
+type WindowSize struct {
+	Rows    int32 `protobuf:"varint,1,opt,name=rows" json:"rows,omitempty"`
+	Columns int32 `protobuf:"varint,2,opt,name=columns" json:"columns,omitempty"`
+}
+
+func (m *WindowSize) Reset()                    { *m = WindowSize{} }
+func (m *WindowSize) String() string            { return proto.CompactTextString(m) }
+func (*WindowSize) ProtoMessage()               {}
+func (*WindowSize) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *WindowSize) GetRows() int32 {
+	if m != nil {
+		return m.Rows
+	}
+	return 0
+}
+
+func (m *WindowSize) GetColumns() int32 {
+	if m != nil {
+		return m.Columns
+	}
+	return 0
+}
+
+// Sent by clients of the *WithInput RPCs. The first message must contain the
+// options for the command. Subsequent messages carry the command's stdin.
+type CommandInput struct {
+	Options    *RunOptions `protobuf:"bytes,1,opt,name=options" json:"options,omitempty"`
+	Stdin      []byte      `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	CloseStdin bool        `protobuf:"varint,3,opt,name=close_stdin,json=closeStdin" json:"close_stdin,omitempty"`
+	WindowSize *WindowSize `protobuf:"bytes,4,opt,name=window_size,json=windowSize" json:"window_size,omitempty"`
+}
+
+func (m *CommandInput) Reset()                    { *m = CommandInput{} }
+func (m *CommandInput) String() string            { return proto.CompactTextString(m) }
+func (*CommandInput) ProtoMessage()               {}
+func (*CommandInput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *CommandInput) GetOptions() *RunOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *CommandInput) GetStdin() []byte {
+	if m != nil {
+		return m.Stdin
+	}
+	return nil
+}
+
+func (m *CommandInput) GetCloseStdin() bool {
+	if m != nil {
+		return m.CloseStdin
+	}
+	return false
+}
+
+func (m *CommandInput) GetWindowSize() *WindowSize {
+	if m != nil {
+		return m.WindowSize
+	}
+	return nil
+}
+
+type HelloOptions struct {
+	// The protocol versions that the client can speak.
+	ProtocolVersion    int32  `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion" json:"protocol_version,omitempty"`
+	MinProtocolVersion int32  `protobuf:"varint,2,opt,name=min_protocol_version,json=minProtocolVersion" json:"min_protocol_version,omitempty"`
+	Version            string `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	Revision           string `protobuf:"bytes,4,opt,name=revision" json:"revision,omitempty"`
+}
+
+func (m *HelloOptions) Reset()                    { *m = HelloOptions{} }
+func (m *HelloOptions) String() string            { return proto.CompactTextString(m) }
+func (*HelloOptions) ProtoMessage()               {}
+func (*HelloOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *HelloOptions) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *HelloOptions) GetMinProtocolVersion() int32 {
+	if m != nil {
+		return m.MinProtocolVersion
+	}
+	return 0
+}
+
+func (m *HelloOptions) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *HelloOptions) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+type HelloResult struct {
+	// The protocol versions that the server can speak.
+	ProtocolVersion    int32  `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion" json:"protocol_version,omitempty"`
+	MinProtocolVersion int32  `protobuf:"varint,2,opt,name=min_protocol_version,json=minProtocolVersion" json:"min_protocol_version,omitempty"`
+	Version            string `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	// The git revision that the server was built from, if known.
+	Revision string `protobuf:"bytes,4,opt,name=revision" json:"revision,omitempty"`
+	// Optional features and RPCs that the server supports.
+	Capabilities []string                    `protobuf:"bytes,5,rep,name=capabilities" json:"capabilities,omitempty"`
+	Host         string                      `protobuf:"bytes,6,opt,name=host" json:"host,omitempty"`
+	Os           string                      `protobuf:"bytes,7,opt,name=os" json:"os,omitempty"`
+	Arch         string                      `protobuf:"bytes,8,opt,name=arch" json:"arch,omitempty"`
+	StartTime    *google_protobuf1.Timestamp `protobuf:"bytes,9,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
+}
+
+func (m *HelloResult) Reset()                    { *m = HelloResult{} }
+func (m *HelloResult) String() string            { return proto.CompactTextString(m) }
+func (*HelloResult) ProtoMessage()               {}
+func (*HelloResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *HelloResult) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *HelloResult) GetMinProtocolVersion() int32 {
+	if m != nil {
+		return m.MinProtocolVersion
+	}
+	return 0
+}
+
+func (m *HelloResult) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *HelloResult) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+func (m *HelloResult) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *HelloResult) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *HelloResult) GetOs() string {
+	if m != nil {
+		return m.Os
+	}
+	return ""
+}
+
+func (m *HelloResult) GetArch() string {
+	if m != nil {
+		return m.Arch
+	}
+	return ""
+}
+
+func (m *HelloResult) GetStartTime() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+// Attached to the status of an RPC that failed due to an ErrorWithDetails.
+type ErrorDetails struct {
+	ErrorClass string `protobuf:"bytes,1,opt,name=error_class,json=errorClass" json:"error_class,omitempty"`
+	Details    string `protobuf:"bytes,2,opt,name=details" json:"details,omitempty"`
+	// Only included if the client asks for it.
+	Stack []byte `protobuf:"bytes,3,opt,name=stack,proto3" json:"stack,omitempty"`
+}
+
+func (m *ErrorDetails) Reset()                    { *m = ErrorDetails{} }
+func (m *ErrorDetails) String() string            { return proto.CompactTextString(m) }
+func (*ErrorDetails) ProtoMessage()               {}
+func (*ErrorDetails) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *ErrorDetails) GetErrorClass() string {
+	if m != nil {
+		return m.ErrorClass
+	}
+	return ""
+}
+
+func (m *ErrorDetails) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+func (m *ErrorDetails) GetStack() []byte {
+	if m != nil {
+		return m.Stack
+	}
+	return nil
+}
+
 type PingOptions struct {
 	Ping string `protobuf:"bytes,1,opt,name=ping" json:"ping,omitempty"`
 }
@@ -696,7 +1110,7 @@ type PingOptions struct {
 func (m *PingOptions) Reset()                    { *m = PingOptions{} }
 func (m *PingOptions) String() string            { return proto.CompactTextString(m) }
 func (*PingOptions) ProtoMessage()               {}
-func (*PingOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*PingOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *PingOptions) GetPing() string {
 	if m != nil {
@@ -712,7 +1126,7 @@ type PingResult struct {
 func (m *PingResult) Reset()                    { *m = PingResult{} }
 func (m *PingResult) String() string            { return proto.CompactTextString(m) }
 func (*PingResult) ProtoMessage()               {}
-func (*PingResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*PingResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *PingResult) GetPong() string {
 	if m != nil {
@@ -732,7 +1146,7 @@ type FetchFileOptions struct {
 func (m *FetchFileOptions) Reset()                    { *m = FetchFileOptions{} }
 func (m *FetchFileOptions) String() string            { return proto.CompactTextString(m) }
 func (*FetchFileOptions) ProtoMessage()               {}
-func (*FetchFileOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*FetchFileOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *FetchFileOptions) GetRepository() string {
 	if m != nil {
@@ -778,7 +1192,7 @@ type BranchConfigOptions struct {
 func (m *BranchConfigOptions) Reset()                    { *m = BranchConfigOptions{} }
 func (m *BranchConfigOptions) String() string            { return proto.CompactTextString(m) }
 func (*BranchConfigOptions) ProtoMessage()               {}
-func (*BranchConfigOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*BranchConfigOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *BranchConfigOptions) GetRepository() string {
 	if m != nil {
@@ -809,7 +1223,7 @@ type ListCommandsOptions struct {
 func (m *ListCommandsOptions) Reset()                    { *m = ListCommandsOptions{} }
 func (m *ListCommandsOptions) String() string            { return proto.CompactTextString(m) }
 func (*ListCommandsOptions) ProtoMessage()               {}
-func (*ListCommandsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*ListCommandsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *ListCommandsOptions) GetRepository() string {
 	if m != nil {
@@ -831,12 +1245,13 @@ type Command struct {
 	Usage           string   `protobuf:"bytes,3,opt,name=usage" json:"usage,omitempty"`
 	DependsOnSource bool     `protobuf:"varint,4,opt,name=depends_on_source,json=dependsOnSource" json:"depends_on_source,omitempty"`
 	Visible         bool     `protobuf:"varint,5,opt,name=visible" json:"visible,omitempty"`
+	Light           bool     `protobuf:"varint,6,opt,name=light" json:"light,omitempty"`
 }
 
 func (m *Command) Reset()                    { *m = Command{} }
 func (m *Command) String() string            { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()               {}
-func (*Command) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*Command) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *Command) GetName() []string {
 	if m != nil {
@@ -873,6 +1288,13 @@ func (m *Command) GetVisible() bool {
 	return false
 }
 
+func (m *Command) GetLight() bool {
+	if m != nil {
+		return m.Light
+	}
+	return false
+}
+
 type CommandList struct {
 	Command []*Command `protobuf:"bytes,1,rep,name=command" json:"command,omitempty"`
 }
@@ -880,7 +1302,7 @@ type CommandList struct {
 func (m *CommandList) Reset()                    { *m = CommandList{} }
 func (m *CommandList) String() string            { return proto.CompactTextString(m) }
 func (*CommandList) ProtoMessage()               {}
-func (*CommandList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*CommandList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 func (m *CommandList) GetCommand() []*Command {
 	if m != nil {
@@ -897,7 +1319,7 @@ type ListTargetsOptions struct {
 func (m *ListTargetsOptions) Reset()                    { *m = ListTargetsOptions{} }
 func (m *ListTargetsOptions) String() string            { return proto.CompactTextString(m) }
 func (*ListTargetsOptions) ProtoMessage()               {}
-func (*ListTargetsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*ListTargetsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *ListTargetsOptions) GetRepository() string {
 	if m != nil {
@@ -920,7 +1342,7 @@ type TargetList struct {
 func (m *TargetList) Reset()                    { *m = TargetList{} }
 func (m *TargetList) String() string            { return proto.CompactTextString(m) }
 func (*TargetList) ProtoMessage()               {}
-func (*TargetList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*TargetList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
 func (m *TargetList) GetTarget() []string {
 	if m != nil {
@@ -930,21 +1352,30 @@ func (m *TargetList) GetTarget() []string {
 }
 
 type ListJobsOptions struct {
+	IncludeCompleted bool `protobuf:"varint,1,opt,name=include_completed,json=includeCompleted" json:"include_completed,omitempty"`
 }
 
 func (m *ListJobsOptions) Reset()                    { *m = ListJobsOptions{} }
 func (m *ListJobsOptions) String() string            { return proto.CompactTextString(m) }
 func (*ListJobsOptions) ProtoMessage()               {}
-func (*ListJobsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (*ListJobsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *ListJobsOptions) GetIncludeCompleted() bool {
+	if m != nil {
+		return m.IncludeCompleted
+	}
+	return false
+}
 
 type KillJobsOptions struct {
-	Id []int32 `protobuf:"varint,1,rep,packed,name=id" json:"id,omitempty"`
+	Id  []int32 `protobuf:"varint,1,rep,packed,name=id" json:"id,omitempty"`
+	All bool    `protobuf:"varint,2,opt,name=all" json:"all,omitempty"`
 }
 
 func (m *KillJobsOptions) Reset()                    { *m = KillJobsOptions{} }
 func (m *KillJobsOptions) String() string            { return proto.CompactTextString(m) }
 func (*KillJobsOptions) ProtoMessage()               {}
-func (*KillJobsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+func (*KillJobsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 func (m *KillJobsOptions) GetId() []int32 {
 	if m != nil {
@@ -953,28 +1384,715 @@ func (m *KillJobsOptions) GetId() []int32 {
 	return nil
 }
 
-type ShutdownOptions struct {
+func (m *KillJobsOptions) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
 }
 
-func (m *ShutdownOptions) Reset()                    { *m = ShutdownOptions{} }
-func (m *ShutdownOptions) String() string            { return proto.CompactTextString(m) }
-func (*ShutdownOptions) ProtoMessage()               {}
-func (*ShutdownOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
-
-type SelfUpdateOptions struct {
+type AttachJobOptions struct {
+	Id int32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
 }
 
-func (m *SelfUpdateOptions) Reset()                    { *m = SelfUpdateOptions{} }
-func (m *SelfUpdateOptions) String() string            { return proto.CompactTextString(m) }
-func (*SelfUpdateOptions) ProtoMessage()               {}
-func (*SelfUpdateOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (m *AttachJobOptions) Reset()                    { *m = AttachJobOptions{} }
+func (m *AttachJobOptions) String() string            { return proto.CompactTextString(m) }
+func (*AttachJobOptions) ProtoMessage()               {}
+func (*AttachJobOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
 
-func init() {
-	proto.RegisterType((*ShellCommand)(nil), "stonesthrow.ShellCommand")
-	proto.RegisterType((*RepositoryState)(nil), "stonesthrow.RepositoryState")
-	proto.RegisterType((*RunState)(nil), "stonesthrow.RunState")
-	proto.RegisterType((*BuilderJob)(nil), "stonesthrow.BuilderJob")
-	proto.RegisterType((*BuilderJobs)(nil), "stonesthrow.BuilderJobs")
+func (m *AttachJobOptions) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type JobHistoryOptions struct {
+	Repository string `protobuf:"bytes,1,opt,name=repository" json:"repository,omitempty"`
+	Platform   string `protobuf:"bytes,2,opt,name=platform" json:"platform,omitempty"`
+	MaxJobs    int32  `protobuf:"varint,3,opt,name=max_jobs,json=maxJobs" json:"max_jobs,omitempty"`
+}
+
+func (m *JobHistoryOptions) Reset()                    { *m = JobHistoryOptions{} }
+func (m *JobHistoryOptions) String() string            { return proto.CompactTextString(m) }
+func (*JobHistoryOptions) ProtoMessage()               {}
+func (*JobHistoryOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *JobHistoryOptions) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *JobHistoryOptions) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *JobHistoryOptions) GetMaxJobs() int32 {
+	if m != nil {
+		return m.MaxJobs
+	}
+	return 0
+}
+
+type JobLogOptions struct {
+	Id int32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *JobLogOptions) Reset()                    { *m = JobLogOptions{} }
+func (m *JobLogOptions) String() string            { return proto.CompactTextString(m) }
+func (*JobLogOptions) ProtoMessage()               {}
+func (*JobLogOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *JobLogOptions) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type ShutdownOptions struct {
+}
+
+func (m *ShutdownOptions) Reset()                    { *m = ShutdownOptions{} }
+func (m *ShutdownOptions) String() string            { return proto.CompactTextString(m) }
+func (*ShutdownOptions) ProtoMessage()               {}
+func (*ShutdownOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+type SelfUpdateOptions struct {
+}
+
+func (m *SelfUpdateOptions) Reset()                    { *m = SelfUpdateOptions{} }
+func (m *SelfUpdateOptions) String() string            { return proto.CompactTextString(m) }
+func (*SelfUpdateOptions) ProtoMessage()               {}
+func (*SelfUpdateOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+// An entry in a server's audit log. Each RPC is recorded once when the
+// request arrives and once when it finishes.
+type AuditRecord struct {
+	Time *google_protobuf1.Timestamp `protobuf:"bytes,1,opt,name=time" json:"time,omitempty"`
+	// The common name of the client's certificate, or "uid:<uid>" for clients
+	// that connect via unix sockets. Empty if the client couldn't be
+	// identified.
+	Identity   string   `protobuf:"bytes,2,opt,name=identity" json:"identity,omitempty"`
+	Address    string   `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	Method     string   `protobuf:"bytes,4,opt,name=method" json:"method,omitempty"`
+	Repository string   `protobuf:"bytes,5,opt,name=repository" json:"repository,omitempty"`
+	Platform   string   `protobuf:"bytes,6,opt,name=platform" json:"platform,omitempty"`
+	Command    []string `protobuf:"bytes,7,rep,name=command" json:"command,omitempty"`
+	Shell      bool     `protobuf:"varint,8,opt,name=shell" json:"shell,omitempty"`
+	// Jobs that were started or affected by the RPC.
+	JobId []int32 `protobuf:"varint,9,rep,packed,name=job_id,json=jobId" json:"job_id,omitempty"`
+	// The status code and the error, if any, returned by the RPC.
+	Code  string `protobuf:"bytes,10,opt,name=code" json:"code,omitempty"`
+	Error string `protobuf:"bytes,11,opt,name=error" json:"error,omitempty"`
+	// Set for the record written when the request arrives. That record only
+	// lists the jobs named in the request. Jobs started by the RPC are listed
+	// once it finishes.
+	Started bool `protobuf:"varint,12,opt,name=started" json:"started,omitempty"`
+	// Identifies the RPC among those served by the same server process. Both
+	// records for an RPC have the same ID.
+	RpcId int64 `protobuf:"varint,13,opt,name=rpc_id,json=rpcId" json:"rpc_id,omitempty"`
+}
+
+func (m *AuditRecord) Reset()                    { *m = AuditRecord{} }
+func (m *AuditRecord) String() string            { return proto.CompactTextString(m) }
+func (*AuditRecord) ProtoMessage()               {}
+func (*AuditRecord) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *AuditRecord) GetTime() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetShell() bool {
+	if m != nil {
+		return m.Shell
+	}
+	return false
+}
+
+func (m *AuditRecord) GetJobId() []int32 {
+	if m != nil {
+		return m.JobId
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetStarted() bool {
+	if m != nil {
+		return m.Started
+	}
+	return false
+}
+
+func (m *AuditRecord) GetRpcId() int64 {
+	if m != nil {
+		return m.RpcId
+	}
+	return 0
+}
+
+type AuditRecords struct {
+	Record []*AuditRecord `protobuf:"bytes,1,rep,name=record" json:"record,omitempty"`
+}
+
+func (m *AuditRecords) Reset()                    { *m = AuditRecords{} }
+func (m *AuditRecords) String() string            { return proto.CompactTextString(m) }
+func (*AuditRecords) ProtoMessage()               {}
+func (*AuditRecords) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *AuditRecords) GetRecord() []*AuditRecord {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+// Selects the audit records to return. Empty fields match all records.
+type AuditLogOptions struct {
+	Identity string `protobuf:"bytes,1,opt,name=identity" json:"identity,omitempty"`
+	// Matches methods that contain this string. E.g. "Shutdown".
+	Method     string                      `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
+	Repository string                      `protobuf:"bytes,3,opt,name=repository" json:"repository,omitempty"`
+	Platform   string                      `protobuf:"bytes,4,opt,name=platform" json:"platform,omitempty"`
+	Since      *google_protobuf1.Timestamp `protobuf:"bytes,5,opt,name=since" json:"since,omitempty"`
+	FailedOnly bool                        `protobuf:"varint,6,opt,name=failed_only,json=failedOnly" json:"failed_only,omitempty"`
+	MaxRecords int32                       `protobuf:"varint,7,opt,name=max_records,json=maxRecords" json:"max_records,omitempty"`
+}
+
+func (m *AuditLogOptions) Reset()                    { *m = AuditLogOptions{} }
+func (m *AuditLogOptions) String() string            { return proto.CompactTextString(m) }
+func (*AuditLogOptions) ProtoMessage()               {}
+func (*AuditLogOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *AuditLogOptions) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *AuditLogOptions) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditLogOptions) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *AuditLogOptions) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *AuditLogOptions) GetSince() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *AuditLogOptions) GetFailedOnly() bool {
+	if m != nil {
+		return m.FailedOnly
+	}
+	return false
+}
+
+func (m *AuditLogOptions) GetMaxRecords() int32 {
+	if m != nil {
+		return m.MaxRecords
+	}
+	return 0
+}
+
+// A task is a job as seen by clients of the Host service.
+type Task struct {
+	Id         int32                     `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Command    *ShellCommand             `protobuf:"bytes,2,opt,name=command" json:"command,omitempty"`
+	State      *RunState                 `protobuf:"bytes,3,opt,name=state" json:"state,omitempty"`
+	Host       string                    `protobuf:"bytes,4,opt,name=host" json:"host,omitempty"`
+	ParentId   int32                     `protobuf:"varint,5,opt,name=parent_id,json=parentId" json:"parent_id,omitempty"`
+	Repository string                    `protobuf:"bytes,6,opt,name=repository" json:"repository,omitempty"`
+	Platform   string                    `protobuf:"bytes,7,opt,name=platform" json:"platform,omitempty"`
+	SystemTime *google_protobuf.Duration `protobuf:"bytes,8,opt,name=system_time,json=systemTime" json:"system_time,omitempty"`
+	UserTime   *google_protobuf.Duration `protobuf:"bytes,9,opt,name=user_time,json=userTime" json:"user_time,omitempty"`
+	Cancelled  bool                      `protobuf:"varint,10,opt,name=cancelled" json:"cancelled,omitempty"`
+	TimedOut   bool                      `protobuf:"varint,11,opt,name=timed_out,json=timedOut" json:"timed_out,omitempty"`
+	SignalName string                    `protobuf:"bytes,12,opt,name=signal_name,json=signalName" json:"signal_name,omitempty"`
+}
+
+func (m *Task) Reset()                    { *m = Task{} }
+func (m *Task) String() string            { return proto.CompactTextString(m) }
+func (*Task) ProtoMessage()               {}
+func (*Task) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *Task) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Task) GetCommand() *ShellCommand {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *Task) GetState() *RunState {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *Task) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Task) GetParentId() int32 {
+	if m != nil {
+		return m.ParentId
+	}
+	return 0
+}
+
+func (m *Task) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *Task) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *Task) GetSystemTime() *google_protobuf.Duration {
+	if m != nil {
+		return m.SystemTime
+	}
+	return nil
+}
+
+func (m *Task) GetUserTime() *google_protobuf.Duration {
+	if m != nil {
+		return m.UserTime
+	}
+	return nil
+}
+
+func (m *Task) GetCancelled() bool {
+	if m != nil {
+		return m.Cancelled
+	}
+	return false
+}
+
+func (m *Task) GetTimedOut() bool {
+	if m != nil {
+		return m.TimedOut
+	}
+	return false
+}
+
+func (m *Task) GetSignalName() string {
+	if m != nil {
+		return m.SignalName
+	}
+	return ""
+}
+
+// Events sent by the Host service. A task that is running has started. One
+// that isn't has completed.
+type TaskEvent struct {
+	Time       *google_protobuf1.Timestamp `protobuf:"bytes,1,opt,name=time" json:"time,omitempty"`
+	Host       string                      `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
+	Log        []*LogEvent                 `protobuf:"bytes,3,rep,name=log" json:"log,omitempty"`
+	Task       []*Task                     `protobuf:"bytes,4,rep,name=task" json:"task,omitempty"`
+	Output     []*CommandOutputEvent       `protobuf:"bytes,5,rep,name=output" json:"output,omitempty"`
+	BranchTask []*GitBranchTaskEvent       `protobuf:"bytes,6,rep,name=branch_task,json=branchTask" json:"branch_task,omitempty"`
+}
+
+func (m *TaskEvent) Reset()                    { *m = TaskEvent{} }
+func (m *TaskEvent) String() string            { return proto.CompactTextString(m) }
+func (*TaskEvent) ProtoMessage()               {}
+func (*TaskEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *TaskEvent) GetTime() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *TaskEvent) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetLog() []*LogEvent {
+	if m != nil {
+		return m.Log
+	}
+	return nil
+}
+
+func (m *TaskEvent) GetTask() []*Task {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
+func (m *TaskEvent) GetOutput() []*CommandOutputEvent {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *TaskEvent) GetBranchTask() []*GitBranchTaskEvent {
+	if m != nil {
+		return m.BranchTask
+	}
+	return nil
+}
+
+type ListJobsResult struct {
+	Task []*Task `protobuf:"bytes,1,rep,name=task" json:"task,omitempty"`
+}
+
+func (m *ListJobsResult) Reset()                    { *m = ListJobsResult{} }
+func (m *ListJobsResult) String() string            { return proto.CompactTextString(m) }
+func (*ListJobsResult) ProtoMessage()               {}
+func (*ListJobsResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ListJobsResult) GetTask() []*Task {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
+type KillJobsResult struct {
+	Task []*Task     `protobuf:"bytes,1,rep,name=task" json:"task,omitempty"`
+	Log  []*LogEvent `protobuf:"bytes,2,rep,name=log" json:"log,omitempty"`
+}
+
+func (m *KillJobsResult) Reset()                    { *m = KillJobsResult{} }
+func (m *KillJobsResult) String() string            { return proto.CompactTextString(m) }
+func (*KillJobsResult) ProtoMessage()               {}
+func (*KillJobsResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *KillJobsResult) GetTask() []*Task {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
+func (m *KillJobsResult) GetLog() []*LogEvent {
+	if m != nil {
+		return m.Log
+	}
+	return nil
+}
+
+type ShutdownResult struct {
+	State *RunState `protobuf:"bytes,1,opt,name=state" json:"state,omitempty"`
+}
+
+func (m *ShutdownResult) Reset()                    { *m = ShutdownResult{} }
+func (m *ShutdownResult) String() string            { return proto.CompactTextString(m) }
+func (*ShutdownResult) ProtoMessage()               {}
+func (*ShutdownResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *ShutdownResult) GetState() *RunState {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+type SelfUpdateResult struct {
+	State *RunState `protobuf:"bytes,1,opt,name=state" json:"state,omitempty"`
+}
+
+func (m *SelfUpdateResult) Reset()                    { *m = SelfUpdateResult{} }
+func (m *SelfUpdateResult) String() string            { return proto.CompactTextString(m) }
+func (*SelfUpdateResult) ProtoMessage()               {}
+func (*SelfUpdateResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *SelfUpdateResult) GetState() *RunState {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+type FetchFileResult struct {
+	// A chunk of a zip archive containing the requested files.
+	Data []byte      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Log  []*LogEvent `protobuf:"bytes,2,rep,name=log" json:"log,omitempty"`
+}
+
+func (m *FetchFileResult) Reset()                    { *m = FetchFileResult{} }
+func (m *FetchFileResult) String() string            { return proto.CompactTextString(m) }
+func (*FetchFileResult) ProtoMessage()               {}
+func (*FetchFileResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *FetchFileResult) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *FetchFileResult) GetLog() []*LogEvent {
+	if m != nil {
+		return m.Log
+	}
+	return nil
+}
+
+type Revision struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *Revision) Reset()                    { *m = Revision{} }
+func (m *Revision) String() string            { return proto.CompactTextString(m) }
+func (*Revision) ProtoMessage()               {}
+func (*Revision) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *Revision) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Revision) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ExtractRefsOptions struct {
+	Repository string    `protobuf:"bytes,1,opt,name=repository" json:"repository,omitempty"`
+	Revision   *Revision `protobuf:"bytes,2,opt,name=revision" json:"revision,omitempty"`
+	// Revisions that the recipient already has. Objects reachable from these
+	// aren't included.
+	BaseRevision []*Revision `protobuf:"bytes,3,rep,name=base_revision,json=baseRevision" json:"base_revision,omitempty"`
+}
+
+func (m *ExtractRefsOptions) Reset()                    { *m = ExtractRefsOptions{} }
+func (m *ExtractRefsOptions) String() string            { return proto.CompactTextString(m) }
+func (*ExtractRefsOptions) ProtoMessage()               {}
+func (*ExtractRefsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *ExtractRefsOptions) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *ExtractRefsOptions) GetRevision() *Revision {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+func (m *ExtractRefsOptions) GetBaseRevision() []*Revision {
+	if m != nil {
+		return m.BaseRevision
+	}
+	return nil
+}
+
+type ExtractRefsResult struct {
+	// A chunk of a git bundle.
+	Bundle []byte `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+}
+
+func (m *ExtractRefsResult) Reset()                    { *m = ExtractRefsResult{} }
+func (m *ExtractRefsResult) String() string            { return proto.CompactTextString(m) }
+func (*ExtractRefsResult) ProtoMessage()               {}
+func (*ExtractRefsResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *ExtractRefsResult) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type ApplyRefsOptions struct {
+	// Only needs to be specified in the first message.
+	Repository string `protobuf:"bytes,1,opt,name=repository" json:"repository,omitempty"`
+	// A chunk of a git bundle.
+	Bundle []byte `protobuf:"bytes,2,opt,name=bundle,proto3" json:"bundle,omitempty"`
+}
+
+func (m *ApplyRefsOptions) Reset()                    { *m = ApplyRefsOptions{} }
+func (m *ApplyRefsOptions) String() string            { return proto.CompactTextString(m) }
+func (*ApplyRefsOptions) ProtoMessage()               {}
+func (*ApplyRefsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *ApplyRefsOptions) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *ApplyRefsOptions) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type ApplyRefsResult struct {
+	// The refs that were updated.
+	Revision []*Revision `protobuf:"bytes,1,rep,name=revision" json:"revision,omitempty"`
+}
+
+func (m *ApplyRefsResult) Reset()                    { *m = ApplyRefsResult{} }
+func (m *ApplyRefsResult) String() string            { return proto.CompactTextString(m) }
+func (*ApplyRefsResult) ProtoMessage()               {}
+func (*ApplyRefsResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *ApplyRefsResult) GetRevision() []*Revision {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+type GetKnownRefsOptions struct {
+	Repository string `protobuf:"bytes,1,opt,name=repository" json:"repository,omitempty"`
+}
+
+func (m *GetKnownRefsOptions) Reset()                    { *m = GetKnownRefsOptions{} }
+func (m *GetKnownRefsOptions) String() string            { return proto.CompactTextString(m) }
+func (*GetKnownRefsOptions) ProtoMessage()               {}
+func (*GetKnownRefsOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *GetKnownRefsOptions) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+type GetKnownRefsResult struct {
+	Revision []*Revision `protobuf:"bytes,1,rep,name=revision" json:"revision,omitempty"`
+}
+
+func (m *GetKnownRefsResult) Reset()                    { *m = GetKnownRefsResult{} }
+func (m *GetKnownRefsResult) String() string            { return proto.CompactTextString(m) }
+func (*GetKnownRefsResult) ProtoMessage()               {}
+func (*GetKnownRefsResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *GetKnownRefsResult) GetRevision() []*Revision {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ShellCommand)(nil), "stonesthrow.ShellCommand")
+	proto.RegisterType((*RepositoryState)(nil), "stonesthrow.RepositoryState")
+	proto.RegisterType((*RunState)(nil), "stonesthrow.RunState")
+	proto.RegisterType((*BuilderJob)(nil), "stonesthrow.BuilderJob")
+	proto.RegisterType((*BuilderJobs)(nil), "stonesthrow.BuilderJobs")
 	proto.RegisterType((*GitRepositoryInfo)(nil), "stonesthrow.GitRepositoryInfo")
 	proto.RegisterType((*GitRepositoryInfo_Branch)(nil), "stonesthrow.GitRepositoryInfo.Branch")
 	proto.RegisterType((*GitRepositoryInfo_Upstream)(nil), "stonesthrow.GitRepositoryInfo.Upstream")
@@ -987,6 +2105,11 @@ func init() {
 	proto.RegisterType((*JobEvent)(nil), "stonesthrow.JobEvent")
 	proto.RegisterType((*BranchList)(nil), "stonesthrow.BranchList")
 	proto.RegisterType((*RunOptions)(nil), "stonesthrow.RunOptions")
+	proto.RegisterType((*WindowSize)(nil), "stonesthrow.WindowSize")
+	proto.RegisterType((*CommandInput)(nil), "stonesthrow.CommandInput")
+	proto.RegisterType((*HelloOptions)(nil), "stonesthrow.HelloOptions")
+	proto.RegisterType((*HelloResult)(nil), "stonesthrow.HelloResult")
+	proto.RegisterType((*ErrorDetails)(nil), "stonesthrow.ErrorDetails")
 	proto.RegisterType((*PingOptions)(nil), "stonesthrow.PingOptions")
 	proto.RegisterType((*PingResult)(nil), "stonesthrow.PingResult")
 	proto.RegisterType((*FetchFileOptions)(nil), "stonesthrow.FetchFileOptions")
@@ -998,45 +2121,723 @@ func init() {
 	proto.RegisterType((*TargetList)(nil), "stonesthrow.TargetList")
 	proto.RegisterType((*ListJobsOptions)(nil), "stonesthrow.ListJobsOptions")
 	proto.RegisterType((*KillJobsOptions)(nil), "stonesthrow.KillJobsOptions")
+	proto.RegisterType((*AttachJobOptions)(nil), "stonesthrow.AttachJobOptions")
+	proto.RegisterType((*JobHistoryOptions)(nil), "stonesthrow.JobHistoryOptions")
+	proto.RegisterType((*JobLogOptions)(nil), "stonesthrow.JobLogOptions")
 	proto.RegisterType((*ShutdownOptions)(nil), "stonesthrow.ShutdownOptions")
 	proto.RegisterType((*SelfUpdateOptions)(nil), "stonesthrow.SelfUpdateOptions")
+	proto.RegisterType((*AuditRecord)(nil), "stonesthrow.AuditRecord")
+	proto.RegisterType((*AuditRecords)(nil), "stonesthrow.AuditRecords")
+	proto.RegisterType((*AuditLogOptions)(nil), "stonesthrow.AuditLogOptions")
+	proto.RegisterType((*Task)(nil), "stonesthrow.Task")
+	proto.RegisterType((*TaskEvent)(nil), "stonesthrow.TaskEvent")
+	proto.RegisterType((*ListJobsResult)(nil), "stonesthrow.ListJobsResult")
+	proto.RegisterType((*KillJobsResult)(nil), "stonesthrow.KillJobsResult")
+	proto.RegisterType((*ShutdownResult)(nil), "stonesthrow.ShutdownResult")
+	proto.RegisterType((*SelfUpdateResult)(nil), "stonesthrow.SelfUpdateResult")
+	proto.RegisterType((*FetchFileResult)(nil), "stonesthrow.FetchFileResult")
+	proto.RegisterType((*Revision)(nil), "stonesthrow.Revision")
+	proto.RegisterType((*ExtractRefsOptions)(nil), "stonesthrow.ExtractRefsOptions")
+	proto.RegisterType((*ExtractRefsResult)(nil), "stonesthrow.ExtractRefsResult")
+	proto.RegisterType((*ApplyRefsOptions)(nil), "stonesthrow.ApplyRefsOptions")
+	proto.RegisterType((*ApplyRefsResult)(nil), "stonesthrow.ApplyRefsResult")
+	proto.RegisterType((*GetKnownRefsOptions)(nil), "stonesthrow.GetKnownRefsOptions")
+	proto.RegisterType((*GetKnownRefsResult)(nil), "stonesthrow.GetKnownRefsResult")
 	proto.RegisterEnum("stonesthrow.LogEvent_Severity", LogEvent_Severity_name, LogEvent_Severity_value)
 	proto.RegisterEnum("stonesthrow.CommandOutputEvent_Stream", CommandOutputEvent_Stream_name, CommandOutputEvent_Stream_value)
 	proto.RegisterEnum("stonesthrow.GitBranchTaskEvent_Result", GitBranchTaskEvent_Result_name, GitBranchTaskEvent_Result_value)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for BuildHost service
+
+type BuildHostClient interface {
+	RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunScriptCommandClient, error)
+	ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error)
+	ListTargets(ctx context.Context, in *ListTargetsOptions, opts ...grpc.CallOption) (*TargetList, error)
+	RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunShellCommandClient, error)
+	FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (BuildHost_FetchFileClient, error)
+	RunScriptCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (BuildHost_RunScriptCommandWithInputClient, error)
+	RunShellCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (BuildHost_RunShellCommandWithInputClient, error)
+}
+
+type buildHostClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBuildHostClient(cc *grpc.ClientConn) BuildHostClient {
+	return &buildHostClient{cc}
+}
+
+func (c *buildHostClient) RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunScriptCommandClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[0], c.cc, "/stonesthrow.BuildHost/RunScriptCommand", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildHostRunScriptCommandClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BuildHost_RunScriptCommandClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type buildHostRunScriptCommandClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildHostRunScriptCommandClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *buildHostClient) ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error) {
+	out := new(CommandList)
+	err := grpc.Invoke(ctx, "/stonesthrow.BuildHost/ListScriptCommands", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildHostClient) ListTargets(ctx context.Context, in *ListTargetsOptions, opts ...grpc.CallOption) (*TargetList, error) {
+	out := new(TargetList)
+	err := grpc.Invoke(ctx, "/stonesthrow.BuildHost/ListTargets", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildHostClient) RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunShellCommandClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[1], c.cc, "/stonesthrow.BuildHost/RunShellCommand", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildHostRunShellCommandClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BuildHost_RunShellCommandClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type buildHostRunShellCommandClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildHostRunShellCommandClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *buildHostClient) FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (BuildHost_FetchFileClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[2], c.cc, "/stonesthrow.BuildHost/FetchFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildHostFetchFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BuildHost_FetchFileClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type buildHostFetchFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildHostFetchFileClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *buildHostClient) RunScriptCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (BuildHost_RunScriptCommandWithInputClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[3], c.cc, "/stonesthrow.BuildHost/RunScriptCommandWithInput", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildHostRunScriptCommandWithInputClient{stream}
+	return x, nil
+}
+
+type BuildHost_RunScriptCommandWithInputClient interface {
+	Send(*CommandInput) error
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type buildHostRunScriptCommandWithInputClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildHostRunScriptCommandWithInputClient) Send(m *CommandInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *buildHostRunScriptCommandWithInputClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *buildHostClient) RunShellCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (BuildHost_RunShellCommandWithInputClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[4], c.cc, "/stonesthrow.BuildHost/RunShellCommandWithInput", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildHostRunShellCommandWithInputClient{stream}
+	return x, nil
+}
+
+type BuildHost_RunShellCommandWithInputClient interface {
+	Send(*CommandInput) error
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type buildHostRunShellCommandWithInputClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildHostRunShellCommandWithInputClient) Send(m *CommandInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *buildHostRunShellCommandWithInputClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for BuildHost service
+
+type BuildHostServer interface {
+	RunScriptCommand(*RunOptions, BuildHost_RunScriptCommandServer) error
+	ListScriptCommands(context.Context, *ListCommandsOptions) (*CommandList, error)
+	ListTargets(context.Context, *ListTargetsOptions) (*TargetList, error)
+	RunShellCommand(*RunOptions, BuildHost_RunShellCommandServer) error
+	FetchFile(*FetchFileOptions, BuildHost_FetchFileServer) error
+	RunScriptCommandWithInput(BuildHost_RunScriptCommandWithInputServer) error
+	RunShellCommandWithInput(BuildHost_RunShellCommandWithInputServer) error
+}
+
+func RegisterBuildHostServer(s *grpc.Server, srv BuildHostServer) {
+	s.RegisterService(&_BuildHost_serviceDesc, srv)
+}
+
+func _BuildHost_RunScriptCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunOptions)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildHostServer).RunScriptCommand(m, &buildHostRunScriptCommandServer{stream})
+}
+
+type BuildHost_RunScriptCommandServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type buildHostRunScriptCommandServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildHostRunScriptCommandServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BuildHost_ListScriptCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommandsOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildHostServer).ListScriptCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.BuildHost/ListScriptCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildHostServer).ListScriptCommands(ctx, req.(*ListCommandsOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildHost_ListTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTargetsOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildHostServer).ListTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.BuildHost/ListTargets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildHostServer).ListTargets(ctx, req.(*ListTargetsOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildHost_RunShellCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunOptions)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildHostServer).RunShellCommand(m, &buildHostRunShellCommandServer{stream})
+}
+
+type BuildHost_RunShellCommandServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type buildHostRunShellCommandServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildHostRunShellCommandServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BuildHost_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchFileOptions)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildHostServer).FetchFile(m, &buildHostFetchFileServer{stream})
+}
+
+type BuildHost_FetchFileServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type buildHostFetchFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildHostFetchFileServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BuildHost_RunScriptCommandWithInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BuildHostServer).RunScriptCommandWithInput(&buildHostRunScriptCommandWithInputServer{stream})
+}
+
+type BuildHost_RunScriptCommandWithInputServer interface {
+	Send(*JobEvent) error
+	Recv() (*CommandInput, error)
+	grpc.ServerStream
+}
+
+type buildHostRunScriptCommandWithInputServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildHostRunScriptCommandWithInputServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *buildHostRunScriptCommandWithInputServer) Recv() (*CommandInput, error) {
+	m := new(CommandInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BuildHost_RunShellCommandWithInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BuildHostServer).RunShellCommandWithInput(&buildHostRunShellCommandWithInputServer{stream})
+}
+
+type BuildHost_RunShellCommandWithInputServer interface {
+	Send(*JobEvent) error
+	Recv() (*CommandInput, error)
+	grpc.ServerStream
+}
+
+type buildHostRunShellCommandWithInputServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildHostRunShellCommandWithInputServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *buildHostRunShellCommandWithInputServer) Recv() (*CommandInput, error) {
+	m := new(CommandInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _BuildHost_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "stonesthrow.BuildHost",
+	HandlerType: (*BuildHostServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListScriptCommands",
+			Handler:    _BuildHost_ListScriptCommands_Handler,
+		},
+		{
+			MethodName: "ListTargets",
+			Handler:    _BuildHost_ListTargets_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunScriptCommand",
+			Handler:       _BuildHost_RunScriptCommand_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RunShellCommand",
+			Handler:       _BuildHost_RunShellCommand_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FetchFile",
+			Handler:       _BuildHost_FetchFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RunScriptCommandWithInput",
+			Handler:       _BuildHost_RunScriptCommandWithInput_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RunShellCommandWithInput",
+			Handler:       _BuildHost_RunShellCommandWithInput_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "st.proto",
+}
+
+// Client API for RepositoryHost service
+
+type RepositoryHostClient interface {
+	RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunScriptCommandClient, error)
+	ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error)
+	RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunShellCommandClient, error)
+	GetBranchConfig(ctx context.Context, in *BranchConfigOptions, opts ...grpc.CallOption) (*GitRepositoryInfo, error)
+	SetBranchConfig(ctx context.Context, in *GitRepositoryInfo, opts ...grpc.CallOption) (RepositoryHost_SetBranchConfigClient, error)
+	PullFromUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PullFromUpstreamClient, error)
+	PushToUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PushToUpstreamClient, error)
+	Status(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_StatusClient, error)
+	SyncRemote(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_SyncRemoteClient, error)
+	PrepareForReceive(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_PrepareForReceiveClient, error)
+	FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (RepositoryHost_FetchFileClient, error)
+	RunScriptCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (RepositoryHost_RunScriptCommandWithInputClient, error)
+	RunShellCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (RepositoryHost_RunShellCommandWithInputClient, error)
+}
+
+type repositoryHostClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRepositoryHostClient(cc *grpc.ClientConn) RepositoryHostClient {
+	return &repositoryHostClient{cc}
+}
+
+func (c *repositoryHostClient) RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunScriptCommandClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[0], c.cc, "/stonesthrow.RepositoryHost/RunScriptCommand", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostRunScriptCommandClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepositoryHost_RunScriptCommandClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostRunScriptCommandClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostRunScriptCommandClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error) {
+	out := new(CommandList)
+	err := grpc.Invoke(ctx, "/stonesthrow.RepositoryHost/ListScriptCommands", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryHostClient) RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunShellCommandClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[1], c.cc, "/stonesthrow.RepositoryHost/RunShellCommand", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostRunShellCommandClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepositoryHost_RunShellCommandClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostRunShellCommandClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostRunShellCommandClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) GetBranchConfig(ctx context.Context, in *BranchConfigOptions, opts ...grpc.CallOption) (*GitRepositoryInfo, error) {
+	out := new(GitRepositoryInfo)
+	err := grpc.Invoke(ctx, "/stonesthrow.RepositoryHost/GetBranchConfig", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryHostClient) SetBranchConfig(ctx context.Context, in *GitRepositoryInfo, opts ...grpc.CallOption) (RepositoryHost_SetBranchConfigClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[2], c.cc, "/stonesthrow.RepositoryHost/SetBranchConfig", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostSetBranchConfigClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepositoryHost_SetBranchConfigClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostSetBranchConfigClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostSetBranchConfigClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) PullFromUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PullFromUpstreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[3], c.cc, "/stonesthrow.RepositoryHost/PullFromUpstream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostPullFromUpstreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepositoryHost_PullFromUpstreamClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostPullFromUpstreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostPullFromUpstreamClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) PushToUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PushToUpstreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[4], c.cc, "/stonesthrow.RepositoryHost/PushToUpstream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostPushToUpstreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepositoryHost_PushToUpstreamClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostPushToUpstreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostPushToUpstreamClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) Status(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_StatusClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[5], c.cc, "/stonesthrow.RepositoryHost/Status", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepositoryHost_StatusClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostStatusClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
 
-// Client API for BuildHost service
+func (c *repositoryHostClient) SyncRemote(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_SyncRemoteClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[6], c.cc, "/stonesthrow.RepositoryHost/SyncRemote", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repositoryHostSyncRemoteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
 
-type BuildHostClient interface {
-	RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunScriptCommandClient, error)
-	ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error)
-	ListTargets(ctx context.Context, in *ListTargetsOptions, opts ...grpc.CallOption) (*TargetList, error)
-	RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunShellCommandClient, error)
-	FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (BuildHost_FetchFileClient, error)
+type RepositoryHost_SyncRemoteClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
 }
 
-type buildHostClient struct {
-	cc *grpc.ClientConn
+type repositoryHostSyncRemoteClient struct {
+	grpc.ClientStream
 }
 
-func NewBuildHostClient(cc *grpc.ClientConn) BuildHostClient {
-	return &buildHostClient{cc}
+func (x *repositoryHostSyncRemoteClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func (c *buildHostClient) RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunScriptCommandClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[0], c.cc, "/stonesthrow.BuildHost/RunScriptCommand", opts...)
+func (c *repositoryHostClient) PrepareForReceive(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_PrepareForReceiveClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[7], c.cc, "/stonesthrow.RepositoryHost/PrepareForReceive", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &buildHostRunScriptCommandClient{stream}
+	x := &repositoryHostPrepareForReceiveClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1046,16 +2847,16 @@ func (c *buildHostClient) RunScriptCommand(ctx context.Context, in *RunOptions,
 	return x, nil
 }
 
-type BuildHost_RunScriptCommandClient interface {
+type RepositoryHost_PrepareForReceiveClient interface {
 	Recv() (*JobEvent, error)
 	grpc.ClientStream
 }
 
-type buildHostRunScriptCommandClient struct {
+type repositoryHostPrepareForReceiveClient struct {
 	grpc.ClientStream
 }
 
-func (x *buildHostRunScriptCommandClient) Recv() (*JobEvent, error) {
+func (x *repositoryHostPrepareForReceiveClient) Recv() (*JobEvent, error) {
 	m := new(JobEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1063,346 +2864,530 @@ func (x *buildHostRunScriptCommandClient) Recv() (*JobEvent, error) {
 	return m, nil
 }
 
-func (c *buildHostClient) ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error) {
-	out := new(CommandList)
-	err := grpc.Invoke(ctx, "/stonesthrow.BuildHost/ListScriptCommands", in, out, c.cc, opts...)
+func (c *repositoryHostClient) FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (RepositoryHost_FetchFileClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[8], c.cc, "/stonesthrow.RepositoryHost/FetchFile", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &repositoryHostFetchFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *buildHostClient) ListTargets(ctx context.Context, in *ListTargetsOptions, opts ...grpc.CallOption) (*TargetList, error) {
-	out := new(TargetList)
-	err := grpc.Invoke(ctx, "/stonesthrow.BuildHost/ListTargets", in, out, c.cc, opts...)
+type RepositoryHost_FetchFileClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostFetchFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostFetchFileClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) RunScriptCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (RepositoryHost_RunScriptCommandWithInputClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[9], c.cc, "/stonesthrow.RepositoryHost/RunScriptCommandWithInput", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &repositoryHostRunScriptCommandWithInputClient{stream}
+	return x, nil
 }
 
-func (c *buildHostClient) RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (BuildHost_RunShellCommandClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[1], c.cc, "/stonesthrow.BuildHost/RunShellCommand", opts...)
+type RepositoryHost_RunScriptCommandWithInputClient interface {
+	Send(*CommandInput) error
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostRunScriptCommandWithInputClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostRunScriptCommandWithInputClient) Send(m *CommandInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *repositoryHostRunScriptCommandWithInputClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repositoryHostClient) RunShellCommandWithInput(ctx context.Context, opts ...grpc.CallOption) (RepositoryHost_RunShellCommandWithInputClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[10], c.cc, "/stonesthrow.RepositoryHost/RunShellCommandWithInput", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &buildHostRunShellCommandClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	x := &repositoryHostRunShellCommandWithInputClient{stream}
+	return x, nil
+}
+
+type RepositoryHost_RunShellCommandWithInputClient interface {
+	Send(*CommandInput) error
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type repositoryHostRunShellCommandWithInputClient struct {
+	grpc.ClientStream
+}
+
+func (x *repositoryHostRunShellCommandWithInputClient) Send(m *CommandInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *repositoryHostRunShellCommandWithInputClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	return m, nil
+}
+
+// Server API for RepositoryHost service
+
+type RepositoryHostServer interface {
+	RunScriptCommand(*RunOptions, RepositoryHost_RunScriptCommandServer) error
+	ListScriptCommands(context.Context, *ListCommandsOptions) (*CommandList, error)
+	RunShellCommand(*RunOptions, RepositoryHost_RunShellCommandServer) error
+	GetBranchConfig(context.Context, *BranchConfigOptions) (*GitRepositoryInfo, error)
+	SetBranchConfig(*GitRepositoryInfo, RepositoryHost_SetBranchConfigServer) error
+	PullFromUpstream(*BranchList, RepositoryHost_PullFromUpstreamServer) error
+	PushToUpstream(*BranchList, RepositoryHost_PushToUpstreamServer) error
+	Status(*RepositoryState, RepositoryHost_StatusServer) error
+	SyncRemote(*RepositoryState, RepositoryHost_SyncRemoteServer) error
+	PrepareForReceive(*RepositoryState, RepositoryHost_PrepareForReceiveServer) error
+	FetchFile(*FetchFileOptions, RepositoryHost_FetchFileServer) error
+	RunScriptCommandWithInput(RepositoryHost_RunScriptCommandWithInputServer) error
+	RunShellCommandWithInput(RepositoryHost_RunShellCommandWithInputServer) error
+}
+
+func RegisterRepositoryHostServer(s *grpc.Server, srv RepositoryHostServer) {
+	s.RegisterService(&_RepositoryHost_serviceDesc, srv)
+}
+
+func _RepositoryHost_RunScriptCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunOptions)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepositoryHostServer).RunScriptCommand(m, &repositoryHostRunScriptCommandServer{stream})
+}
+
+type RepositoryHost_RunScriptCommandServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type repositoryHostRunScriptCommandServer struct {
+	grpc.ServerStream
+}
+
+func (x *repositoryHostRunScriptCommandServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RepositoryHost_ListScriptCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommandsOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryHostServer).ListScriptCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.RepositoryHost/ListScriptCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryHostServer).ListScriptCommands(ctx, req.(*ListCommandsOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryHost_RunShellCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunOptions)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepositoryHostServer).RunShellCommand(m, &repositoryHostRunShellCommandServer{stream})
+}
+
+type RepositoryHost_RunShellCommandServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type repositoryHostRunShellCommandServer struct {
+	grpc.ServerStream
+}
+
+func (x *repositoryHostRunShellCommandServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RepositoryHost_GetBranchConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BranchConfigOptions)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(RepositoryHostServer).GetBranchConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.RepositoryHost/GetBranchConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryHostServer).GetBranchConfig(ctx, req.(*BranchConfigOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryHost_SetBranchConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GitRepositoryInfo)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepositoryHostServer).SetBranchConfig(m, &repositoryHostSetBranchConfigServer{stream})
+}
+
+type RepositoryHost_SetBranchConfigServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type repositoryHostSetBranchConfigServer struct {
+	grpc.ServerStream
+}
+
+func (x *repositoryHostSetBranchConfigServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RepositoryHost_PullFromUpstream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BranchList)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepositoryHostServer).PullFromUpstream(m, &repositoryHostPullFromUpstreamServer{stream})
 }
 
-type BuildHost_RunShellCommandClient interface {
-	Recv() (*JobEvent, error)
-	grpc.ClientStream
+type RepositoryHost_PullFromUpstreamServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
 }
 
-type buildHostRunShellCommandClient struct {
-	grpc.ClientStream
+type repositoryHostPullFromUpstreamServer struct {
+	grpc.ServerStream
 }
 
-func (x *buildHostRunShellCommandClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (x *repositoryHostPullFromUpstreamServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *buildHostClient) FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (BuildHost_FetchFileClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_BuildHost_serviceDesc.Streams[2], c.cc, "/stonesthrow.BuildHost/FetchFile", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &buildHostFetchFileClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func _RepositoryHost_PushToUpstream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BranchList)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return x, nil
+	return srv.(RepositoryHostServer).PushToUpstream(m, &repositoryHostPushToUpstreamServer{stream})
 }
 
-type BuildHost_FetchFileClient interface {
-	Recv() (*JobEvent, error)
-	grpc.ClientStream
+type RepositoryHost_PushToUpstreamServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
 }
 
-type buildHostFetchFileClient struct {
-	grpc.ClientStream
+type repositoryHostPushToUpstreamServer struct {
+	grpc.ServerStream
 }
 
-func (x *buildHostFetchFileClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (x *repositoryHostPushToUpstreamServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RepositoryHost_Status_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RepositoryState)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return m, nil
+	return srv.(RepositoryHostServer).Status(m, &repositoryHostStatusServer{stream})
 }
 
-// Server API for BuildHost service
+type RepositoryHost_StatusServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
 
-type BuildHostServer interface {
-	RunScriptCommand(*RunOptions, BuildHost_RunScriptCommandServer) error
-	ListScriptCommands(context.Context, *ListCommandsOptions) (*CommandList, error)
-	ListTargets(context.Context, *ListTargetsOptions) (*TargetList, error)
-	RunShellCommand(*RunOptions, BuildHost_RunShellCommandServer) error
-	FetchFile(*FetchFileOptions, BuildHost_FetchFileServer) error
+type repositoryHostStatusServer struct {
+	grpc.ServerStream
 }
 
-func RegisterBuildHostServer(s *grpc.Server, srv BuildHostServer) {
-	s.RegisterService(&_BuildHost_serviceDesc, srv)
+func (x *repositoryHostStatusServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _BuildHost_RunScriptCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RunOptions)
+func _RepositoryHost_SyncRemote_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RepositoryState)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(BuildHostServer).RunScriptCommand(m, &buildHostRunScriptCommandServer{stream})
+	return srv.(RepositoryHostServer).SyncRemote(m, &repositoryHostSyncRemoteServer{stream})
 }
 
-type BuildHost_RunScriptCommandServer interface {
+type RepositoryHost_SyncRemoteServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type buildHostRunScriptCommandServer struct {
+type repositoryHostSyncRemoteServer struct {
 	grpc.ServerStream
 }
 
-func (x *buildHostRunScriptCommandServer) Send(m *JobEvent) error {
+func (x *repositoryHostSyncRemoteServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _BuildHost_ListScriptCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListCommandsOptions)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(BuildHostServer).ListScriptCommands(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/stonesthrow.BuildHost/ListScriptCommands",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BuildHostServer).ListScriptCommands(ctx, req.(*ListCommandsOptions))
+func _RepositoryHost_PrepareForReceive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RepositoryState)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(RepositoryHostServer).PrepareForReceive(m, &repositoryHostPrepareForReceiveServer{stream})
 }
 
-func _BuildHost_ListTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListTargetsOptions)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(BuildHostServer).ListTargets(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/stonesthrow.BuildHost/ListTargets",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BuildHostServer).ListTargets(ctx, req.(*ListTargetsOptions))
-	}
-	return interceptor(ctx, in, info, handler)
+type RepositoryHost_PrepareForReceiveServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
 }
 
-func _BuildHost_RunShellCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RunOptions)
+type repositoryHostPrepareForReceiveServer struct {
+	grpc.ServerStream
+}
+
+func (x *repositoryHostPrepareForReceiveServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RepositoryHost_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchFileOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(BuildHostServer).RunShellCommand(m, &buildHostRunShellCommandServer{stream})
+	return srv.(RepositoryHostServer).FetchFile(m, &repositoryHostFetchFileServer{stream})
 }
 
-type BuildHost_RunShellCommandServer interface {
+type RepositoryHost_FetchFileServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type buildHostRunShellCommandServer struct {
+type repositoryHostFetchFileServer struct {
 	grpc.ServerStream
 }
 
-func (x *buildHostRunShellCommandServer) Send(m *JobEvent) error {
+func (x *repositoryHostFetchFileServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _BuildHost_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(FetchFileOptions)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _RepositoryHost_RunScriptCommandWithInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RepositoryHostServer).RunScriptCommandWithInput(&repositoryHostRunScriptCommandWithInputServer{stream})
+}
+
+type RepositoryHost_RunScriptCommandWithInputServer interface {
+	Send(*JobEvent) error
+	Recv() (*CommandInput, error)
+	grpc.ServerStream
+}
+
+type repositoryHostRunScriptCommandWithInputServer struct {
+	grpc.ServerStream
+}
+
+func (x *repositoryHostRunScriptCommandWithInputServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *repositoryHostRunScriptCommandWithInputServer) Recv() (*CommandInput, error) {
+	m := new(CommandInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return srv.(BuildHostServer).FetchFile(m, &buildHostFetchFileServer{stream})
+	return m, nil
 }
 
-type BuildHost_FetchFileServer interface {
+func _RepositoryHost_RunShellCommandWithInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RepositoryHostServer).RunShellCommandWithInput(&repositoryHostRunShellCommandWithInputServer{stream})
+}
+
+type RepositoryHost_RunShellCommandWithInputServer interface {
 	Send(*JobEvent) error
+	Recv() (*CommandInput, error)
 	grpc.ServerStream
 }
 
-type buildHostFetchFileServer struct {
+type repositoryHostRunShellCommandWithInputServer struct {
 	grpc.ServerStream
 }
 
-func (x *buildHostFetchFileServer) Send(m *JobEvent) error {
+func (x *repositoryHostRunShellCommandWithInputServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-var _BuildHost_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "stonesthrow.BuildHost",
-	HandlerType: (*BuildHostServer)(nil),
+func (x *repositoryHostRunShellCommandWithInputServer) Recv() (*CommandInput, error) {
+	m := new(CommandInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _RepositoryHost_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "stonesthrow.RepositoryHost",
+	HandlerType: (*RepositoryHostServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "ListScriptCommands",
-			Handler:    _BuildHost_ListScriptCommands_Handler,
+			Handler:    _RepositoryHost_ListScriptCommands_Handler,
 		},
 		{
-			MethodName: "ListTargets",
-			Handler:    _BuildHost_ListTargets_Handler,
+			MethodName: "GetBranchConfig",
+			Handler:    _RepositoryHost_GetBranchConfig_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "RunScriptCommand",
-			Handler:       _BuildHost_RunScriptCommand_Handler,
+			Handler:       _RepositoryHost_RunScriptCommand_Handler,
 			ServerStreams: true,
 		},
 		{
 			StreamName:    "RunShellCommand",
-			Handler:       _BuildHost_RunShellCommand_Handler,
+			Handler:       _RepositoryHost_RunShellCommand_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SetBranchConfig",
+			Handler:       _RepositoryHost_SetBranchConfig_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PullFromUpstream",
+			Handler:       _RepositoryHost_PullFromUpstream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PushToUpstream",
+			Handler:       _RepositoryHost_PushToUpstream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Status",
+			Handler:       _RepositoryHost_Status_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SyncRemote",
+			Handler:       _RepositoryHost_SyncRemote_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PrepareForReceive",
+			Handler:       _RepositoryHost_PrepareForReceive_Handler,
 			ServerStreams: true,
 		},
 		{
 			StreamName:    "FetchFile",
-			Handler:       _BuildHost_FetchFile_Handler,
+			Handler:       _RepositoryHost_FetchFile_Handler,
 			ServerStreams: true,
 		},
-	},
-	Metadata: "st.proto",
-}
-
-// Client API for RepositoryHost service
-
-type RepositoryHostClient interface {
-	RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunScriptCommandClient, error)
-	ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error)
-	RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunShellCommandClient, error)
-	GetBranchConfig(ctx context.Context, in *BranchConfigOptions, opts ...grpc.CallOption) (*GitRepositoryInfo, error)
-	SetBranchConfig(ctx context.Context, in *GitRepositoryInfo, opts ...grpc.CallOption) (RepositoryHost_SetBranchConfigClient, error)
-	PullFromUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PullFromUpstreamClient, error)
-	PushToUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PushToUpstreamClient, error)
-	Status(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_StatusClient, error)
-	SyncRemote(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_SyncRemoteClient, error)
-	PrepareForReceive(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_PrepareForReceiveClient, error)
-	FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (RepositoryHost_FetchFileClient, error)
-}
-
-type repositoryHostClient struct {
-	cc *grpc.ClientConn
-}
-
-func NewRepositoryHostClient(cc *grpc.ClientConn) RepositoryHostClient {
-	return &repositoryHostClient{cc}
+		{
+			StreamName:    "RunScriptCommandWithInput",
+			Handler:       _RepositoryHost_RunScriptCommandWithInput_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RunShellCommandWithInput",
+			Handler:       _RepositoryHost_RunShellCommandWithInput_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "st.proto",
 }
 
-func (c *repositoryHostClient) RunScriptCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunScriptCommandClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[0], c.cc, "/stonesthrow.RepositoryHost/RunScriptCommand", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &repositoryHostRunScriptCommandClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
+// Client API for ServiceHost service
 
-type RepositoryHost_RunScriptCommandClient interface {
-	Recv() (*JobEvent, error)
-	grpc.ClientStream
+type ServiceHostClient interface {
+	Hello(ctx context.Context, in *HelloOptions, opts ...grpc.CallOption) (*HelloResult, error)
+	Ping(ctx context.Context, in *PingOptions, opts ...grpc.CallOption) (*PingResult, error)
+	ListJobs(ctx context.Context, in *ListJobsOptions, opts ...grpc.CallOption) (*BuilderJobs, error)
+	KillJobs(ctx context.Context, in *KillJobsOptions, opts ...grpc.CallOption) (ServiceHost_KillJobsClient, error)
+	AttachJob(ctx context.Context, in *AttachJobOptions, opts ...grpc.CallOption) (ServiceHost_AttachJobClient, error)
+	GetJobHistory(ctx context.Context, in *JobHistoryOptions, opts ...grpc.CallOption) (*BuilderJobs, error)
+	GetJobLog(ctx context.Context, in *JobLogOptions, opts ...grpc.CallOption) (ServiceHost_GetJobLogClient, error)
+	Shutdown(ctx context.Context, in *ShutdownOptions, opts ...grpc.CallOption) (ServiceHost_ShutdownClient, error)
+	SelfUpdate(ctx context.Context, in *SelfUpdateOptions, opts ...grpc.CallOption) (ServiceHost_SelfUpdateClient, error)
+	GetAuditLog(ctx context.Context, in *AuditLogOptions, opts ...grpc.CallOption) (*AuditRecords, error)
 }
 
-type repositoryHostRunScriptCommandClient struct {
-	grpc.ClientStream
+type serviceHostClient struct {
+	cc *grpc.ClientConn
 }
 
-func (x *repositoryHostRunScriptCommandClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func NewServiceHostClient(cc *grpc.ClientConn) ServiceHostClient {
+	return &serviceHostClient{cc}
 }
 
-func (c *repositoryHostClient) ListScriptCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error) {
-	out := new(CommandList)
-	err := grpc.Invoke(ctx, "/stonesthrow.RepositoryHost/ListScriptCommands", in, out, c.cc, opts...)
+func (c *serviceHostClient) Hello(ctx context.Context, in *HelloOptions, opts ...grpc.CallOption) (*HelloResult, error) {
+	out := new(HelloResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/Hello", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *repositoryHostClient) RunShellCommand(ctx context.Context, in *RunOptions, opts ...grpc.CallOption) (RepositoryHost_RunShellCommandClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[1], c.cc, "/stonesthrow.RepositoryHost/RunShellCommand", opts...)
+func (c *serviceHostClient) Ping(ctx context.Context, in *PingOptions, opts ...grpc.CallOption) (*PingResult, error) {
+	out := new(PingResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/Ping", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostRunShellCommandClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type RepositoryHost_RunShellCommandClient interface {
-	Recv() (*JobEvent, error)
-	grpc.ClientStream
-}
-
-type repositoryHostRunShellCommandClient struct {
-	grpc.ClientStream
-}
-
-func (x *repositoryHostRunShellCommandClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+	return out, nil
 }
 
-func (c *repositoryHostClient) GetBranchConfig(ctx context.Context, in *BranchConfigOptions, opts ...grpc.CallOption) (*GitRepositoryInfo, error) {
-	out := new(GitRepositoryInfo)
-	err := grpc.Invoke(ctx, "/stonesthrow.RepositoryHost/GetBranchConfig", in, out, c.cc, opts...)
+func (c *serviceHostClient) ListJobs(ctx context.Context, in *ListJobsOptions, opts ...grpc.CallOption) (*BuilderJobs, error) {
+	out := new(BuilderJobs)
+	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/ListJobs", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *repositoryHostClient) SetBranchConfig(ctx context.Context, in *GitRepositoryInfo, opts ...grpc.CallOption) (RepositoryHost_SetBranchConfigClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[2], c.cc, "/stonesthrow.RepositoryHost/SetBranchConfig", opts...)
+func (c *serviceHostClient) KillJobs(ctx context.Context, in *KillJobsOptions, opts ...grpc.CallOption) (ServiceHost_KillJobsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[0], c.cc, "/stonesthrow.ServiceHost/KillJobs", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostSetBranchConfigClient{stream}
+	x := &serviceHostKillJobsClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1412,16 +3397,16 @@ func (c *repositoryHostClient) SetBranchConfig(ctx context.Context, in *GitRepos
 	return x, nil
 }
 
-type RepositoryHost_SetBranchConfigClient interface {
+type ServiceHost_KillJobsClient interface {
 	Recv() (*JobEvent, error)
 	grpc.ClientStream
 }
 
-type repositoryHostSetBranchConfigClient struct {
+type serviceHostKillJobsClient struct {
 	grpc.ClientStream
 }
 
-func (x *repositoryHostSetBranchConfigClient) Recv() (*JobEvent, error) {
+func (x *serviceHostKillJobsClient) Recv() (*JobEvent, error) {
 	m := new(JobEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1429,12 +3414,12 @@ func (x *repositoryHostSetBranchConfigClient) Recv() (*JobEvent, error) {
 	return m, nil
 }
 
-func (c *repositoryHostClient) PullFromUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PullFromUpstreamClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[3], c.cc, "/stonesthrow.RepositoryHost/PullFromUpstream", opts...)
+func (c *serviceHostClient) AttachJob(ctx context.Context, in *AttachJobOptions, opts ...grpc.CallOption) (ServiceHost_AttachJobClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[1], c.cc, "/stonesthrow.ServiceHost/AttachJob", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostPullFromUpstreamClient{stream}
+	x := &serviceHostAttachJobClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1444,16 +3429,16 @@ func (c *repositoryHostClient) PullFromUpstream(ctx context.Context, in *BranchL
 	return x, nil
 }
 
-type RepositoryHost_PullFromUpstreamClient interface {
+type ServiceHost_AttachJobClient interface {
 	Recv() (*JobEvent, error)
 	grpc.ClientStream
 }
 
-type repositoryHostPullFromUpstreamClient struct {
+type serviceHostAttachJobClient struct {
 	grpc.ClientStream
 }
 
-func (x *repositoryHostPullFromUpstreamClient) Recv() (*JobEvent, error) {
+func (x *serviceHostAttachJobClient) Recv() (*JobEvent, error) {
 	m := new(JobEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1461,44 +3446,21 @@ func (x *repositoryHostPullFromUpstreamClient) Recv() (*JobEvent, error) {
 	return m, nil
 }
 
-func (c *repositoryHostClient) PushToUpstream(ctx context.Context, in *BranchList, opts ...grpc.CallOption) (RepositoryHost_PushToUpstreamClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[4], c.cc, "/stonesthrow.RepositoryHost/PushToUpstream", opts...)
+func (c *serviceHostClient) GetJobHistory(ctx context.Context, in *JobHistoryOptions, opts ...grpc.CallOption) (*BuilderJobs, error) {
+	out := new(BuilderJobs)
+	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/GetJobHistory", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostPushToUpstreamClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type RepositoryHost_PushToUpstreamClient interface {
-	Recv() (*JobEvent, error)
-	grpc.ClientStream
-}
-
-type repositoryHostPushToUpstreamClient struct {
-	grpc.ClientStream
-}
-
-func (x *repositoryHostPushToUpstreamClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+	return out, nil
 }
 
-func (c *repositoryHostClient) Status(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_StatusClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[5], c.cc, "/stonesthrow.RepositoryHost/Status", opts...)
+func (c *serviceHostClient) GetJobLog(ctx context.Context, in *JobLogOptions, opts ...grpc.CallOption) (ServiceHost_GetJobLogClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[2], c.cc, "/stonesthrow.ServiceHost/GetJobLog", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostStatusClient{stream}
+	x := &serviceHostGetJobLogClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1508,16 +3470,16 @@ func (c *repositoryHostClient) Status(ctx context.Context, in *RepositoryState,
 	return x, nil
 }
 
-type RepositoryHost_StatusClient interface {
+type ServiceHost_GetJobLogClient interface {
 	Recv() (*JobEvent, error)
 	grpc.ClientStream
 }
 
-type repositoryHostStatusClient struct {
+type serviceHostGetJobLogClient struct {
 	grpc.ClientStream
 }
 
-func (x *repositoryHostStatusClient) Recv() (*JobEvent, error) {
+func (x *serviceHostGetJobLogClient) Recv() (*JobEvent, error) {
 	m := new(JobEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1525,12 +3487,12 @@ func (x *repositoryHostStatusClient) Recv() (*JobEvent, error) {
 	return m, nil
 }
 
-func (c *repositoryHostClient) SyncRemote(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_SyncRemoteClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[6], c.cc, "/stonesthrow.RepositoryHost/SyncRemote", opts...)
+func (c *serviceHostClient) Shutdown(ctx context.Context, in *ShutdownOptions, opts ...grpc.CallOption) (ServiceHost_ShutdownClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[3], c.cc, "/stonesthrow.ServiceHost/Shutdown", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostSyncRemoteClient{stream}
+	x := &serviceHostShutdownClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1540,16 +3502,16 @@ func (c *repositoryHostClient) SyncRemote(ctx context.Context, in *RepositorySta
 	return x, nil
 }
 
-type RepositoryHost_SyncRemoteClient interface {
+type ServiceHost_ShutdownClient interface {
 	Recv() (*JobEvent, error)
 	grpc.ClientStream
 }
 
-type repositoryHostSyncRemoteClient struct {
+type serviceHostShutdownClient struct {
 	grpc.ClientStream
 }
 
-func (x *repositoryHostSyncRemoteClient) Recv() (*JobEvent, error) {
+func (x *serviceHostShutdownClient) Recv() (*JobEvent, error) {
 	m := new(JobEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1557,12 +3519,12 @@ func (x *repositoryHostSyncRemoteClient) Recv() (*JobEvent, error) {
 	return m, nil
 }
 
-func (c *repositoryHostClient) PrepareForReceive(ctx context.Context, in *RepositoryState, opts ...grpc.CallOption) (RepositoryHost_PrepareForReceiveClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[7], c.cc, "/stonesthrow.RepositoryHost/PrepareForReceive", opts...)
+func (c *serviceHostClient) SelfUpdate(ctx context.Context, in *SelfUpdateOptions, opts ...grpc.CallOption) (ServiceHost_SelfUpdateClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[4], c.cc, "/stonesthrow.ServiceHost/SelfUpdate", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostPrepareForReceiveClient{stream}
+	x := &serviceHostSelfUpdateClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1572,16 +3534,16 @@ func (c *repositoryHostClient) PrepareForReceive(ctx context.Context, in *Reposi
 	return x, nil
 }
 
-type RepositoryHost_PrepareForReceiveClient interface {
+type ServiceHost_SelfUpdateClient interface {
 	Recv() (*JobEvent, error)
 	grpc.ClientStream
 }
 
-type repositoryHostPrepareForReceiveClient struct {
+type serviceHostSelfUpdateClient struct {
 	grpc.ClientStream
 }
 
-func (x *repositoryHostPrepareForReceiveClient) Recv() (*JobEvent, error) {
+func (x *serviceHostSelfUpdateClient) Recv() (*JobEvent, error) {
 	m := new(JobEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1589,388 +3551,459 @@ func (x *repositoryHostPrepareForReceiveClient) Recv() (*JobEvent, error) {
 	return m, nil
 }
 
-func (c *repositoryHostClient) FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (RepositoryHost_FetchFileClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_RepositoryHost_serviceDesc.Streams[8], c.cc, "/stonesthrow.RepositoryHost/FetchFile", opts...)
+func (c *serviceHostClient) GetAuditLog(ctx context.Context, in *AuditLogOptions, opts ...grpc.CallOption) (*AuditRecords, error) {
+	out := new(AuditRecords)
+	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/GetAuditLog", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &repositoryHostFetchFileClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type RepositoryHost_FetchFileClient interface {
-	Recv() (*JobEvent, error)
-	grpc.ClientStream
-}
-
-type repositoryHostFetchFileClient struct {
-	grpc.ClientStream
-}
-
-func (x *repositoryHostFetchFileClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-// Server API for RepositoryHost service
-
-type RepositoryHostServer interface {
-	RunScriptCommand(*RunOptions, RepositoryHost_RunScriptCommandServer) error
-	ListScriptCommands(context.Context, *ListCommandsOptions) (*CommandList, error)
-	RunShellCommand(*RunOptions, RepositoryHost_RunShellCommandServer) error
-	GetBranchConfig(context.Context, *BranchConfigOptions) (*GitRepositoryInfo, error)
-	SetBranchConfig(*GitRepositoryInfo, RepositoryHost_SetBranchConfigServer) error
-	PullFromUpstream(*BranchList, RepositoryHost_PullFromUpstreamServer) error
-	PushToUpstream(*BranchList, RepositoryHost_PushToUpstreamServer) error
-	Status(*RepositoryState, RepositoryHost_StatusServer) error
-	SyncRemote(*RepositoryState, RepositoryHost_SyncRemoteServer) error
-	PrepareForReceive(*RepositoryState, RepositoryHost_PrepareForReceiveServer) error
-	FetchFile(*FetchFileOptions, RepositoryHost_FetchFileServer) error
-}
-
-func RegisterRepositoryHostServer(s *grpc.Server, srv RepositoryHostServer) {
-	s.RegisterService(&_RepositoryHost_serviceDesc, srv)
-}
-
-func _RepositoryHost_RunScriptCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RunOptions)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(RepositoryHostServer).RunScriptCommand(m, &repositoryHostRunScriptCommandServer{stream})
+	return out, nil
 }
 
-type RepositoryHost_RunScriptCommandServer interface {
-	Send(*JobEvent) error
-	grpc.ServerStream
-}
+// Server API for ServiceHost service
 
-type repositoryHostRunScriptCommandServer struct {
-	grpc.ServerStream
+type ServiceHostServer interface {
+	Hello(context.Context, *HelloOptions) (*HelloResult, error)
+	Ping(context.Context, *PingOptions) (*PingResult, error)
+	ListJobs(context.Context, *ListJobsOptions) (*BuilderJobs, error)
+	KillJobs(*KillJobsOptions, ServiceHost_KillJobsServer) error
+	AttachJob(*AttachJobOptions, ServiceHost_AttachJobServer) error
+	GetJobHistory(context.Context, *JobHistoryOptions) (*BuilderJobs, error)
+	GetJobLog(*JobLogOptions, ServiceHost_GetJobLogServer) error
+	Shutdown(*ShutdownOptions, ServiceHost_ShutdownServer) error
+	SelfUpdate(*SelfUpdateOptions, ServiceHost_SelfUpdateServer) error
+	GetAuditLog(context.Context, *AuditLogOptions) (*AuditRecords, error)
 }
 
-func (x *repositoryHostRunScriptCommandServer) Send(m *JobEvent) error {
-	return x.ServerStream.SendMsg(m)
+func RegisterServiceHostServer(s *grpc.Server, srv ServiceHostServer) {
+	s.RegisterService(&_ServiceHost_serviceDesc, srv)
 }
 
-func _RepositoryHost_ListScriptCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListCommandsOptions)
+func _ServiceHost_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HelloOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RepositoryHostServer).ListScriptCommands(ctx, in)
+		return srv.(ServiceHostServer).Hello(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/stonesthrow.RepositoryHost/ListScriptCommands",
+		FullMethod: "/stonesthrow.ServiceHost/Hello",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RepositoryHostServer).ListScriptCommands(ctx, req.(*ListCommandsOptions))
+		return srv.(ServiceHostServer).Hello(ctx, req.(*HelloOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RepositoryHost_RunShellCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RunOptions)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(RepositoryHostServer).RunShellCommand(m, &repositoryHostRunShellCommandServer{stream})
-}
-
-type RepositoryHost_RunShellCommandServer interface {
-	Send(*JobEvent) error
-	grpc.ServerStream
-}
-
-type repositoryHostRunShellCommandServer struct {
-	grpc.ServerStream
-}
-
-func (x *repositoryHostRunShellCommandServer) Send(m *JobEvent) error {
-	return x.ServerStream.SendMsg(m)
+func _ServiceHost_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceHostServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.ServiceHost/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceHostServer).Ping(ctx, req.(*PingOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _RepositoryHost_GetBranchConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BranchConfigOptions)
+func _ServiceHost_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RepositoryHostServer).GetBranchConfig(ctx, in)
+		return srv.(ServiceHostServer).ListJobs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/stonesthrow.RepositoryHost/GetBranchConfig",
+		FullMethod: "/stonesthrow.ServiceHost/ListJobs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RepositoryHostServer).GetBranchConfig(ctx, req.(*BranchConfigOptions))
+		return srv.(ServiceHostServer).ListJobs(ctx, req.(*ListJobsOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RepositoryHost_SetBranchConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GitRepositoryInfo)
+func _ServiceHost_KillJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(KillJobsOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(RepositoryHostServer).SetBranchConfig(m, &repositoryHostSetBranchConfigServer{stream})
+	return srv.(ServiceHostServer).KillJobs(m, &serviceHostKillJobsServer{stream})
 }
 
-type RepositoryHost_SetBranchConfigServer interface {
+type ServiceHost_KillJobsServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type repositoryHostSetBranchConfigServer struct {
+type serviceHostKillJobsServer struct {
 	grpc.ServerStream
 }
 
-func (x *repositoryHostSetBranchConfigServer) Send(m *JobEvent) error {
+func (x *serviceHostKillJobsServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _RepositoryHost_PullFromUpstream_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(BranchList)
+func _ServiceHost_AttachJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AttachJobOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(RepositoryHostServer).PullFromUpstream(m, &repositoryHostPullFromUpstreamServer{stream})
+	return srv.(ServiceHostServer).AttachJob(m, &serviceHostAttachJobServer{stream})
 }
 
-type RepositoryHost_PullFromUpstreamServer interface {
+type ServiceHost_AttachJobServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type repositoryHostPullFromUpstreamServer struct {
+type serviceHostAttachJobServer struct {
 	grpc.ServerStream
 }
 
-func (x *repositoryHostPullFromUpstreamServer) Send(m *JobEvent) error {
+func (x *serviceHostAttachJobServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _RepositoryHost_PushToUpstream_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(BranchList)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _ServiceHost_GetJobHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobHistoryOptions)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(RepositoryHostServer).PushToUpstream(m, &repositoryHostPushToUpstreamServer{stream})
-}
-
-type RepositoryHost_PushToUpstreamServer interface {
-	Send(*JobEvent) error
-	grpc.ServerStream
-}
-
-type repositoryHostPushToUpstreamServer struct {
-	grpc.ServerStream
-}
-
-func (x *repositoryHostPushToUpstreamServer) Send(m *JobEvent) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(ServiceHostServer).GetJobHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.ServiceHost/GetJobHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceHostServer).GetJobHistory(ctx, req.(*JobHistoryOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _RepositoryHost_Status_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RepositoryState)
+func _ServiceHost_GetJobLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobLogOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(RepositoryHostServer).Status(m, &repositoryHostStatusServer{stream})
+	return srv.(ServiceHostServer).GetJobLog(m, &serviceHostGetJobLogServer{stream})
 }
 
-type RepositoryHost_StatusServer interface {
+type ServiceHost_GetJobLogServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type repositoryHostStatusServer struct {
+type serviceHostGetJobLogServer struct {
 	grpc.ServerStream
 }
 
-func (x *repositoryHostStatusServer) Send(m *JobEvent) error {
+func (x *serviceHostGetJobLogServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _RepositoryHost_SyncRemote_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RepositoryState)
+func _ServiceHost_Shutdown_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ShutdownOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(RepositoryHostServer).SyncRemote(m, &repositoryHostSyncRemoteServer{stream})
+	return srv.(ServiceHostServer).Shutdown(m, &serviceHostShutdownServer{stream})
 }
 
-type RepositoryHost_SyncRemoteServer interface {
+type ServiceHost_ShutdownServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type repositoryHostSyncRemoteServer struct {
+type serviceHostShutdownServer struct {
 	grpc.ServerStream
 }
 
-func (x *repositoryHostSyncRemoteServer) Send(m *JobEvent) error {
+func (x *serviceHostShutdownServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _RepositoryHost_PrepareForReceive_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RepositoryState)
+func _ServiceHost_SelfUpdate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SelfUpdateOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(RepositoryHostServer).PrepareForReceive(m, &repositoryHostPrepareForReceiveServer{stream})
+	return srv.(ServiceHostServer).SelfUpdate(m, &serviceHostSelfUpdateServer{stream})
 }
 
-type RepositoryHost_PrepareForReceiveServer interface {
+type ServiceHost_SelfUpdateServer interface {
 	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type repositoryHostPrepareForReceiveServer struct {
+type serviceHostSelfUpdateServer struct {
 	grpc.ServerStream
 }
 
-func (x *repositoryHostPrepareForReceiveServer) Send(m *JobEvent) error {
+func (x *serviceHostSelfUpdateServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _RepositoryHost_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(FetchFileOptions)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _ServiceHost_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogOptions)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(RepositoryHostServer).FetchFile(m, &repositoryHostFetchFileServer{stream})
-}
-
-type RepositoryHost_FetchFileServer interface {
-	Send(*JobEvent) error
-	grpc.ServerStream
-}
-
-type repositoryHostFetchFileServer struct {
-	grpc.ServerStream
-}
-
-func (x *repositoryHostFetchFileServer) Send(m *JobEvent) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(ServiceHostServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.ServiceHost/GetAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceHostServer).GetAuditLog(ctx, req.(*AuditLogOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-var _RepositoryHost_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "stonesthrow.RepositoryHost",
-	HandlerType: (*RepositoryHostServer)(nil),
+var _ServiceHost_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "stonesthrow.ServiceHost",
+	HandlerType: (*ServiceHostServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "ListScriptCommands",
-			Handler:    _RepositoryHost_ListScriptCommands_Handler,
-		},
-		{
-			MethodName: "GetBranchConfig",
-			Handler:    _RepositoryHost_GetBranchConfig_Handler,
+			MethodName: "Hello",
+			Handler:    _ServiceHost_Hello_Handler,
 		},
-	},
-	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "RunScriptCommand",
-			Handler:       _RepositoryHost_RunScriptCommand_Handler,
-			ServerStreams: true,
+			MethodName: "Ping",
+			Handler:    _ServiceHost_Ping_Handler,
 		},
 		{
-			StreamName:    "RunShellCommand",
-			Handler:       _RepositoryHost_RunShellCommand_Handler,
-			ServerStreams: true,
+			MethodName: "ListJobs",
+			Handler:    _ServiceHost_ListJobs_Handler,
 		},
 		{
-			StreamName:    "SetBranchConfig",
-			Handler:       _RepositoryHost_SetBranchConfig_Handler,
-			ServerStreams: true,
+			MethodName: "GetJobHistory",
+			Handler:    _ServiceHost_GetJobHistory_Handler,
 		},
 		{
-			StreamName:    "PullFromUpstream",
-			Handler:       _RepositoryHost_PullFromUpstream_Handler,
-			ServerStreams: true,
+			MethodName: "GetAuditLog",
+			Handler:    _ServiceHost_GetAuditLog_Handler,
 		},
+	},
+	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "PushToUpstream",
-			Handler:       _RepositoryHost_PushToUpstream_Handler,
+			StreamName:    "KillJobs",
+			Handler:       _ServiceHost_KillJobs_Handler,
 			ServerStreams: true,
 		},
 		{
-			StreamName:    "Status",
-			Handler:       _RepositoryHost_Status_Handler,
+			StreamName:    "AttachJob",
+			Handler:       _ServiceHost_AttachJob_Handler,
 			ServerStreams: true,
 		},
 		{
-			StreamName:    "SyncRemote",
-			Handler:       _RepositoryHost_SyncRemote_Handler,
+			StreamName:    "GetJobLog",
+			Handler:       _ServiceHost_GetJobLog_Handler,
 			ServerStreams: true,
 		},
 		{
-			StreamName:    "PrepareForReceive",
-			Handler:       _RepositoryHost_PrepareForReceive_Handler,
+			StreamName:    "Shutdown",
+			Handler:       _ServiceHost_Shutdown_Handler,
 			ServerStreams: true,
 		},
 		{
-			StreamName:    "FetchFile",
-			Handler:       _RepositoryHost_FetchFile_Handler,
+			StreamName:    "SelfUpdate",
+			Handler:       _ServiceHost_SelfUpdate_Handler,
 			ServerStreams: true,
 		},
 	},
 	Metadata: "st.proto",
 }
 
-// Client API for ServiceHost service
+// Client API for Host service
+
+type HostClient interface {
+	Hello(ctx context.Context, in *HelloOptions, opts ...grpc.CallOption) (*HelloResult, error)
+	Ping(ctx context.Context, in *PingOptions, opts ...grpc.CallOption) (*PingResult, error)
+	ListJobs(ctx context.Context, in *ListJobsOptions, opts ...grpc.CallOption) (Host_ListJobsClient, error)
+	KillJobs(ctx context.Context, in *KillJobsOptions, opts ...grpc.CallOption) (Host_KillJobsClient, error)
+	Shutdown(ctx context.Context, in *ShutdownOptions, opts ...grpc.CallOption) (*ShutdownResult, error)
+	SelfUpdate(ctx context.Context, in *SelfUpdateOptions, opts ...grpc.CallOption) (*SelfUpdateResult, error)
+	// Runs a script command, or a shell command if the options say so. The
+	// command runs on the build host if a platform is specified, and in the
+	// source directory otherwise.
+	RunCommand(ctx context.Context, opts ...grpc.CallOption) (Host_RunCommandClient, error)
+	ListCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error)
+	FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (Host_FetchFileClient, error)
+	ExtractRefs(ctx context.Context, in *ExtractRefsOptions, opts ...grpc.CallOption) (Host_ExtractRefsClient, error)
+	ApplyRefs(ctx context.Context, opts ...grpc.CallOption) (Host_ApplyRefsClient, error)
+	GetKnownRefs(ctx context.Context, in *GetKnownRefsOptions, opts ...grpc.CallOption) (*GetKnownRefsResult, error)
+	GetAuditLog(ctx context.Context, in *AuditLogOptions, opts ...grpc.CallOption) (*AuditRecords, error)
+}
+
+type hostClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHostClient(cc *grpc.ClientConn) HostClient {
+	return &hostClient{cc}
+}
+
+func (c *hostClient) Hello(ctx context.Context, in *HelloOptions, opts ...grpc.CallOption) (*HelloResult, error) {
+	out := new(HelloResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/Hello", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostClient) Ping(ctx context.Context, in *PingOptions, opts ...grpc.CallOption) (*PingResult, error) {
+	out := new(PingResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/Ping", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostClient) ListJobs(ctx context.Context, in *ListJobsOptions, opts ...grpc.CallOption) (Host_ListJobsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Host_serviceDesc.Streams[0], c.cc, "/stonesthrow.Host/ListJobs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hostListJobsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Host_ListJobsClient interface {
+	Recv() (*ListJobsResult, error)
+	grpc.ClientStream
+}
+
+type hostListJobsClient struct {
+	grpc.ClientStream
+}
+
+func (x *hostListJobsClient) Recv() (*ListJobsResult, error) {
+	m := new(ListJobsResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *hostClient) KillJobs(ctx context.Context, in *KillJobsOptions, opts ...grpc.CallOption) (Host_KillJobsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Host_serviceDesc.Streams[1], c.cc, "/stonesthrow.Host/KillJobs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hostKillJobsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Host_KillJobsClient interface {
+	Recv() (*KillJobsResult, error)
+	grpc.ClientStream
+}
+
+type hostKillJobsClient struct {
+	grpc.ClientStream
+}
+
+func (x *hostKillJobsClient) Recv() (*KillJobsResult, error) {
+	m := new(KillJobsResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *hostClient) Shutdown(ctx context.Context, in *ShutdownOptions, opts ...grpc.CallOption) (*ShutdownResult, error) {
+	out := new(ShutdownResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/Shutdown", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostClient) SelfUpdate(ctx context.Context, in *SelfUpdateOptions, opts ...grpc.CallOption) (*SelfUpdateResult, error) {
+	out := new(SelfUpdateResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/SelfUpdate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostClient) RunCommand(ctx context.Context, opts ...grpc.CallOption) (Host_RunCommandClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Host_serviceDesc.Streams[2], c.cc, "/stonesthrow.Host/RunCommand", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hostRunCommandClient{stream}
+	return x, nil
+}
 
-type ServiceHostClient interface {
-	Ping(ctx context.Context, in *PingOptions, opts ...grpc.CallOption) (*PingResult, error)
-	ListJobs(ctx context.Context, in *ListJobsOptions, opts ...grpc.CallOption) (*BuilderJobs, error)
-	KillJobs(ctx context.Context, in *KillJobsOptions, opts ...grpc.CallOption) (ServiceHost_KillJobsClient, error)
-	Shutdown(ctx context.Context, in *ShutdownOptions, opts ...grpc.CallOption) (ServiceHost_ShutdownClient, error)
-	SelfUpdate(ctx context.Context, in *SelfUpdateOptions, opts ...grpc.CallOption) (ServiceHost_SelfUpdateClient, error)
+type Host_RunCommandClient interface {
+	Send(*CommandInput) error
+	Recv() (*TaskEvent, error)
+	grpc.ClientStream
 }
 
-type serviceHostClient struct {
-	cc *grpc.ClientConn
+type hostRunCommandClient struct {
+	grpc.ClientStream
 }
 
-func NewServiceHostClient(cc *grpc.ClientConn) ServiceHostClient {
-	return &serviceHostClient{cc}
+func (x *hostRunCommandClient) Send(m *CommandInput) error {
+	return x.ClientStream.SendMsg(m)
 }
 
-func (c *serviceHostClient) Ping(ctx context.Context, in *PingOptions, opts ...grpc.CallOption) (*PingResult, error) {
-	out := new(PingResult)
-	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/Ping", in, out, c.cc, opts...)
-	if err != nil {
+func (x *hostRunCommandClient) Recv() (*TaskEvent, error) {
+	m := new(TaskEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *serviceHostClient) ListJobs(ctx context.Context, in *ListJobsOptions, opts ...grpc.CallOption) (*BuilderJobs, error) {
-	out := new(BuilderJobs)
-	err := grpc.Invoke(ctx, "/stonesthrow.ServiceHost/ListJobs", in, out, c.cc, opts...)
+func (c *hostClient) ListCommands(ctx context.Context, in *ListCommandsOptions, opts ...grpc.CallOption) (*CommandList, error) {
+	out := new(CommandList)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/ListCommands", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *serviceHostClient) KillJobs(ctx context.Context, in *KillJobsOptions, opts ...grpc.CallOption) (ServiceHost_KillJobsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[0], c.cc, "/stonesthrow.ServiceHost/KillJobs", opts...)
+func (c *hostClient) FetchFile(ctx context.Context, in *FetchFileOptions, opts ...grpc.CallOption) (Host_FetchFileClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Host_serviceDesc.Streams[3], c.cc, "/stonesthrow.Host/FetchFile", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &serviceHostKillJobsClient{stream}
+	x := &hostFetchFileClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1980,29 +4013,29 @@ func (c *serviceHostClient) KillJobs(ctx context.Context, in *KillJobsOptions, o
 	return x, nil
 }
 
-type ServiceHost_KillJobsClient interface {
-	Recv() (*JobEvent, error)
+type Host_FetchFileClient interface {
+	Recv() (*FetchFileResult, error)
 	grpc.ClientStream
 }
 
-type serviceHostKillJobsClient struct {
+type hostFetchFileClient struct {
 	grpc.ClientStream
 }
 
-func (x *serviceHostKillJobsClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
+func (x *hostFetchFileClient) Recv() (*FetchFileResult, error) {
+	m := new(FetchFileResult)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *serviceHostClient) Shutdown(ctx context.Context, in *ShutdownOptions, opts ...grpc.CallOption) (ServiceHost_ShutdownClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[1], c.cc, "/stonesthrow.ServiceHost/Shutdown", opts...)
+func (c *hostClient) ExtractRefs(ctx context.Context, in *ExtractRefsOptions, opts ...grpc.CallOption) (Host_ExtractRefsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Host_serviceDesc.Streams[4], c.cc, "/stonesthrow.Host/ExtractRefs", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &serviceHostShutdownClient{stream}
+	x := &hostExtractRefsClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -2012,197 +4045,427 @@ func (c *serviceHostClient) Shutdown(ctx context.Context, in *ShutdownOptions, o
 	return x, nil
 }
 
-type ServiceHost_ShutdownClient interface {
-	Recv() (*JobEvent, error)
+type Host_ExtractRefsClient interface {
+	Recv() (*ExtractRefsResult, error)
 	grpc.ClientStream
 }
 
-type serviceHostShutdownClient struct {
+type hostExtractRefsClient struct {
 	grpc.ClientStream
 }
 
-func (x *serviceHostShutdownClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
+func (x *hostExtractRefsClient) Recv() (*ExtractRefsResult, error) {
+	m := new(ExtractRefsResult)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *serviceHostClient) SelfUpdate(ctx context.Context, in *SelfUpdateOptions, opts ...grpc.CallOption) (ServiceHost_SelfUpdateClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_ServiceHost_serviceDesc.Streams[2], c.cc, "/stonesthrow.ServiceHost/SelfUpdate", opts...)
+func (c *hostClient) ApplyRefs(ctx context.Context, opts ...grpc.CallOption) (Host_ApplyRefsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Host_serviceDesc.Streams[5], c.cc, "/stonesthrow.Host/ApplyRefs", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &serviceHostSelfUpdateClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
+	x := &hostApplyRefsClient{stream}
 	return x, nil
 }
 
-type ServiceHost_SelfUpdateClient interface {
-	Recv() (*JobEvent, error)
+type Host_ApplyRefsClient interface {
+	Send(*ApplyRefsOptions) error
+	CloseAndRecv() (*ApplyRefsResult, error)
 	grpc.ClientStream
 }
 
-type serviceHostSelfUpdateClient struct {
+type hostApplyRefsClient struct {
 	grpc.ClientStream
 }
 
-func (x *serviceHostSelfUpdateClient) Recv() (*JobEvent, error) {
-	m := new(JobEvent)
+func (x *hostApplyRefsClient) Send(m *ApplyRefsOptions) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *hostApplyRefsClient) CloseAndRecv() (*ApplyRefsResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ApplyRefsResult)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-// Server API for ServiceHost service
-
-type ServiceHostServer interface {
-	Ping(context.Context, *PingOptions) (*PingResult, error)
-	ListJobs(context.Context, *ListJobsOptions) (*BuilderJobs, error)
-	KillJobs(*KillJobsOptions, ServiceHost_KillJobsServer) error
-	Shutdown(*ShutdownOptions, ServiceHost_ShutdownServer) error
-	SelfUpdate(*SelfUpdateOptions, ServiceHost_SelfUpdateServer) error
+func (c *hostClient) GetKnownRefs(ctx context.Context, in *GetKnownRefsOptions, opts ...grpc.CallOption) (*GetKnownRefsResult, error) {
+	out := new(GetKnownRefsResult)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/GetKnownRefs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterServiceHostServer(s *grpc.Server, srv ServiceHostServer) {
-	s.RegisterService(&_ServiceHost_serviceDesc, srv)
+func (c *hostClient) GetAuditLog(ctx context.Context, in *AuditLogOptions, opts ...grpc.CallOption) (*AuditRecords, error) {
+	out := new(AuditRecords)
+	err := grpc.Invoke(ctx, "/stonesthrow.Host/GetAuditLog", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func _ServiceHost_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PingOptions)
+// Server API for Host service
+
+type HostServer interface {
+	Hello(context.Context, *HelloOptions) (*HelloResult, error)
+	Ping(context.Context, *PingOptions) (*PingResult, error)
+	ListJobs(*ListJobsOptions, Host_ListJobsServer) error
+	KillJobs(*KillJobsOptions, Host_KillJobsServer) error
+	Shutdown(context.Context, *ShutdownOptions) (*ShutdownResult, error)
+	SelfUpdate(context.Context, *SelfUpdateOptions) (*SelfUpdateResult, error)
+	// Runs a script command, or a shell command if the options say so. The
+	// command runs on the build host if a platform is specified, and in the
+	// source directory otherwise.
+	RunCommand(Host_RunCommandServer) error
+	ListCommands(context.Context, *ListCommandsOptions) (*CommandList, error)
+	FetchFile(*FetchFileOptions, Host_FetchFileServer) error
+	ExtractRefs(*ExtractRefsOptions, Host_ExtractRefsServer) error
+	ApplyRefs(Host_ApplyRefsServer) error
+	GetKnownRefs(context.Context, *GetKnownRefsOptions) (*GetKnownRefsResult, error)
+	GetAuditLog(context.Context, *AuditLogOptions) (*AuditRecords, error)
+}
+
+func RegisterHostServer(s *grpc.Server, srv HostServer) {
+	s.RegisterService(&_Host_serviceDesc, srv)
+}
+
+func _Host_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HelloOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ServiceHostServer).Ping(ctx, in)
+		return srv.(HostServer).Hello(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/stonesthrow.ServiceHost/Ping",
+		FullMethod: "/stonesthrow.Host/Hello",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ServiceHostServer).Ping(ctx, req.(*PingOptions))
+		return srv.(HostServer).Hello(ctx, req.(*HelloOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ServiceHost_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListJobsOptions)
+func _Host_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ServiceHostServer).ListJobs(ctx, in)
+		return srv.(HostServer).Ping(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/stonesthrow.ServiceHost/ListJobs",
+		FullMethod: "/stonesthrow.Host/Ping",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ServiceHostServer).ListJobs(ctx, req.(*ListJobsOptions))
+		return srv.(HostServer).Ping(ctx, req.(*PingOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ServiceHost_KillJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+func _Host_ListJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListJobsOptions)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HostServer).ListJobs(m, &hostListJobsServer{stream})
+}
+
+type Host_ListJobsServer interface {
+	Send(*ListJobsResult) error
+	grpc.ServerStream
+}
+
+type hostListJobsServer struct {
+	grpc.ServerStream
+}
+
+func (x *hostListJobsServer) Send(m *ListJobsResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Host_KillJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(KillJobsOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ServiceHostServer).KillJobs(m, &serviceHostKillJobsServer{stream})
+	return srv.(HostServer).KillJobs(m, &hostKillJobsServer{stream})
 }
 
-type ServiceHost_KillJobsServer interface {
-	Send(*JobEvent) error
+type Host_KillJobsServer interface {
+	Send(*KillJobsResult) error
 	grpc.ServerStream
 }
 
-type serviceHostKillJobsServer struct {
+type hostKillJobsServer struct {
 	grpc.ServerStream
 }
 
-func (x *serviceHostKillJobsServer) Send(m *JobEvent) error {
+func (x *hostKillJobsServer) Send(m *KillJobsResult) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _ServiceHost_Shutdown_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ShutdownOptions)
+func _Host_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.Host/Shutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServer).Shutdown(ctx, req.(*ShutdownOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Host_SelfUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelfUpdateOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServer).SelfUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.Host/SelfUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServer).SelfUpdate(ctx, req.(*SelfUpdateOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Host_RunCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HostServer).RunCommand(&hostRunCommandServer{stream})
+}
+
+type Host_RunCommandServer interface {
+	Send(*TaskEvent) error
+	Recv() (*CommandInput, error)
+	grpc.ServerStream
+}
+
+type hostRunCommandServer struct {
+	grpc.ServerStream
+}
+
+func (x *hostRunCommandServer) Send(m *TaskEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *hostRunCommandServer) Recv() (*CommandInput, error) {
+	m := new(CommandInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Host_ListCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommandsOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServer).ListCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.Host/ListCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServer).ListCommands(ctx, req.(*ListCommandsOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Host_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchFileOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ServiceHostServer).Shutdown(m, &serviceHostShutdownServer{stream})
+	return srv.(HostServer).FetchFile(m, &hostFetchFileServer{stream})
 }
 
-type ServiceHost_ShutdownServer interface {
-	Send(*JobEvent) error
+type Host_FetchFileServer interface {
+	Send(*FetchFileResult) error
 	grpc.ServerStream
 }
 
-type serviceHostShutdownServer struct {
+type hostFetchFileServer struct {
 	grpc.ServerStream
 }
 
-func (x *serviceHostShutdownServer) Send(m *JobEvent) error {
+func (x *hostFetchFileServer) Send(m *FetchFileResult) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _ServiceHost_SelfUpdate_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SelfUpdateOptions)
+func _Host_ExtractRefs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExtractRefsOptions)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ServiceHostServer).SelfUpdate(m, &serviceHostSelfUpdateServer{stream})
+	return srv.(HostServer).ExtractRefs(m, &hostExtractRefsServer{stream})
 }
 
-type ServiceHost_SelfUpdateServer interface {
-	Send(*JobEvent) error
+type Host_ExtractRefsServer interface {
+	Send(*ExtractRefsResult) error
 	grpc.ServerStream
 }
 
-type serviceHostSelfUpdateServer struct {
+type hostExtractRefsServer struct {
 	grpc.ServerStream
 }
 
-func (x *serviceHostSelfUpdateServer) Send(m *JobEvent) error {
+func (x *hostExtractRefsServer) Send(m *ExtractRefsResult) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-var _ServiceHost_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "stonesthrow.ServiceHost",
-	HandlerType: (*ServiceHostServer)(nil),
+func _Host_ApplyRefs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HostServer).ApplyRefs(&hostApplyRefsServer{stream})
+}
+
+type Host_ApplyRefsServer interface {
+	SendAndClose(*ApplyRefsResult) error
+	Recv() (*ApplyRefsOptions, error)
+	grpc.ServerStream
+}
+
+type hostApplyRefsServer struct {
+	grpc.ServerStream
+}
+
+func (x *hostApplyRefsServer) SendAndClose(m *ApplyRefsResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *hostApplyRefsServer) Recv() (*ApplyRefsOptions, error) {
+	m := new(ApplyRefsOptions)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Host_GetKnownRefs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKnownRefsOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServer).GetKnownRefs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.Host/GetKnownRefs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServer).GetKnownRefs(ctx, req.(*GetKnownRefsOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Host_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stonesthrow.Host/GetAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServer).GetAuditLog(ctx, req.(*AuditLogOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Host_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "stonesthrow.Host",
+	HandlerType: (*HostServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Hello",
+			Handler:    _Host_Hello_Handler,
+		},
 		{
 			MethodName: "Ping",
-			Handler:    _ServiceHost_Ping_Handler,
+			Handler:    _Host_Ping_Handler,
 		},
 		{
-			MethodName: "ListJobs",
-			Handler:    _ServiceHost_ListJobs_Handler,
+			MethodName: "Shutdown",
+			Handler:    _Host_Shutdown_Handler,
+		},
+		{
+			MethodName: "SelfUpdate",
+			Handler:    _Host_SelfUpdate_Handler,
+		},
+		{
+			MethodName: "ListCommands",
+			Handler:    _Host_ListCommands_Handler,
+		},
+		{
+			MethodName: "GetKnownRefs",
+			Handler:    _Host_GetKnownRefs_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _Host_GetAuditLog_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListJobs",
+			Handler:       _Host_ListJobs_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "KillJobs",
-			Handler:       _ServiceHost_KillJobs_Handler,
+			Handler:       _Host_KillJobs_Handler,
 			ServerStreams: true,
 		},
 		{
-			StreamName:    "Shutdown",
-			Handler:       _ServiceHost_Shutdown_Handler,
+			StreamName:    "RunCommand",
+			Handler:       _Host_RunCommand_Handler,
 			ServerStreams: true,
+			ClientStreams: true,
 		},
 		{
-			StreamName:    "SelfUpdate",
-			Handler:       _ServiceHost_SelfUpdate_Handler,
+			StreamName:    "FetchFile",
+			Handler:       _Host_FetchFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExtractRefs",
+			Handler:       _Host_ExtractRefs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ApplyRefs",
+			Handler:       _Host_ApplyRefs_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "st.proto",
 }
@@ -2210,115 +4473,199 @@ var _ServiceHost_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("st.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1753 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xcd, 0x72, 0x23, 0x49,
-	0x11, 0x9e, 0x96, 0x2c, 0xb9, 0x95, 0xf2, 0x58, 0x52, 0xcd, 0xec, 0x8e, 0x56, 0x2c, 0x63, 0x6f,
-	0x2f, 0xb0, 0xc3, 0x2e, 0xa1, 0x05, 0x4f, 0x40, 0x30, 0x43, 0x00, 0xe1, 0x1f, 0xd9, 0x33, 0xb3,
-	0xc3, 0xda, 0x5b, 0xb2, 0x39, 0xec, 0x45, 0xd1, 0xea, 0x2e, 0x4b, 0xcd, 0xb4, 0xaa, 0x3a, 0xaa,
-	0xaa, 0xbd, 0xe1, 0x3d, 0x73, 0xe0, 0xcc, 0x81, 0x20, 0x82, 0x3b, 0x07, 0x2e, 0x44, 0xf0, 0x0e,
-	0xbc, 0x02, 0x11, 0xbc, 0x05, 0xc1, 0x81, 0x33, 0x51, 0x3f, 0xad, 0xfe, 0x91, 0x3d, 0xf6, 0x0e,
-	0x73, 0xe0, 0x56, 0x99, 0x9d, 0xf9, 0x55, 0x66, 0x56, 0x66, 0x56, 0x56, 0x83, 0x2b, 0xe4, 0x30,
-	0xe1, 0x4c, 0x32, 0xd4, 0x16, 0x92, 0x51, 0x22, 0xe4, 0x9c, 0xb3, 0xaf, 0x06, 0x0f, 0x67, 0x8c,
-	0xcd, 0x62, 0xf2, 0xa9, 0xfe, 0x34, 0x4d, 0xcf, 0x3f, 0x0d, 0x53, 0xee, 0xcb, 0x88, 0x51, 0x23,
-	0x3c, 0xd8, 0xaa, 0x7e, 0x97, 0xd1, 0x82, 0x08, 0xe9, 0x2f, 0x12, 0x23, 0xe0, 0x7d, 0x09, 0x1b,
-	0xe3, 0x39, 0x89, 0xe3, 0x7d, 0xb6, 0x58, 0xf8, 0x34, 0x44, 0x7d, 0x58, 0x0f, 0xcc, 0xb2, 0xef,
-	0x6c, 0xd7, 0x1f, 0xb5, 0x70, 0x46, 0xa2, 0xf7, 0xa1, 0x15, 0x46, 0x9c, 0x04, 0x92, 0xf1, 0xcb,
-	0x7e, 0x6d, 0xdb, 0x79, 0xd4, 0xc2, 0x39, 0x03, 0x21, 0x58, 0x9b, 0x33, 0x21, 0xfb, 0x75, 0xfd,
-	0x41, 0xaf, 0xbd, 0x5f, 0x41, 0x07, 0x93, 0x84, 0x89, 0x48, 0x49, 0x8c, 0xa5, 0x2f, 0x09, 0x7a,
-	0x08, 0xc0, 0x97, 0x2c, 0x8b, 0x52, 0xe0, 0xa0, 0x01, 0xb8, 0x9c, 0x5c, 0x44, 0x22, 0x62, 0xd4,
-	0x42, 0x2d, 0x69, 0xef, 0x0f, 0x0e, 0xb8, 0x38, 0xa5, 0x06, 0xe8, 0x09, 0x80, 0x90, 0x3e, 0x97,
-	0x13, 0xe5, 0x50, 0xdf, 0xd9, 0x76, 0x1e, 0xb5, 0x77, 0x06, 0x43, 0xe3, 0xed, 0x30, 0xf3, 0x76,
-	0x78, 0x9a, 0x79, 0x8b, 0x5b, 0x5a, 0x5a, 0xd1, 0xca, 0x45, 0x9e, 0x52, 0x1a, 0xd1, 0x99, 0x36,
-	0xc0, 0xc5, 0x19, 0x89, 0x7e, 0x0c, 0x2e, 0xa1, 0xa1, 0x81, 0xac, 0xdf, 0x08, 0xb9, 0x4e, 0x68,
-	0xa8, 0x28, 0xef, 0xdf, 0x0e, 0xc0, 0x5e, 0x1a, 0xc5, 0x21, 0xe1, 0x2f, 0xd8, 0x14, 0x6d, 0x42,
-	0x2d, 0x0a, 0xb5, 0x49, 0x0d, 0x5c, 0x8b, 0x42, 0xf4, 0x38, 0x0f, 0x69, 0x4d, 0x83, 0xbe, 0x37,
-	0x2c, 0x1c, 0xe1, 0xb0, 0x18, 0xfe, 0x3c, 0xda, 0x9f, 0x40, 0x43, 0x28, 0x47, 0xad, 0x1d, 0xef,
-	0x94, 0x54, 0xb2, 0x28, 0x60, 0x23, 0x83, 0x9e, 0x42, 0x5b, 0x5c, 0x0a, 0x49, 0x16, 0xc6, 0xf4,
-	0x35, 0xbb, 0x4b, 0xd5, 0xf4, 0x03, 0x9b, 0x1b, 0x18, 0x8c, 0xb4, 0x8e, 0xc6, 0x4f, 0xa0, 0x95,
-	0x0a, 0xc2, 0x8d, 0x66, 0xe3, 0x26, 0x4d, 0x57, 0xc9, 0x6a, 0xa7, 0x9f, 0x42, 0x3b, 0xf7, 0x59,
-	0xa0, 0x4f, 0x60, 0xed, 0x37, 0x6c, 0x2a, 0x74, 0xd2, 0xb4, 0x77, 0x1e, 0x94, 0xcc, 0xcd, 0xe5,
-	0xb0, 0x16, 0xf2, 0xfe, 0xb2, 0x06, 0xbd, 0xa3, 0x48, 0xe6, 0xc9, 0xf1, 0x9c, 0x9e, 0xb3, 0x4a,
-	0x6e, 0x38, 0x2b, 0xb9, 0xb1, 0x0b, 0xee, 0x94, 0xfb, 0x34, 0x98, 0x13, 0xd1, 0xaf, 0xe9, 0x6d,
-	0xbe, 0x5b, 0xda, 0x66, 0x05, 0x71, 0xb8, 0xa7, 0xc5, 0xf1, 0x52, 0x0d, 0x8d, 0xa0, 0x95, 0x26,
-	0x42, 0x72, 0xe2, 0x2f, 0x44, 0xbf, 0xae, 0x31, 0x3e, 0xba, 0x01, 0xe3, 0xcc, 0xca, 0xe3, 0x5c,
-	0x73, 0xf0, 0xfb, 0x1a, 0x34, 0x0d, 0xb6, 0xca, 0x7b, 0xea, 0xdb, 0x0c, 0x6c, 0x61, 0xbd, 0x2e,
-	0x25, 0x71, 0xad, 0x9c, 0xc4, 0xe8, 0x23, 0xe8, 0x64, 0x6b, 0x31, 0xf1, 0xe7, 0xc4, 0x0f, 0xf5,
-	0x09, 0x37, 0xf0, 0xe6, 0x92, 0xbd, 0xab, 0xb8, 0xe8, 0xfb, 0xd0, 0xcd, 0x05, 0xa7, 0x64, 0x1e,
-	0xd1, 0x50, 0x1f, 0x6c, 0x03, 0xe7, 0x00, 0x7b, 0x9a, 0x8d, 0x9e, 0x43, 0x33, 0x60, 0xf4, 0x3c,
-	0x9a, 0xf5, 0x1b, 0xda, 0xa5, 0x1f, 0xdd, 0x2a, 0x2c, 0xc3, 0x7d, 0xad, 0x33, 0xa2, 0x92, 0x5f,
-	0x62, 0x0b, 0x30, 0x78, 0x02, 0xed, 0x02, 0x1b, 0x75, 0xa1, 0xfe, 0x8a, 0x64, 0x67, 0xa1, 0x96,
-	0xe8, 0x3e, 0x34, 0x2e, 0xfc, 0x38, 0x25, 0xd6, 0x31, 0x43, 0x3c, 0xad, 0xfd, 0xd4, 0x19, 0xfc,
-	0x1a, 0xdc, 0x2c, 0x56, 0x57, 0x46, 0xe5, 0x3d, 0x70, 0x93, 0x54, 0xcc, 0x27, 0x29, 0x8f, 0xad,
-	0xf2, 0xba, 0xa2, 0xcf, 0x78, 0x8c, 0xbe, 0x05, 0xad, 0x73, 0x22, 0x03, 0xf3, 0xcd, 0x96, 0xbd,
-	0x66, 0x9c, 0xf1, 0xd8, 0xfb, 0xa3, 0x03, 0xee, 0x4b, 0x36, 0x1b, 0x5d, 0x10, 0x2a, 0x97, 0x6d,
-	0xc6, 0xc9, 0xdb, 0x8c, 0x32, 0x72, 0x21, 0x66, 0x16, 0x53, 0x2d, 0xd1, 0x53, 0x70, 0x05, 0xb9,
-	0x20, 0x3c, 0x92, 0x97, 0x1a, 0x6e, 0x73, 0xe7, 0x61, 0x29, 0x24, 0x19, 0xdc, 0x70, 0x6c, 0xa5,
-	0xf0, 0x52, 0xde, 0xfb, 0x18, 0xdc, 0x8c, 0x8b, 0x5a, 0xd0, 0x18, 0x61, 0x7c, 0x8c, 0xbb, 0x77,
-	0x90, 0x0b, 0x6b, 0xcf, 0x3f, 0x3f, 0x3c, 0xee, 0x3a, 0x8a, 0x79, 0x30, 0xda, 0x3b, 0x3b, 0xea,
-	0xd6, 0xbc, 0x67, 0xd0, 0xdb, 0x23, 0xb3, 0x88, 0xda, 0xea, 0x35, 0x26, 0x3e, 0x2e, 0x76, 0xd0,
-	0x5b, 0x96, 0xbb, 0xf7, 0x3b, 0x07, 0x90, 0x65, 0x1e, 0xa7, 0x32, 0x49, 0xa5, 0xc1, 0xfa, 0x05,
-	0x34, 0x4d, 0x44, 0x35, 0xd4, 0xe6, 0xce, 0xf7, 0x4a, 0x50, 0xab, 0x0a, 0xc3, 0xb1, 0xc9, 0x55,
-	0xab, 0x85, 0xde, 0x85, 0x26, 0xd3, 0x5f, 0x6d, 0x74, 0x2c, 0xe5, 0x0d, 0xa0, 0x69, 0x24, 0xd1,
-	0x3a, 0xd4, 0x8f, 0xcf, 0x4e, 0xbb, 0x77, 0xd4, 0x62, 0x84, 0x71, 0xd7, 0xf1, 0xfe, 0xec, 0x40,
-	0x67, 0x44, 0xc3, 0x92, 0x4f, 0x5b, 0xd0, 0xe6, 0x44, 0xa6, 0x9c, 0x4e, 0x02, 0x16, 0x12, 0xdb,
-	0xdb, 0xc0, 0xb0, 0xf6, 0x59, 0xb8, 0xd2, 0x81, 0x6a, 0x6f, 0xdc, 0x81, 0xea, 0xb7, 0xef, 0x40,
-	0x7f, 0x77, 0x00, 0x1d, 0x45, 0xd2, 0x64, 0xf3, 0xa9, 0x2f, 0x5e, 0x19, 0x5b, 0xdf, 0x85, 0xa6,
-	0xa9, 0x77, 0x9b, 0x24, 0x96, 0x52, 0xb1, 0xe4, 0x44, 0xa4, 0xb1, 0x89, 0x45, 0x35, 0x96, 0xab,
-	0x40, 0x43, 0xac, 0xa5, 0xb1, 0xd5, 0x7a, 0xdd, 0xd5, 0xa4, 0xf6, 0xe4, 0xc4, 0x17, 0x8c, 0xea,
-	0x12, 0x6d, 0x61, 0x4b, 0x79, 0x1f, 0x42, 0xd3, 0xa0, 0xa0, 0xbb, 0xd0, 0x1a, 0x9f, 0xed, 0xef,
-	0x8f, 0x46, 0x07, 0xa3, 0x83, 0xee, 0x1d, 0x04, 0xd0, 0x3c, 0xdc, 0x7d, 0xfe, 0x72, 0x74, 0xd0,
-	0x75, 0xbc, 0x47, 0x80, 0xbe, 0x8c, 0x92, 0x84, 0x84, 0xfb, 0x8c, 0x4a, 0x42, 0xe5, 0x32, 0xd3,
-	0x43, 0x5f, 0xfa, 0xda, 0x89, 0x0d, 0xac, 0xd7, 0xde, 0xbf, 0xea, 0xe0, 0xbe, 0x60, 0x53, 0x23,
-	0x30, 0x84, 0xb5, 0x5b, 0xde, 0x7d, 0x5a, 0x0e, 0xed, 0x40, 0x2b, 0x66, 0xb3, 0x09, 0x51, 0xca,
-	0xf6, 0x80, 0xde, 0xb9, 0xb2, 0x2a, 0xb0, 0x1b, 0x67, 0xe5, 0xf6, 0x39, 0xdc, 0x9b, 0xaa, 0x04,
-	0x9f, 0xd8, 0x3c, 0xb5, 0xda, 0xe6, 0x90, 0xca, 0x35, 0xb5, 0x52, 0x08, 0xb8, 0x37, 0x5d, 0xa9,
-	0x8d, 0x2f, 0xe0, 0x7e, 0x86, 0x64, 0x32, 0xd1, 0x02, 0x9a, 0x1b, 0x6b, 0xeb, 0x86, 0xec, 0xc6,
-	0x28, 0x58, 0x2d, 0x91, 0x67, 0xd0, 0x53, 0x77, 0x76, 0xd9, 0x40, 0x73, 0x8f, 0xbd, 0x5f, 0xc2,
-	0xab, 0xe4, 0x34, 0xee, 0x90, 0x4a, 0x92, 0x7f, 0x06, 0x3d, 0x93, 0x2a, 0x13, 0xe9, 0x8b, 0x57,
-	0x16, 0xa9, 0x79, 0x85, 0x65, 0xab, 0xb9, 0x82, 0x3b, 0xd3, 0x4a, 0x16, 0x1e, 0xc2, 0xe6, 0xd7,
-	0xfa, 0x50, 0x27, 0x81, 0x39, 0xd5, 0xfe, 0xfa, 0x15, 0x48, 0xab, 0xe7, 0x8e, 0xef, 0x7e, 0x5d,
-	0xe4, 0x79, 0x07, 0x00, 0x66, 0xaf, 0x97, 0x91, 0x90, 0x37, 0x5e, 0x91, 0x79, 0xee, 0xd7, 0xf4,
-	0xf0, 0x66, 0x29, 0xef, 0x1f, 0x0e, 0x00, 0x4e, 0xe9, 0x71, 0xa2, 0x4a, 0x48, 0xdc, 0x08, 0xf3,
-	0xba, 0x0b, 0x6c, 0x00, 0x6e, 0x12, 0xfb, 0xf2, 0x9c, 0xf1, 0x45, 0x56, 0x06, 0x19, 0x8d, 0x7e,
-	0x06, 0x1b, 0x21, 0x49, 0x08, 0x0d, 0x09, 0x0d, 0x22, 0x22, 0xec, 0xb1, 0x96, 0x87, 0x81, 0x53,
-	0x9f, 0xcf, 0x88, 0x54, 0xde, 0xe0, 0x92, 0x70, 0xb1, 0x6f, 0x36, 0x6e, 0xdd, 0x37, 0x3f, 0x80,
-	0xf6, 0x49, 0x44, 0x67, 0x99, 0x63, 0x08, 0xd6, 0x12, 0x35, 0xd7, 0xd9, 0xeb, 0x41, 0xad, 0xbd,
-	0x6d, 0x00, 0x25, 0x62, 0xeb, 0x50, 0x49, 0xb0, 0x82, 0x04, 0xa3, 0x33, 0xef, 0x6f, 0x0e, 0x74,
-	0x0f, 0xd5, 0x75, 0x73, 0x18, 0xc5, 0xe4, 0x1b, 0xc4, 0x68, 0x19, 0x87, 0x5a, 0x25, 0x0e, 0x1f,
-	0xc2, 0x5d, 0x4e, 0x62, 0x5f, 0x46, 0x17, 0x64, 0x92, 0xf8, 0x72, 0x6e, 0x03, 0xb5, 0x91, 0x31,
-	0x4f, 0x7c, 0x39, 0x57, 0x42, 0xe7, 0x51, 0x4c, 0xd4, 0xdd, 0x38, 0x99, 0xc5, 0x6c, 0x6a, 0x5b,
-	0xc7, 0x46, 0xc6, 0x3c, 0x8a, 0xd9, 0x54, 0xcf, 0xaa, 0x24, 0x48, 0xb9, 0x30, 0xb3, 0x99, 0x9a,
-	0x55, 0x0d, 0xe9, 0xfd, 0xd6, 0x81, 0x7b, 0x26, 0x33, 0xcc, 0x85, 0x7d, 0x5b, 0xbb, 0xb7, 0xa0,
-	0x6d, 0xb3, 0x5c, 0x24, 0x24, 0xc8, 0x46, 0x70, 0xc3, 0x1a, 0x27, 0x24, 0x40, 0x3f, 0x00, 0x14,
-	0xd1, 0x20, 0x4e, 0x43, 0x32, 0x99, 0x45, 0x72, 0x62, 0x27, 0x8b, 0xba, 0xde, 0xbd, 0x6b, 0xbf,
-	0x1c, 0x45, 0xd2, 0xec, 0xea, 0x7d, 0x01, 0xf7, 0xd4, 0x59, 0xda, 0x83, 0x11, 0x6f, 0x21, 0x7a,
-	0xde, 0x9f, 0x1c, 0x58, 0xcf, 0x9e, 0x23, 0xf9, 0x20, 0x51, 0x5f, 0x0e, 0x12, 0xdb, 0xd0, 0x0e,
-	0x89, 0x08, 0x78, 0xa4, 0xf7, 0xb2, 0xea, 0x45, 0x96, 0x1a, 0x52, 0x52, 0xe1, 0xcf, 0x88, 0x8d,
-	0xbb, 0x21, 0xd0, 0xc7, 0xd0, 0x33, 0x09, 0x27, 0x26, 0x8c, 0x4e, 0x04, 0x4b, 0x79, 0x60, 0x66,
-	0x65, 0x17, 0x77, 0xec, 0x87, 0x63, 0x3a, 0xd6, 0x6c, 0x15, 0x77, 0x95, 0xef, 0xd3, 0x78, 0x19,
-	0x77, 0x4b, 0x7a, 0x3f, 0x57, 0x13, 0x92, 0x36, 0x4e, 0x57, 0xe4, 0xb0, 0xfc, 0x5e, 0x6a, 0xef,
-	0xdc, 0xbf, 0xaa, 0x89, 0xe5, 0x09, 0x7b, 0x02, 0x48, 0xe9, 0x99, 0x2a, 0x78, 0x2b, 0xe1, 0xfa,
-	0x0e, 0x40, 0x5e, 0x53, 0xaa, 0x03, 0x48, 0x4d, 0xd9, 0x90, 0x59, 0xca, 0xeb, 0x41, 0x47, 0x7d,
-	0x57, 0xb3, 0xba, 0xdd, 0xd4, 0xfb, 0x00, 0x3a, 0x9f, 0x45, 0x71, 0x5c, 0x60, 0x2d, 0x9f, 0x2e,
-	0x75, 0xf3, 0x74, 0x51, 0x5a, 0xe3, 0x79, 0x2a, 0x43, 0xf6, 0x55, 0xd6, 0x3b, 0xbc, 0x7b, 0xd0,
-	0x1b, 0x93, 0xf8, 0xfc, 0x2c, 0x09, 0x7d, 0x99, 0x15, 0xcb, 0xce, 0x7f, 0x6a, 0xd0, 0xd2, 0x53,
-	0xfe, 0x33, 0x35, 0x90, 0x1d, 0x40, 0x57, 0xbd, 0x50, 0xf4, 0x71, 0x64, 0x07, 0xf9, 0xa0, 0xfa,
-	0x80, 0xb1, 0xaa, 0x83, 0xf2, 0x1d, 0x94, 0xdd, 0x6e, 0x3f, 0x74, 0x90, 0x8d, 0x54, 0x09, 0x46,
-	0xa0, 0xed, 0xf2, 0x95, 0xb5, 0x9a, 0x7a, 0x83, 0xfe, 0x55, 0x07, 0xa0, 0x63, 0x73, 0x04, 0xed,
-	0x42, 0xec, 0xd1, 0xd6, 0x0a, 0x54, 0xf9, 0x54, 0x06, 0xd7, 0x35, 0x2e, 0xb4, 0x0f, 0x1d, 0xe5,
-	0x60, 0xf1, 0xdd, 0xfc, 0xcd, 0xfd, 0xdb, 0x87, 0xd6, 0xb2, 0xe9, 0xa0, 0x6f, 0x97, 0xa4, 0xaa,
-	0xcd, 0xe8, 0x5a, 0x90, 0x9d, 0xbf, 0x36, 0x61, 0x33, 0x9f, 0xee, 0xff, 0xaf, 0xa3, 0xff, 0x56,
-	0x82, 0x36, 0x86, 0xce, 0x11, 0x91, 0xc5, 0xbe, 0x57, 0xb1, 0xe9, 0x8a, 0x96, 0x38, 0x78, 0xf8,
-	0xfa, 0xf7, 0x10, 0x7a, 0x01, 0x9d, 0x71, 0x05, 0xf4, 0x06, 0x95, 0xeb, 0x0d, 0x3c, 0x80, 0xee,
-	0x49, 0x1a, 0xc7, 0x87, 0x9c, 0x2d, 0x96, 0xaf, 0xa1, 0x07, 0x57, 0x58, 0xa8, 0x42, 0x72, 0x3d,
-	0xca, 0x1e, 0x6c, 0x9e, 0xa4, 0x62, 0x7e, 0xca, 0xfe, 0x07, 0x8c, 0x5f, 0xaa, 0x19, 0xdf, 0x97,
-	0xa9, 0x40, 0xe5, 0x39, 0xa8, 0xf2, 0x4b, 0xe6, 0x75, 0x09, 0x0a, 0xe3, 0x4b, 0x1a, 0x60, 0xb2,
-	0x60, 0x92, 0xbc, 0x29, 0xc8, 0x0b, 0xe8, 0x9d, 0x70, 0x92, 0xf8, 0x9c, 0x1c, 0x32, 0x8e, 0x49,
-	0x40, 0xa2, 0x0b, 0xf2, 0xe6, 0x06, 0xbd, 0x85, 0x8a, 0xf9, 0x67, 0x0d, 0xda, 0x63, 0xc2, 0x2f,
-	0xa2, 0x80, 0xe8, 0x72, 0x79, 0x02, 0x6b, 0x6a, 0x3c, 0x40, 0xe5, 0xc4, 0x2d, 0x0c, 0x15, 0x95,
-	0x36, 0x50, 0x98, 0x25, 0xf6, 0xc0, 0xcd, 0x7a, 0x6a, 0xc5, 0xa5, 0x4a, 0xab, 0xad, 0x54, 0x45,
-	0xf1, 0xbf, 0xc9, 0x2e, 0xb8, 0x59, 0x13, 0xae, 0x60, 0x54, 0x7a, 0xf3, 0xf5, 0x61, 0xd9, 0x05,
-	0x37, 0x6b, 0xd2, 0x15, 0x88, 0x4a, 0xef, 0xbe, 0x1e, 0x62, 0x04, 0x90, 0x37, 0xf5, 0x4a, 0xf2,
-	0xaf, 0x74, 0xfb, 0x6b, 0x61, 0xa6, 0x4d, 0xfd, 0xf8, 0x78, 0xfc, 0xdf, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x99, 0x27, 0x21, 0xb0, 0xad, 0x14, 0x00, 0x00,
+	// 3104 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x3a, 0xcd, 0x73, 0xdb, 0xc6,
+	0xf5, 0x01, 0x3f, 0xc1, 0x47, 0x5a, 0xa4, 0xd6, 0x8e, 0x43, 0xd3, 0x8e, 0xa5, 0x1f, 0xf2, 0x4b,
+	0xa3, 0xd4, 0x1d, 0xc6, 0x91, 0x9b, 0x2f, 0xb7, 0xf9, 0x90, 0x25, 0x4a, 0x96, 0xec, 0x5a, 0x0a,
+	0x68, 0x27, 0x33, 0xbd, 0xb0, 0x20, 0xb0, 0x22, 0x61, 0x83, 0x58, 0x0e, 0x76, 0x21, 0x5b, 0x3e,
+	0xf7, 0x2f, 0xe8, 0xb1, 0xe9, 0xa5, 0xd3, 0xe9, 0xa5, 0xb7, 0x74, 0xa6, 0xd3, 0x43, 0xcf, 0x3d,
+	0xf5, 0xd2, 0x43, 0x4f, 0xfd, 0x0f, 0x7a, 0xe8, 0xb1, 0xf7, 0xce, 0x7e, 0x00, 0x04, 0xc0, 0x0f,
+	0x51, 0xb6, 0x67, 0x92, 0x99, 0xde, 0xf0, 0xde, 0xbe, 0x7d, 0xfb, 0xf6, 0xed, 0xfb, 0x26, 0x41,
+	0xa7, 0xac, 0x3d, 0x0e, 0x08, 0x23, 0xa8, 0x4a, 0x19, 0xf1, 0x31, 0x65, 0xc3, 0x80, 0x3c, 0x6d,
+	0x5d, 0x1f, 0x10, 0x32, 0xf0, 0xf0, 0x7b, 0x62, 0xa9, 0x1f, 0x1e, 0xbf, 0xe7, 0x84, 0x81, 0xc5,
+	0x5c, 0xe2, 0x4b, 0xe2, 0xd6, 0x5a, 0x76, 0x9d, 0xb9, 0x23, 0x4c, 0x99, 0x35, 0x1a, 0x4b, 0x02,
+	0xe3, 0x9f, 0x1a, 0xd4, 0xba, 0x43, 0xec, 0x79, 0xdb, 0x64, 0x34, 0xb2, 0x7c, 0x07, 0x35, 0xa1,
+	0x6c, 0xcb, 0xcf, 0xa6, 0xb6, 0x9e, 0xdf, 0xa8, 0x98, 0x11, 0x88, 0xae, 0x41, 0xc5, 0x71, 0x03,
+	0x6c, 0x33, 0x12, 0x9c, 0x36, 0x73, 0xeb, 0xda, 0x46, 0xc5, 0x9c, 0x20, 0x10, 0x82, 0xc2, 0x90,
+	0x50, 0xd6, 0xcc, 0x8b, 0x05, 0xf1, 0x8d, 0x7e, 0x0c, 0x79, 0xec, 0x9f, 0x34, 0x0b, 0xeb, 0xf9,
+	0x8d, 0xea, 0xa6, 0xd1, 0x4e, 0x08, 0xde, 0x4e, 0x9e, 0xd9, 0xee, 0xf8, 0x27, 0x1d, 0x9f, 0x05,
+	0xa7, 0x26, 0x27, 0x47, 0x0d, 0xc8, 0x33, 0x76, 0xda, 0x2c, 0xae, 0x6b, 0x1b, 0xba, 0xc9, 0x3f,
+	0x5b, 0x1f, 0x82, 0x1e, 0x91, 0xf0, 0xd5, 0x27, 0xf8, 0xb4, 0xa9, 0x89, 0x63, 0xf8, 0x27, 0xba,
+	0x04, 0xc5, 0x13, 0xcb, 0x0b, 0xb1, 0x92, 0x49, 0x02, 0xb7, 0x73, 0x1f, 0x6b, 0xc6, 0xcf, 0xa0,
+	0x6e, 0xe2, 0x31, 0xa1, 0x2e, 0x97, 0xb0, 0xcb, 0x2c, 0x86, 0xd1, 0x75, 0x80, 0x20, 0x46, 0xa9,
+	0x1d, 0x09, 0x0c, 0x6a, 0x81, 0x1e, 0xe0, 0x13, 0x97, 0xba, 0xc4, 0x57, 0x57, 0x89, 0x61, 0xe3,
+	0xdf, 0x1a, 0xe8, 0x66, 0xe8, 0x4b, 0x46, 0x9f, 0x00, 0x50, 0x66, 0x05, 0xac, 0xc7, 0x35, 0x2a,
+	0xc4, 0xa9, 0x6e, 0xb6, 0xda, 0x52, 0xdd, 0xed, 0x48, 0xdd, 0xed, 0x87, 0x91, 0xba, 0xcd, 0x8a,
+	0xa0, 0xe6, 0x30, 0x57, 0x71, 0x10, 0xfa, 0xbe, 0xeb, 0x0f, 0x84, 0x00, 0xba, 0x19, 0x81, 0xe8,
+	0x03, 0xd0, 0xb1, 0xef, 0x48, 0x96, 0xf9, 0x33, 0x59, 0x96, 0xb1, 0xef, 0x08, 0x86, 0x6b, 0x50,
+	0x0d, 0x30, 0x0b, 0x03, 0xbf, 0x67, 0x13, 0x07, 0x37, 0x0b, 0xeb, 0xda, 0x46, 0xd1, 0x04, 0x89,
+	0xda, 0x26, 0x0e, 0x46, 0x97, 0xa1, 0x44, 0xdd, 0x81, 0x6f, 0x79, 0x42, 0xab, 0x45, 0x53, 0x41,
+	0xfc, 0x49, 0x6d, 0xcb, 0xb7, 0xb1, 0xe7, 0x61, 0xa7, 0x59, 0x12, 0xb2, 0x4c, 0x10, 0xc6, 0xdf,
+	0x72, 0x00, 0x77, 0x42, 0xd7, 0x73, 0x70, 0x70, 0x40, 0xfa, 0x68, 0x05, 0x72, 0xae, 0x23, 0x6e,
+	0x5a, 0x34, 0x73, 0xae, 0x83, 0x6e, 0x4d, 0x2c, 0x25, 0x27, 0x64, 0xbd, 0x32, 0xf7, 0x85, 0x27,
+	0x46, 0x74, 0x03, 0x8a, 0x94, 0xeb, 0x4f, 0x5d, 0xef, 0xf5, 0xd4, 0x96, 0x48, 0xb9, 0xa6, 0xa4,
+	0x41, 0xb7, 0xa1, 0x4a, 0x4f, 0x29, 0xc3, 0x23, 0xa9, 0x91, 0x82, 0x3a, 0x25, 0xab, 0x91, 0x1d,
+	0x65, 0xf3, 0x26, 0x48, 0x6a, 0xa1, 0x93, 0x0f, 0xa1, 0x12, 0x52, 0x1c, 0xc8, 0x9d, 0xc5, 0xb3,
+	0x76, 0xea, 0x9c, 0x56, 0xec, 0x4b, 0x1b, 0x48, 0x69, 0x96, 0x81, 0x8c, 0x3d, 0x8b, 0x1d, 0x93,
+	0x60, 0xd4, 0x2c, 0x4b, 0x03, 0x89, 0x60, 0x74, 0x15, 0x2a, 0x63, 0x2b, 0xc0, 0x3e, 0xeb, 0xb9,
+	0x4e, 0x53, 0x17, 0x8a, 0xd2, 0x25, 0x62, 0xdf, 0x31, 0x6e, 0x43, 0x75, 0xa2, 0x4c, 0x8a, 0x6e,
+	0x40, 0xe1, 0x31, 0xe9, 0x53, 0xe1, 0x64, 0xd5, 0xcd, 0x37, 0x52, 0x7a, 0x98, 0xd0, 0x99, 0x82,
+	0xc8, 0xf8, 0x43, 0x01, 0x56, 0xf7, 0x5c, 0x36, 0x31, 0xe6, 0x7d, 0xff, 0x98, 0x64, 0x44, 0xd5,
+	0xa6, 0x44, 0xdd, 0x02, 0xbd, 0x1f, 0x58, 0xbe, 0x3d, 0xc4, 0xb4, 0x99, 0x13, 0xc7, 0xbc, 0x9d,
+	0x3a, 0x66, 0x8a, 0x63, 0xfb, 0x8e, 0x20, 0x37, 0xe3, 0x6d, 0xa8, 0x03, 0x95, 0x70, 0x4c, 0x59,
+	0x80, 0xad, 0x11, 0x6d, 0xe6, 0x05, 0x8f, 0x77, 0xce, 0xe0, 0xf1, 0x48, 0xd1, 0x9b, 0x93, 0x9d,
+	0xad, 0x5f, 0xe5, 0xa0, 0x24, 0x79, 0xf3, 0x38, 0xe1, 0x5b, 0xca, 0x63, 0x2a, 0xa6, 0xf8, 0x4e,
+	0x39, 0x5d, 0x2e, 0xed, 0x74, 0xe8, 0x1d, 0xa8, 0x47, 0xdf, 0xb4, 0x67, 0x0d, 0xb1, 0xe5, 0x08,
+	0xd3, 0x29, 0x9a, 0x2b, 0x31, 0x7a, 0x8b, 0x63, 0xd1, 0xbb, 0xd0, 0x98, 0x10, 0xf6, 0xf1, 0xd0,
+	0xf5, 0x1d, 0xe5, 0x09, 0x13, 0x06, 0x77, 0x04, 0x1a, 0xed, 0x43, 0xc9, 0x26, 0xfe, 0xb1, 0x3b,
+	0x68, 0x16, 0xc5, 0x95, 0xde, 0x5f, 0x4a, 0x2d, 0xed, 0x6d, 0xb1, 0x47, 0x46, 0x2a, 0xc5, 0xa0,
+	0xf5, 0x09, 0x54, 0x13, 0xe8, 0xf3, 0x44, 0xa7, 0xd6, 0x57, 0xa0, 0x47, 0xba, 0x9a, 0xa9, 0x95,
+	0x2b, 0xa0, 0x8f, 0x43, 0x3a, 0xec, 0x85, 0x81, 0xa7, 0x36, 0x97, 0x39, 0xfc, 0x28, 0xf0, 0xb8,
+	0xa1, 0x1d, 0x63, 0x66, 0xcb, 0x35, 0x15, 0xa6, 0x04, 0xe2, 0x51, 0xe0, 0x19, 0xbf, 0xd7, 0x40,
+	0xbf, 0x4f, 0x06, 0x9d, 0x13, 0xec, 0xb3, 0x38, 0x2c, 0x6b, 0x89, 0xb0, 0xdc, 0x80, 0xfc, 0x88,
+	0x0e, 0x14, 0x4f, 0xfe, 0x89, 0x6e, 0x83, 0x4e, 0xf1, 0x09, 0x0e, 0x5c, 0x76, 0x2a, 0xd8, 0xad,
+	0x6c, 0x5e, 0x4f, 0xa9, 0x24, 0x62, 0xd7, 0xee, 0x2a, 0x2a, 0x33, 0xa6, 0x37, 0x3e, 0x02, 0x3d,
+	0xc2, 0xa2, 0x0a, 0x14, 0x3b, 0xa6, 0x79, 0x68, 0x36, 0x5e, 0x43, 0x3a, 0x14, 0xf6, 0x1f, 0xec,
+	0x1e, 0x36, 0x34, 0x8e, 0xdc, 0xe9, 0xdc, 0x79, 0xb4, 0xd7, 0xc8, 0xa1, 0x2a, 0x94, 0xbf, 0xde,
+	0x32, 0x1f, 0xec, 0x3f, 0xd8, 0x6b, 0xe4, 0x8d, 0x1e, 0xac, 0xde, 0xc1, 0x03, 0xd7, 0x57, 0x31,
+	0x42, 0xca, 0x7b, 0x2b, 0x99, 0x7e, 0x96, 0x0d, 0x2a, 0xaf, 0x43, 0xe9, 0x31, 0xe9, 0x73, 0xa7,
+	0xcb, 0x89, 0x07, 0x2f, 0x3e, 0x26, 0xfd, 0x7d, 0xc7, 0xf8, 0x8d, 0x06, 0x48, 0xd1, 0x1e, 0x86,
+	0x6c, 0x1c, 0x32, 0x79, 0xc4, 0x67, 0x50, 0x92, 0x5a, 0x17, 0x27, 0xac, 0x6c, 0xfe, 0x20, 0x75,
+	0xc2, 0xf4, 0x86, 0x76, 0x57, 0xda, 0xb3, 0xda, 0xc5, 0x83, 0x29, 0x11, 0xab, 0x4a, 0x83, 0x0a,
+	0xe2, 0xaa, 0x76, 0x2c, 0x66, 0x09, 0x05, 0xd6, 0x4c, 0xf1, 0x6d, 0xb4, 0xa0, 0x24, 0x77, 0xa3,
+	0x32, 0xe4, 0x0f, 0x1f, 0x3d, 0x6c, 0xbc, 0xc6, 0x3f, 0x3a, 0xa6, 0xd9, 0xd0, 0x8c, 0x5f, 0xe7,
+	0xa0, 0xde, 0xf1, 0x9d, 0xd4, 0xf5, 0x33, 0x91, 0x5c, 0x9b, 0x8a, 0xe4, 0x99, 0x90, 0x98, 0x7b,
+	0xe1, 0x90, 0x98, 0x5f, 0x3e, 0x24, 0xa6, 0xb2, 0x44, 0x21, 0x93, 0x25, 0xe6, 0xe6, 0x96, 0x35,
+	0xa8, 0xca, 0xaf, 0x9e, 0xb0, 0x6c, 0x15, 0x49, 0x25, 0xea, 0x01, 0xb7, 0xef, 0xab, 0x50, 0xe1,
+	0x92, 0x38, 0x3d, 0x12, 0x32, 0x11, 0x4a, 0x75, 0x53, 0x17, 0x88, 0xc3, 0x90, 0x19, 0x7f, 0xd5,
+	0x00, 0xed, 0xb9, 0x4c, 0x7a, 0xde, 0x43, 0x8b, 0x3e, 0x91, 0xfa, 0xb9, 0x0c, 0x25, 0x19, 0x9b,
+	0x94, 0x41, 0x2b, 0x88, 0xbf, 0x69, 0x80, 0x69, 0xe8, 0xc9, 0x37, 0xc9, 0xbe, 0xe9, 0x34, 0xa3,
+	0xb6, 0x29, 0xa8, 0x4d, 0xb5, 0x6b, 0x51, 0xda, 0xe7, 0x67, 0x06, 0xd8, 0xa2, 0xc4, 0x17, 0x77,
+	0xaf, 0x98, 0x0a, 0x32, 0xde, 0x82, 0x92, 0xe4, 0x82, 0x2e, 0x40, 0xa5, 0xfb, 0x68, 0x7b, 0xbb,
+	0xd3, 0xd9, 0xe9, 0xec, 0x34, 0x5e, 0x43, 0x00, 0xa5, 0xdd, 0xad, 0xfd, 0xfb, 0x9d, 0x9d, 0x86,
+	0x66, 0x6c, 0x00, 0xfa, 0xb9, 0x3b, 0x1e, 0x63, 0x67, 0x9b, 0xf8, 0x0c, 0xfb, 0x2c, 0xf6, 0x4a,
+	0x61, 0x2a, 0x5a, 0xc2, 0x54, 0xbe, 0x29, 0x80, 0x7e, 0x40, 0xfa, 0x92, 0xa0, 0x0d, 0x85, 0x25,
+	0xeb, 0x0a, 0x41, 0x87, 0x36, 0xa1, 0xe2, 0x91, 0x41, 0x0f, 0xf3, 0xcd, 0xcd, 0xdc, 0x8c, 0xd4,
+	0x1a, 0x79, 0xb0, 0xa9, 0x7b, 0xea, 0x0b, 0x3d, 0x80, 0x8b, 0x7d, 0xee, 0x7f, 0x3d, 0xe5, 0x46,
+	0x6a, 0xb7, 0x34, 0x8c, 0xb4, 0xff, 0x4f, 0xf9, 0xa9, 0xb9, 0xda, 0xcf, 0xa2, 0xd0, 0x97, 0x70,
+	0x29, 0xe2, 0x24, 0x3d, 0x42, 0x31, 0x94, 0x69, 0x7b, 0xed, 0x0c, 0x2f, 0x33, 0x91, 0x3d, 0x85,
+	0x43, 0x77, 0x61, 0x95, 0xd7, 0x43, 0x69, 0x01, 0x65, 0x32, 0xbf, 0x96, 0xe2, 0x97, 0xf1, 0x23,
+	0xb3, 0x8e, 0xd3, 0x08, 0x74, 0x0f, 0x56, 0xa5, 0xa9, 0xf4, 0x98, 0x45, 0x9f, 0x28, 0x4e, 0xa5,
+	0x19, 0x92, 0x4d, 0xdb, 0x8a, 0x59, 0xef, 0xa7, 0x11, 0x68, 0x17, 0x56, 0x9e, 0x8b, 0x47, 0xed,
+	0xd9, 0xf2, 0x55, 0x9b, 0xe5, 0x19, 0x9c, 0xa6, 0xdf, 0xdd, 0xbc, 0xf0, 0x3c, 0x89, 0x43, 0xef,
+	0x42, 0xfe, 0x31, 0xe9, 0x8b, 0x4a, 0x61, 0x41, 0x09, 0xc0, 0x69, 0x8c, 0x1d, 0x00, 0x29, 0xd6,
+	0x7d, 0x97, 0xb2, 0x33, 0x33, 0xff, 0xc4, 0x4d, 0x72, 0xa2, 0x86, 0x57, 0x90, 0xf1, 0xf7, 0x1c,
+	0x80, 0x19, 0xfa, 0x87, 0x63, 0xee, 0xe1, 0xf4, 0x4c, 0x36, 0x8b, 0xf2, 0x72, 0xb2, 0x0e, 0xca,
+	0x67, 0xea, 0xa0, 0x9f, 0x40, 0xcd, 0xc1, 0x63, 0xec, 0x3b, 0xd8, 0xb7, 0x5d, 0x4c, 0x9b, 0x85,
+	0x19, 0x17, 0x7c, 0x68, 0x05, 0x03, 0xcc, 0xf8, 0x6d, 0xcc, 0x14, 0x71, 0x32, 0x03, 0x14, 0x97,
+	0xce, 0x00, 0x97, 0xa1, 0xe4, 0x60, 0x66, 0xd9, 0x43, 0x55, 0xc5, 0x2a, 0x28, 0xea, 0x25, 0xca,
+	0x71, 0x2f, 0x81, 0x3e, 0x86, 0xea, 0x53, 0xd7, 0x77, 0xc8, 0xd3, 0x1e, 0x75, 0x9f, 0xe3, 0x99,
+	0xba, 0xff, 0x5a, 0xac, 0x77, 0xdd, 0xe7, 0xd8, 0x84, 0xa7, 0xf1, 0x37, 0xcf, 0xe4, 0x94, 0x1f,
+	0xde, 0xac, 0x08, 0x6e, 0x12, 0x30, 0x6e, 0x03, 0x4c, 0xe8, 0xb9, 0x63, 0x07, 0xe4, 0x29, 0x55,
+	0x81, 0x5b, 0x7c, 0xcb, 0x8e, 0xca, 0x0b, 0x47, 0x3e, 0x55, 0xe9, 0x29, 0x02, 0x8d, 0x3f, 0x6a,
+	0x50, 0x53, 0x57, 0xd9, 0xf7, 0x79, 0x0a, 0x79, 0x1f, 0xca, 0x44, 0xbe, 0x4d, 0x53, 0x9b, 0x21,
+	0xd8, 0xe4, 0xe9, 0xcc, 0x88, 0x4e, 0x48, 0xc5, 0x1c, 0x57, 0x3e, 0x50, 0xcd, 0x94, 0x00, 0x0f,
+	0xbe, 0xb6, 0x47, 0x28, 0xee, 0xc9, 0xb5, 0xbc, 0x90, 0x18, 0x04, 0xaa, 0x2b, 0x08, 0x32, 0x6a,
+	0x28, 0x2c, 0xad, 0x06, 0xe3, 0xb7, 0x1a, 0xd4, 0xee, 0x62, 0xcf, 0x23, 0x91, 0x15, 0xbd, 0x0b,
+	0x0d, 0x11, 0x97, 0x6c, 0xe2, 0xf5, 0x4e, 0x70, 0x20, 0xac, 0x45, 0xde, 0xbf, 0x1e, 0xe1, 0xbf,
+	0x92, 0x68, 0x74, 0x13, 0x2e, 0x8d, 0x5c, 0xbf, 0x37, 0x45, 0x2e, 0xf5, 0x82, 0x46, 0xae, 0x7f,
+	0x94, 0xd9, 0xd1, 0x84, 0x72, 0x44, 0x24, 0xad, 0x2c, 0x02, 0x53, 0xc6, 0x59, 0xc8, 0x74, 0x6a,
+	0x7f, 0xce, 0x41, 0x55, 0xc8, 0xa8, 0x02, 0xf4, 0xf7, 0x4f, 0x44, 0x64, 0x40, 0xcd, 0xb6, 0xc6,
+	0x56, 0xdf, 0xf5, 0x5c, 0xc6, 0x7d, 0xa4, 0x28, 0x1c, 0x35, 0x85, 0x8b, 0x8b, 0xb7, 0x52, 0xa2,
+	0x78, 0x5b, 0x81, 0x1c, 0xa1, 0xaa, 0xf3, 0xc8, 0x11, 0x41, 0x63, 0x05, 0xf6, 0x50, 0x18, 0x72,
+	0xc5, 0x14, 0xdf, 0x99, 0xde, 0xb4, 0x72, 0x8e, 0xde, 0xd4, 0xe8, 0x41, 0xad, 0x13, 0x04, 0x24,
+	0xd8, 0xc1, 0xcc, 0x72, 0x3d, 0xca, 0x0d, 0x09, 0x73, 0xb8, 0x67, 0x7b, 0x16, 0xa5, 0x51, 0x8c,
+	0x10, 0xa8, 0x6d, 0x8e, 0xe1, 0xb7, 0x77, 0x24, 0x6d, 0x54, 0xa4, 0x2a, 0x50, 0x5a, 0xa6, 0x65,
+	0x3f, 0x51, 0x05, 0x91, 0x04, 0x8c, 0xff, 0x83, 0xea, 0x91, 0xeb, 0x0f, 0x22, 0xe3, 0x41, 0x50,
+	0x18, 0xf3, 0x46, 0x58, 0xd5, 0xa7, 0xfc, 0xdb, 0x58, 0x07, 0xe0, 0x24, 0xea, 0xed, 0x38, 0x05,
+	0x49, 0x50, 0x10, 0x7f, 0xc0, 0x1d, 0xa7, 0xb1, 0xcb, 0xeb, 0xdd, 0x5d, 0xd7, 0xc3, 0xe7, 0x88,
+	0x66, 0x71, 0xc4, 0xca, 0x65, 0x22, 0xd6, 0x5b, 0x70, 0x21, 0xc0, 0x9e, 0xc5, 0xdc, 0x13, 0xdc,
+	0x1b, 0x5b, 0x6c, 0xa8, 0x5e, 0xb2, 0x16, 0x21, 0x8f, 0x2c, 0x36, 0xe4, 0x44, 0xc7, 0xae, 0x87,
+	0x79, 0x39, 0xd3, 0x1b, 0x78, 0xa4, 0xaf, 0xde, 0xb4, 0x16, 0x21, 0xf7, 0x3c, 0xd2, 0x17, 0xcd,
+	0x3d, 0xb6, 0xc3, 0x80, 0x62, 0x35, 0xc1, 0x88, 0x40, 0xe3, 0x97, 0x1a, 0x5c, 0x94, 0x31, 0x5c,
+	0x76, 0x0c, 0xcb, 0xca, 0xbd, 0x06, 0x55, 0x95, 0xba, 0xe8, 0x18, 0xdb, 0x4a, 0x74, 0x90, 0xa8,
+	0xee, 0x18, 0xdb, 0xe8, 0x47, 0x80, 0x5c, 0xdf, 0xf6, 0x42, 0x07, 0xf7, 0x06, 0x2e, 0xeb, 0xa9,
+	0xd6, 0x46, 0xfa, 0x7c, 0x43, 0xad, 0xec, 0xb9, 0x4c, 0x9e, 0x6a, 0x7c, 0x09, 0x17, 0x79, 0xd4,
+	0x55, 0x71, 0x87, 0xbe, 0x02, 0xed, 0x19, 0xdf, 0x6a, 0x50, 0x56, 0xfc, 0x12, 0x9d, 0x4c, 0x3e,
+	0xee, 0x64, 0xd6, 0xa1, 0xea, 0x60, 0x6a, 0x07, 0xae, 0x38, 0x4b, 0x6d, 0x4f, 0xa2, 0xb8, 0xad,
+	0x84, 0xd4, 0x1a, 0x60, 0xa5, 0x77, 0x09, 0xa0, 0x1f, 0xc2, 0xaa, 0x4c, 0x0d, 0xb4, 0x47, 0xfc,
+	0x1e, 0x25, 0x61, 0x60, 0x63, 0x55, 0x80, 0xd6, 0xd5, 0xc2, 0xa1, 0xdf, 0x15, 0x68, 0xe1, 0x85,
+	0x2e, 0x75, 0xfb, 0x5e, 0xac, 0x77, 0x05, 0x72, 0xde, 0x9e, 0x3b, 0x18, 0x32, 0x95, 0x1a, 0x24,
+	0x60, 0x7c, 0x0a, 0x55, 0x25, 0xb2, 0xc8, 0xa8, 0xed, 0xf4, 0xd8, 0xab, 0xba, 0x79, 0x69, 0x56,
+	0xbd, 0x12, 0x27, 0x1c, 0xe3, 0x08, 0x10, 0xdf, 0x27, 0xb3, 0xd8, 0x2b, 0x51, 0xe2, 0xff, 0x03,
+	0x4c, 0x72, 0x22, 0x4f, 0x68, 0x4c, 0x40, 0x4a, 0x91, 0x0a, 0x32, 0x3e, 0x83, 0x3a, 0x5f, 0xe7,
+	0x23, 0x84, 0xe8, 0xd0, 0x1b, 0xb0, 0x1a, 0x3d, 0xbf, 0x4d, 0x46, 0x63, 0x0f, 0x33, 0x2c, 0x9b,
+	0xa7, 0xc9, 0xeb, 0x6f, 0x47, 0x78, 0xe3, 0x16, 0xd4, 0xef, 0xb9, 0x9e, 0x97, 0xdc, 0x1f, 0xcd,
+	0x75, 0xf2, 0x6a, 0xae, 0xd3, 0x80, 0xbc, 0xe5, 0x79, 0x6a, 0x34, 0xc5, 0x3f, 0x0d, 0x03, 0x1a,
+	0x5b, 0x8c, 0xe7, 0xd3, 0x03, 0xd2, 0xcf, 0xee, 0x52, 0xd3, 0x20, 0xe3, 0x31, 0xac, 0x1e, 0x90,
+	0xfe, 0x5d, 0x97, 0xf2, 0x8b, 0xbe, 0x0a, 0x97, 0xbc, 0x02, 0xfa, 0xc8, 0x7a, 0xd6, 0x13, 0x43,
+	0x12, 0xd9, 0xf1, 0x97, 0x47, 0xd6, 0x33, 0x2e, 0xb8, 0xb1, 0x06, 0x17, 0x0e, 0x48, 0xff, 0x3e,
+	0x19, 0xcc, 0x13, 0x66, 0x15, 0xea, 0xdd, 0x61, 0xc8, 0x1c, 0xf2, 0x34, 0x4a, 0x98, 0xc6, 0x45,
+	0x58, 0xed, 0x62, 0xef, 0xf8, 0xd1, 0xd8, 0xb1, 0x58, 0x14, 0x32, 0x8c, 0x7f, 0xe5, 0xa0, 0xba,
+	0x15, 0x3a, 0xbc, 0xe1, 0xb7, 0x49, 0xe0, 0x9c, 0xbb, 0xec, 0x6e, 0x81, 0xee, 0x3a, 0xd8, 0x67,
+	0xbc, 0x6f, 0x56, 0xf2, 0x47, 0x30, 0x37, 0x48, 0xcb, 0x71, 0x02, 0x4c, 0x69, 0x94, 0x16, 0x14,
+	0xc8, 0xdf, 0x76, 0x84, 0xd9, 0x90, 0x38, 0x51, 0x43, 0x21, 0xa1, 0x8c, 0xb6, 0x8a, 0x0b, 0xb5,
+	0x55, 0xca, 0x68, 0x2b, 0x31, 0xb6, 0x2d, 0xa7, 0xc7, 0xb6, 0x71, 0xd9, 0xa2, 0x27, 0xca, 0x96,
+	0x44, 0xcb, 0x5c, 0x59, 0xcf, 0xc7, 0x2d, 0x33, 0xf7, 0x5e, 0xd1, 0x78, 0x82, 0x0c, 0xb6, 0xfc,
+	0x9b, 0x33, 0x10, 0xf1, 0xbe, 0x59, 0x95, 0xbe, 0x29, 0x00, 0x7e, 0xa0, 0xc8, 0x1a, 0xd8, 0x69,
+	0xd6, 0xa4, 0xbf, 0x29, 0x90, 0xb3, 0x0e, 0xc6, 0x36, 0x67, 0x7d, 0x61, 0x5d, 0xdb, 0xc8, 0x9b,
+	0xc5, 0x60, 0x6c, 0xef, 0x3b, 0xc6, 0x17, 0x50, 0x4b, 0xa8, 0x9a, 0xa2, 0x9b, 0xbc, 0xad, 0xe2,
+	0x9f, 0xca, 0xe1, 0x9a, 0x29, 0x87, 0x4b, 0x90, 0x9a, 0x8a, 0xce, 0xf8, 0x8f, 0x06, 0x75, 0x81,
+	0x4f, 0xbc, 0x7c, 0xf2, 0x05, 0xb4, 0xcc, 0x0b, 0x4c, 0xf4, 0x9c, 0x5b, 0xa0, 0xe7, 0xfc, 0x42,
+	0x3d, 0x17, 0x32, 0x7a, 0xbe, 0x09, 0x45, 0xea, 0xfa, 0x76, 0x34, 0x52, 0x5c, 0x64, 0x22, 0x92,
+	0x90, 0x87, 0xef, 0x63, 0xcb, 0xf5, 0x78, 0x9f, 0xeb, 0x7b, 0xa7, 0x2a, 0x08, 0x81, 0x44, 0x1d,
+	0xfa, 0x9e, 0x88, 0xef, 0xdc, 0xd0, 0xe5, 0x25, 0x65, 0x6a, 0x2f, 0x9a, 0x30, 0xb2, 0x9e, 0x29,
+	0x4d, 0x19, 0x7f, 0xca, 0x43, 0x81, 0x37, 0x1f, 0xdf, 0xc1, 0x04, 0x36, 0xaa, 0x40, 0x0a, 0x89,
+	0x0a, 0x24, 0x35, 0xe5, 0x2c, 0xa6, 0xa7, 0x9c, 0x2f, 0x35, 0x3e, 0xcd, 0xcc, 0x36, 0xf4, 0x17,
+	0x9e, 0x6d, 0x54, 0x5e, 0x70, 0xb6, 0x01, 0xd9, 0xd9, 0x46, 0x6a, 0x44, 0x51, 0x4d, 0x8f, 0x28,
+	0xb2, 0x03, 0x8e, 0x5a, 0x76, 0xc0, 0xc1, 0x07, 0x3c, 0x95, 0x49, 0xd3, 0x78, 0xde, 0xd8, 0x12,
+	0xa9, 0x3e, 0x97, 0x50, 0xfd, 0x3b, 0x90, 0xf7, 0xc8, 0x40, 0x0d, 0x62, 0xe7, 0x34, 0xf8, 0x9c,
+	0x02, 0xbd, 0x0d, 0x05, 0xde, 0xe7, 0xaa, 0x9f, 0x5e, 0x56, 0x33, 0x9d, 0x17, 0x7d, 0x62, 0x8a,
+	0x65, 0xf4, 0x51, 0x3c, 0xca, 0x92, 0x83, 0xd0, 0x33, 0x9b, 0x74, 0x45, 0x8e, 0xbe, 0x88, 0x6b,
+	0x12, 0x71, 0x4c, 0x69, 0x3d, 0xbf, 0x4c, 0x23, 0x0d, 0x93, 0x46, 0xda, 0xf8, 0x08, 0x56, 0xa2,
+	0x44, 0xa6, 0x0a, 0xbd, 0x48, 0x66, 0x6d, 0xa1, 0xcc, 0xc6, 0x2f, 0x60, 0x25, 0xca, 0x60, 0xe7,
+	0xda, 0x18, 0x29, 0x2f, 0x77, 0x96, 0xf2, 0x8c, 0x4f, 0x61, 0x25, 0xca, 0x1e, 0xea, 0x84, 0xd8,
+	0x67, 0xb4, 0xb3, 0x7d, 0xc6, 0xf8, 0x1c, 0x1a, 0x93, 0x4c, 0xf3, 0x22, 0x0c, 0x1e, 0x40, 0x3d,
+	0x2e, 0x6e, 0x27, 0x45, 0x70, 0x76, 0x60, 0xb4, 0xfc, 0x7d, 0xda, 0xa0, 0x9b, 0x51, 0xdb, 0x31,
+	0x6b, 0xd0, 0x2c, 0xc3, 0x8a, 0xb4, 0x33, 0x9e, 0x3d, 0x7f, 0xa7, 0x01, 0xea, 0x3c, 0x63, 0x81,
+	0x65, 0x33, 0x13, 0x1f, 0x2f, 0x5d, 0xdc, 0xbc, 0x9f, 0x99, 0x16, 0x4c, 0x5d, 0x53, 0x2d, 0x26,
+	0x9a, 0xa0, 0xdb, 0x70, 0xa1, 0x6f, 0x51, 0xdc, 0x4b, 0xcc, 0xde, 0xf2, 0xf3, 0xf7, 0xd5, 0x38,
+	0x6d, 0x04, 0x19, 0x37, 0x60, 0x35, 0x21, 0xa4, 0xd2, 0x13, 0x1f, 0x7c, 0x84, 0xbe, 0xe3, 0x61,
+	0xa5, 0x29, 0x05, 0x19, 0x07, 0xd0, 0xd8, 0x1a, 0x8f, 0xbd, 0xd3, 0xf3, 0xdc, 0x67, 0xc2, 0x2b,
+	0x97, 0xe2, 0xb5, 0x03, 0xf5, 0x98, 0x97, 0x3a, 0x36, 0x79, 0x75, 0x6d, 0xd1, 0x15, 0x26, 0x2d,
+	0xea, 0x07, 0x70, 0x71, 0x0f, 0xb3, 0x7b, 0xbe, 0x30, 0xb2, 0xa5, 0x85, 0x32, 0xf6, 0x00, 0x25,
+	0xb7, 0xbd, 0xf0, 0xf9, 0x9b, 0xdf, 0x14, 0xa0, 0x22, 0x86, 0x4c, 0x77, 0x79, 0x60, 0xd9, 0x81,
+	0x06, 0xb7, 0x42, 0x51, 0x8f, 0x47, 0x95, 0xfc, 0xbc, 0xd9, 0x43, 0x2b, 0xcd, 0x3b, 0x9a, 0x59,
+	0xde, 0xd4, 0x90, 0x2a, 0x8a, 0x53, 0x6c, 0x28, 0x5a, 0x4f, 0x9b, 0xe6, 0x74, 0xef, 0xd1, 0x6a,
+	0xce, 0x0a, 0x3b, 0x9c, 0x10, 0xed, 0x41, 0x35, 0x51, 0x66, 0xa3, 0xb5, 0x29, 0x56, 0xe9, 0x02,
+	0xbc, 0x35, 0x6f, 0xc6, 0x84, 0xb6, 0xa1, 0xce, 0x2f, 0x98, 0xfc, 0xa5, 0xfb, 0xfc, 0xf7, 0xdb,
+	0x86, 0x4a, 0xec, 0x98, 0xe8, 0xcd, 0x14, 0x55, 0xb6, 0x1b, 0x9d, 0xcf, 0xe4, 0x4b, 0xb8, 0x92,
+	0x55, 0xf5, 0xd7, 0x2e, 0x1b, 0xca, 0x01, 0xd0, 0x95, 0x59, 0x9a, 0x10, 0x4b, 0x73, 0x18, 0x6e,
+	0x68, 0x42, 0xef, 0xcd, 0xcc, 0xe5, 0x5e, 0x92, 0xe3, 0xe6, 0x3f, 0xca, 0xb0, 0x32, 0xf9, 0x11,
+	0xec, 0x7b, 0x6d, 0x22, 0xaf, 0xe4, 0x65, 0xbb, 0x50, 0xdf, 0xc3, 0x2c, 0xd9, 0x9d, 0x67, 0x64,
+	0x9a, 0xd1, 0xb8, 0xb7, 0xae, 0x2f, 0xfe, 0xd9, 0x10, 0x1d, 0x40, 0xbd, 0x9b, 0x61, 0x7a, 0xc6,
+	0x96, 0xf9, 0x02, 0xee, 0x40, 0xe3, 0x28, 0xf4, 0xbc, 0xdd, 0x80, 0x8c, 0xe2, 0x1f, 0x0d, 0xdf,
+	0x98, 0x21, 0x21, 0x57, 0xc9, 0x7c, 0x2e, 0x77, 0x60, 0xe5, 0x28, 0xa4, 0xc3, 0x87, 0xe4, 0x25,
+	0x78, 0x7c, 0xce, 0x7f, 0xd2, 0xb2, 0x58, 0x48, 0xd1, 0xb5, 0x4c, 0x8c, 0x49, 0xfd, 0xd3, 0x62,
+	0x91, 0x17, 0x41, 0xf7, 0xd4, 0xb7, 0x4d, 0x3c, 0x22, 0x0c, 0xbf, 0x28, 0x93, 0x03, 0x58, 0x3d,
+	0x0a, 0x30, 0x2f, 0x3b, 0x77, 0x49, 0x60, 0x62, 0x1b, 0xbb, 0x27, 0xf8, 0xc5, 0x05, 0xfa, 0x5f,
+	0x71, 0xeb, 0x6f, 0x8b, 0x50, 0xed, 0xe2, 0xe0, 0xc4, 0xb5, 0xb1, 0xf0, 0xe9, 0x9f, 0x42, 0x51,
+	0x8c, 0x49, 0x33, 0xec, 0x92, 0xe3, 0xdd, 0x56, 0x73, 0x7a, 0x49, 0x65, 0x9d, 0x4f, 0xa0, 0xc0,
+	0xe7, 0x74, 0x28, 0x4d, 0x91, 0x98, 0xee, 0xb5, 0xde, 0x98, 0x5a, 0x51, 0x5b, 0xef, 0x80, 0x1e,
+	0x55, 0x7f, 0x99, 0x57, 0xcb, 0x4c, 0x37, 0x32, 0xc7, 0x27, 0xff, 0x41, 0xb1, 0x05, 0x7a, 0x54,
+	0x08, 0x66, 0x78, 0x64, 0x26, 0x1c, 0x0b, 0x5f, 0x3e, 0x1e, 0x6c, 0x64, 0x5e, 0x3e, 0x3b, 0xf0,
+	0x98, 0xcf, 0x64, 0x1f, 0x2e, 0xec, 0x61, 0x36, 0x19, 0x7e, 0x64, 0x9c, 0x7c, 0x6a, 0x2a, 0xb2,
+	0xe0, 0x4a, 0x5f, 0x40, 0x45, 0xb2, 0xba, 0x4f, 0x06, 0xa8, 0x95, 0x65, 0x33, 0x69, 0x7b, 0xe7,
+	0x0b, 0xb3, 0x05, 0x7a, 0x54, 0xbb, 0x66, 0x94, 0x92, 0x19, 0x88, 0xcc, 0x67, 0xd1, 0x01, 0x98,
+	0xd4, 0xaf, 0x99, 0xcb, 0x4c, 0x8d, 0x50, 0xe6, 0xb3, 0xd9, 0x85, 0xea, 0x1e, 0x66, 0x51, 0xbf,
+	0x9e, 0x11, 0x26, 0xd3, 0xc6, 0xb7, 0xae, 0xcc, 0x6b, 0xfe, 0xe9, 0xe6, 0x5f, 0xca, 0x50, 0xf8,
+	0x6e, 0x8d, 0x75, 0x6f, 0x69, 0x63, 0xbd, 0x3a, 0x73, 0x55, 0xb2, 0xb9, 0xa9, 0x71, 0x46, 0x4b,
+	0x5a, 0xec, 0xd5, 0x99, 0xab, 0x31, 0xa3, 0xce, 0xd2, 0xaf, 0x7c, 0x75, 0xe6, 0xaa, 0xba, 0xd8,
+	0xbd, 0x73, 0xbd, 0xf4, 0x9b, 0x73, 0xd6, 0x15, 0xb3, 0x6d, 0xf1, 0xd3, 0x62, 0x94, 0x82, 0x17,
+	0xc4, 0xa7, 0xcb, 0x53, 0x0d, 0xda, 0x24, 0xe4, 0x1d, 0x40, 0x2d, 0x99, 0xfd, 0x5f, 0xaa, 0x30,
+	0x38, 0x38, 0x47, 0x58, 0xbf, 0x36, 0x7b, 0x39, 0x56, 0xf8, 0x11, 0x54, 0x13, 0xcd, 0x46, 0xa6,
+	0x0e, 0x9d, 0xee, 0x95, 0x5a, 0xd7, 0xe7, 0x11, 0xc4, 0x1c, 0x0f, 0xa0, 0x12, 0x77, 0x11, 0xd9,
+	0xd0, 0x93, 0xe9, 0x54, 0x5a, 0xd7, 0x66, 0x2f, 0x4b, 0x5e, 0x1b, 0x3c, 0xf7, 0xd4, 0x92, 0x4d,
+	0x41, 0x46, 0x6b, 0x33, 0xda, 0x8c, 0xd6, 0xda, 0x5c, 0x0a, 0xf5, 0x9a, 0xaf, 0xc8, 0x7b, 0xfb,
+	0x25, 0x31, 0xdf, 0xb8, 0xf5, 0xdf, 0x01, 0x00, 0x38, 0xd6, 0x12, 0x06, 0xc0, 0x2a, 0x00, 0x00,
 }
@@ -4,14 +4,31 @@ import (
 	"context"
 	"fmt"
 	"github.com/asankah/stonesthrow"
+	"os"
+	"os/signal"
 )
 
 func main() {
-	err := stonesthrow.InvokeCommandline(context.Background(), func(config stonesthrow.Config) stonesthrow.OutputSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first interrupt cancels the current request, which in turn stops
+	// any remote jobs that it started. A second interrupt kills the client.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		<-interrupts
+		signal.Stop(interrupts)
+		cancel()
+	}()
+
+	exit_status, err := stonesthrow.InvokeCommandline(ctx, func(config stonesthrow.Config) stonesthrow.OutputSink {
 		return &ConsoleFormatter{config: &config}
 	})
 
 	if err != nil {
 		fmt.Printf("%s\n", err.Error())
 	}
+	cancel()
+	os.Exit(exit_status)
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"github.com/asankah/stonesthrow"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"io"
 	"os"
 	"regexp"
@@ -16,6 +17,7 @@ type terminalOutputFilter func(string) string
 
 type ConsoleFormatter struct {
 	filterChain []terminalOutputFilter
+	lines       stonesthrow.OutputLineSplitter
 	templates   map[string]*template.Template
 	config      *stonesthrow.Config
 	porcelain   bool
@@ -146,6 +148,18 @@ func (f *ConsoleFormatter) ApplyFilters(s string) string {
 	return output
 }
 
+// printLines writes |lines| to stdout after passing them through the filter
+// chain. Line terminators are preserved. Partial lines are terminated.
+func (f *ConsoleFormatter) printLines(lines []string) {
+	for _, line := range lines {
+		contents, terminator := stonesthrow.SplitLineTerminator(line)
+		if terminator == "" {
+			terminator = "\n"
+		}
+		fmt.Print(f.ApplyFilters(contents), terminator)
+	}
+}
+
 func (f *ConsoleFormatter) ClearFilters() {
 	f.filterChain = nil
 }
@@ -169,8 +183,10 @@ func (f *ConsoleFormatter) GetTemplate(name string, templateValue string) (*temp
 		"field":    CSubject,
 		"subject":  CSubject,
 		"error":    CError,
+		"warning":  CWarning,
 		"success":  CSucceeded,
 		"location": CLocation,
+		"join":     strings.Join,
 		"lines": func(s string) []string {
 			return strings.Split(s, "\n")
 		},
@@ -180,6 +196,22 @@ func (f *ConsoleFormatter) GetTemplate(name string, templateValue string) (*temp
 		"seconds": func(d time.Duration) string {
 			return fmt.Sprintf("%2.2f", time.Duration(d).Seconds())
 		},
+		"starttime": func(j *stonesthrow.BuilderJob) string {
+			return stonesthrow.TimeFromTimestamp(j.GetState().GetStartTime()).Local().Format("Jan _2 15:04")
+		},
+		"timestamp": func(t *timestamp.Timestamp) string {
+			return stonesthrow.TimeFromTimestamp(t).Local().Format("Jan _2 15:04:05")
+		},
+		"runtime": func(j *stonesthrow.BuilderJob) string {
+			return stonesthrow.RunTimeOfJob(j).Round(time.Second).String()
+		},
+		"cmdline": func(c []string) string {
+			s := strings.Join(c, " ")
+			if len(s) > 80 {
+				s = s[:77] + "..."
+			}
+			return s
+		},
 		"branch_succeeded": func(v stonesthrow.GitBranchTaskEvent_Result) bool {
 			return v == stonesthrow.GitBranchTaskEvent_SUCCEEDED
 		},
@@ -238,7 +270,16 @@ func (f *ConsoleFormatter) OnTargetList(tl *stonesthrow.TargetList) error {
 	return nil
 }
 
+const jobTemplate = `{{define "job"}}{{.Id | printf "%4d" | heading}} {{/*
+*/}}{{if .State.Running}}{{success "running "}}{{else if .State.Cancelled}}{{error "cancel  "}}{{else if .State.ReturnCode}}{{.State.ReturnCode | printf "exit %-3d" | error}}{{else}}{{dark "done    "}}{{end}} {{/*
+*/}}{{starttime . | dark}} {{runtime . | printf "%8s"}} {{.Repository | subject}}{{if .Platform}}/{{.Platform | subject}}{{end}} {{/*
+*/}}{{.Command.Command | cmdline}}{{if .Command.Directory}} [{{.Command.Directory | info}}]{{end}}{{/*
+*/}}{{if .ParentId}} (part of job {{.ParentId}}){{end}}{{end}}`
+
 func (f *ConsoleFormatter) OnBuilderJobs(bj *stonesthrow.BuilderJobs) error {
+	f.Show("jobs", jobTemplate+`{{title "Jobs"}}{{range .Jobs}}
+{{template "job" .}}{{end}}
+`, bj)
 	return nil
 }
 
@@ -251,6 +292,11 @@ func (f *ConsoleFormatter) OnJobEvent(je *stonesthrow.JobEvent) error {
 				`{{.Host | shorthost | subject}}:{{info "Info"}}: {{.Msg}}
 `, je.GetLogEvent())
 
+		case stonesthrow.LogEvent_WARNING:
+			f.Show("warning",
+				`{{.Host | shorthost | subject}}:{{warning "Warning"}}: {{.Msg}}
+`, je.GetLogEvent())
+
 		case stonesthrow.LogEvent_ERROR:
 			f.Show("error",
 				`{{.Host | shorthost | subject}}:{{error "Error"}}: {{.Msg}}
@@ -264,25 +310,51 @@ func (f *ConsoleFormatter) OnJobEvent(je *stonesthrow.JobEvent) error {
 		}
 
 	case je.GetBeginCommandEvent() != nil:
+		f.printLines(f.lines.Flush())
 		e := je.GetBeginCommandEvent()
 		f.Show("bc",
-			`{{.Host | shorthost | subject}}: {{range .Command}}{{.}} {{end}}{{if .Directory}} [{{.Directory | info}}]{{end}}
-`, e.GetCommand())
-		f.SetupFilterChainForCommand(e.Command.Command)
+			`{{.Command.Host | shorthost | subject}}: {{range .Command.Command}}{{.}} {{end}}{{if .Command.Directory}} [{{.Command.Directory | info}}]{{end}}{{if .JobId}} {{.JobId | printf "(job %d)" | dark}}{{end}}
+`, e)
+		if e.GetCommand().GetTty() {
+			// Output from a terminal includes prompts and cursor
+			// movements that can't be split into lines.
+			f.ClearFilters()
+		} else {
+			f.SetupFilterChainForCommand(e.Command.Command)
+		}
 
 	case je.GetCommandOutputEvent() != nil:
 		e := je.GetCommandOutputEvent()
-		fmt.Println(f.ApplyFilters(e.GetOutput()))
+		// Unfiltered output is passed through as-is so that partial lines and
+		// progress updates show up right away.
+		if f.filterChain == nil && len(e.GetData()) != 0 {
+			os.Stdout.Write(e.GetData())
+			break
+		}
+		f.printLines(f.lines.Split(e))
 
 	case je.GetEndCommandEvent() != nil:
+		f.printLines(f.lines.Flush())
 		e := je.GetEndCommandEvent()
-		if e.ReturnCode != 0 {
-			f.Show("fail", `{{error "Failed"}}: Return code {{.ReturnCode | printf "%d" | info}}
+		if e.TimedOut {
+			f.Show("timed-out", `{{error "Timed out"}}
+
+`, e)
+		} else if e.Cancelled {
+			f.Show("cancelled", `{{error "Cancelled"}}
+
+`, e)
+		} else if e.ReturnCode != 0 {
+			f.Show("fail", `{{error "Failed"}}: Return code {{.ReturnCode | printf "%d" | info}}{{if .Signal}} (terminated by signal: {{.SignalName | error}}){{end}}
 
 `, e)
 		}
 		f.ClearFilters()
 
+	case je.GetJob() != nil:
+		f.Show("job", jobTemplate+`{{template "job" .}}
+`, je.GetJob())
+
 	case je.GetBranchTaskEvent() != nil:
 		e := je.GetBranchTaskEvent()
 		f.Show("branch-task", `[{{title .Branch}}] {{if .Result | branch_succeed}}{{success "OK}}{{else}}{{error "FAILED"}}{{end}} {{info .Revision}} ({{.Reason}})
@@ -297,6 +369,26 @@ func (f *ConsoleFormatter) OnPong(pr *stonesthrow.PingResult) error {
 	return nil
 }
 
+func (f *ConsoleFormatter) OnHello(hr *stonesthrow.HelloResult) error {
+	f.Show("hello", `{{info "Host"}}: {{.Host}} ({{.Os}}/{{.Arch}})
+{{info "Version"}}: {{.Version}}{{if .Revision}} ({{.Revision}}){{end}}
+{{info "Protocol"}}: {{.ProtocolVersion}} (supports {{.MinProtocolVersion}} and later)
+{{info "Capabilities"}}: {{join .Capabilities ", "}}
+`, hr)
+	return nil
+}
+
+func (f *ConsoleFormatter) OnAuditRecords(ar *stonesthrow.AuditRecords) error {
+	f.Show("audit", `{{title "Audit log"}}{{range .Record}}
+{{.Time | timestamp | dark}} {{if .Identity}}{{.Identity | subject}}{{else}}{{.Address | subject}}{{end}} {{/*
+*/}}{{.Method | info}}{{if .Repository}} {{.Repository}}{{if .Platform}}/{{.Platform}}{{end}}{{end}}{{/*
+*/}}{{if .Command}} {{if .Shell}}{{dark "shell"}} {{end}}{{.Command | cmdline}}{{end}}{{/*
+*/}}{{range .JobId}} {{. | printf "(job %d)" | dark}}{{end}}{{/*
+*/}}{{if .Started}} {{dark "started"}}{{else if .Error}} {{.Code | error}}: {{.Error}}{{end}}{{end}}
+`, ar)
+	return nil
+}
+
 func (f *ConsoleFormatter) Drain(jr stonesthrow.JobEventReceiver) error {
 	for {
 		je, err := jr.Recv()
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/asankah/stonesthrow"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns everything that |f| writes to stdout.
+func captureStdout(t *testing.T, f func()) string {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	f()
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+func TestConsoleFormatter_TerminalOutput(t *testing.T) {
+	config := stonesthrow.Config{Host: &stonesthrow.HostConfig{HostsConfig: &stonesthrow.HostsConfig{}}}
+	f := &ConsoleFormatter{config: &config}
+
+	// Commands named like test binaries are normally filtered line by line.
+	// When they run in a terminal, prompts and progress updates that don't
+	// end with a newline still need to show up right away.
+	output := captureStdout(t, func() {
+		f.OnJobEvent(&stonesthrow.JobEvent{
+			BeginCommandEvent: &stonesthrow.BeginCommandEvent{
+				Command: &stonesthrow.ShellCommand{Command: []string{"./out/browser_tests"}, Tty: true}}})
+		for _, data := range []string{"[1/2] running\r", "[2/2] running\r", "(gdb) "} {
+			f.OnJobEvent(&stonesthrow.JobEvent{
+				CommandOutputEvent: &stonesthrow.CommandOutputEvent{Data: []byte(data)}})
+		}
+	})
+	expected := "[1/2] running\r[2/2] running\r(gdb) "
+	if !strings.HasSuffix(output, expected) {
+		t.Fatalf("unexpected output %q", output)
+	}
+}
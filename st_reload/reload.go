@@ -55,7 +55,7 @@ func main() {
 	cmd.Stderr = os.Stderr
 	err = cmd.Start()
 	if err != nil {
-		fmt.Printf("Failed to start %s: error %s", err.Error())
+		fmt.Printf("Failed to start %s: error %s\n", args, err.Error())
 		os.Exit(1)
 	}
 	fmt.Println("Done")
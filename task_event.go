@@ -0,0 +1,189 @@
+package stonesthrow
+
+import (
+	net_context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// NewTaskFromBuilderJob returns the Task that describes |job| to clients of
+// the Host service.
+func NewTaskFromBuilderJob(job *BuilderJob) *Task {
+	return &Task{
+		Id:         job.GetId(),
+		Command:    job.GetCommand(),
+		State:      job.GetState(),
+		Host:       job.GetCommand().GetHost(),
+		ParentId:   job.GetParentId(),
+		Repository: job.GetRepository(),
+		Platform:   job.GetPlatform(),
+		SystemTime: job.GetSystemTime(),
+		UserTime:   job.GetUserTime(),
+		Cancelled:  job.GetState().GetCancelled()}
+}
+
+// NewBuilderJobFromTask is the inverse of NewTaskFromBuilderJob.
+func NewBuilderJobFromTask(task *Task) *BuilderJob {
+	return &BuilderJob{
+		Id:         task.GetId(),
+		Command:    task.GetCommand(),
+		State:      task.GetState(),
+		SystemTime: task.GetSystemTime(),
+		UserTime:   task.GetUserTime(),
+		Repository: task.GetRepository(),
+		Platform:   task.GetPlatform(),
+		ParentId:   task.GetParentId()}
+}
+
+// NewTaskEventFromJobEvent converts |je| into the equivalent TaskEvent for
+// |host|. BeginCommandEvents and EndCommandEvents become tasks that are
+// running and completed respectively. Returns nil for events that don't
+// have an equivalent. Those aren't sent by commands.
+func NewTaskEventFromJobEvent(host string, je *JobEvent) *TaskEvent {
+	te := &TaskEvent{Time: je.GetTime(), Host: host}
+	switch {
+	case je.GetLogEvent() != nil:
+		te.Log = []*LogEvent{je.GetLogEvent()}
+
+	case je.GetBeginCommandEvent() != nil:
+		e := je.GetBeginCommandEvent()
+		te.Task = []*Task{&Task{
+			Id:      e.GetJobId(),
+			Command: e.GetCommand(),
+			Host:    e.GetCommand().GetHost(),
+			State: &RunState{
+				StartTime: je.GetTime(),
+				Running:   true}}}
+
+	case je.GetCommandOutputEvent() != nil:
+		te.Output = []*CommandOutputEvent{je.GetCommandOutputEvent()}
+
+	case je.GetEndCommandEvent() != nil:
+		e := je.GetEndCommandEvent()
+		te.Task = []*Task{&Task{
+			Host: host,
+			State: &RunState{
+				EndTime:    je.GetTime(),
+				ReturnCode: e.GetReturnCode(),
+				Signal:     e.GetSignal(),
+				Cancelled:  e.GetCancelled()},
+			SystemTime: e.GetSystemTime(),
+			UserTime:   e.GetUserTime(),
+			Cancelled:  e.GetCancelled(),
+			TimedOut:   e.GetTimedOut(),
+			SignalName: e.GetSignalName()}}
+
+	case je.GetBranchTaskEvent() != nil:
+		te.BranchTask = []*GitBranchTaskEvent{je.GetBranchTaskEvent()}
+
+	default:
+		return nil
+	}
+	return te
+}
+
+// NewJobEventsFromTaskEvent is the inverse of NewTaskEventFromJobEvent.
+func NewJobEventsFromTaskEvent(te *TaskEvent) []*JobEvent {
+	events := []*JobEvent{}
+	for _, e := range te.GetLog() {
+		events = append(events, &JobEvent{Time: te.GetTime(), LogEvent: e})
+	}
+	for _, task := range te.GetTask() {
+		if task.GetState().GetRunning() {
+			events = append(events, &JobEvent{
+				Time: te.GetTime(),
+				BeginCommandEvent: &BeginCommandEvent{
+					Command: task.GetCommand(),
+					JobId:   task.GetId()}})
+			continue
+		}
+		events = append(events, &JobEvent{
+			Time: te.GetTime(),
+			EndCommandEvent: &EndCommandEvent{
+				ReturnCode: task.GetState().GetReturnCode(),
+				SystemTime: task.GetSystemTime(),
+				UserTime:   task.GetUserTime(),
+				Cancelled:  task.GetCancelled(),
+				Signal:     task.GetState().GetSignal(),
+				SignalName: task.GetSignalName(),
+				TimedOut:   task.GetTimedOut()}})
+	}
+	for _, e := range te.GetOutput() {
+		events = append(events, &JobEvent{Time: te.GetTime(), CommandOutputEvent: e})
+	}
+	for _, e := range te.GetBranchTask() {
+		events = append(events, &JobEvent{Time: te.GetTime(), BranchTaskEvent: e})
+	}
+	return events
+}
+
+// convertedJobEventServer lets the implementations of the older services send
+// JobEvents via a Host service stream. |send| converts the events.
+type convertedJobEventServer struct {
+	grpc.ServerStream
+	send func(*JobEvent) error
+}
+
+func (s convertedJobEventServer) Send(je *JobEvent) error {
+	return s.send(je)
+}
+
+// convertedJobEventReceiver presents the results received via a Host service
+// stream as JobEvents. |recv| receives the next result and converts it.
+type convertedJobEventReceiver struct {
+	stream  grpc.ClientStream
+	recv    func() ([]*JobEvent, error)
+	pending []*JobEvent
+}
+
+func (r *convertedJobEventReceiver) Recv() (*JobEvent, error) {
+	for len(r.pending) == 0 {
+		events, err := r.recv()
+		if err != nil {
+			return nil, err
+		}
+		r.pending = events
+	}
+	je := r.pending[0]
+	r.pending = r.pending[1:]
+	return je, nil
+}
+
+func (r *convertedJobEventReceiver) Header() (metadata.MD, error) { return r.stream.Header() }
+func (r *convertedJobEventReceiver) Trailer() metadata.MD         { return r.stream.Trailer() }
+func (r *convertedJobEventReceiver) CloseSend() error             { return r.stream.CloseSend() }
+func (r *convertedJobEventReceiver) Context() net_context.Context { return r.stream.Context() }
+func (r *convertedJobEventReceiver) SendMsg(m interface{}) error  { return r.stream.SendMsg(m) }
+func (r *convertedJobEventReceiver) RecvMsg(m interface{}) error  { return r.stream.RecvMsg(m) }
+
+// CommandStream is a stream for running a command whose stdin is sent by the
+// client.
+type CommandStream interface {
+	Send(*CommandInput) error
+	JobEventReceiver
+}
+
+type taskEventStream struct {
+	Host_RunCommandClient
+	receiver *convertedJobEventReceiver
+}
+
+func (s taskEventStream) Recv() (*JobEvent, error) {
+	return s.receiver.Recv()
+}
+
+// NewTaskEventStream presents the TaskEvents received via |stream| as
+// JobEvents.
+func NewTaskEventStream(stream Host_RunCommandClient) CommandStream {
+	return taskEventStream{
+		Host_RunCommandClient: stream,
+		receiver: &convertedJobEventReceiver{
+			stream: stream,
+			recv: func() ([]*JobEvent, error) {
+				te, err := stream.Recv()
+				if err != nil {
+					return nil, err
+				}
+				return NewJobEventsFromTaskEvent(te), nil
+			}}}
+}
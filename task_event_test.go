@@ -0,0 +1,50 @@
+package stonesthrow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaskEvent_RoundTrip(t *testing.T) {
+	now := TimestampNow()
+	job_events := []*JobEvent{
+		&JobEvent{Time: now, LogEvent: &LogEvent{Host: "a", Msg: "starting"}},
+		&JobEvent{Time: now, BeginCommandEvent: &BeginCommandEvent{
+			Command: &ShellCommand{Command: []string{"ninja"}, Host: "a"},
+			JobId:   3}},
+		&JobEvent{Time: now, CommandOutputEvent: &CommandOutputEvent{Data: []byte("[1/2] CXX")}},
+		&JobEvent{Time: now, EndCommandEvent: &EndCommandEvent{ReturnCode: -1, TimedOut: true, Cancelled: true}},
+		&JobEvent{Time: now, BranchTaskEvent: &GitBranchTaskEvent{Branch: "master"}}}
+
+	for _, je := range job_events {
+		te := NewTaskEventFromJobEvent("a", je)
+		if te == nil || te.Host != "a" {
+			t.Fatalf("unexpected task event %v for %v", te, je)
+		}
+		converted := NewJobEventsFromTaskEvent(te)
+		if len(converted) != 1 || !reflect.DeepEqual(converted[0], je) {
+			t.Fatalf("expected %v. got %v", je, converted)
+		}
+	}
+
+	if NewTaskEventFromJobEvent("a", &JobEvent{ZippedContent: &ZippedContentEvent{}}) != nil {
+		t.Fatal("unexpected task event for zipped content")
+	}
+}
+
+func TestTask_BuilderJob(t *testing.T) {
+	job := &BuilderJob{
+		Id:         2,
+		Command:    &ShellCommand{Command: []string{"ninja"}, Host: "a"},
+		State:      &RunState{Running: true},
+		Repository: "chrome",
+		Platform:   "linux",
+		ParentId:   1}
+	task := NewTaskFromBuilderJob(job)
+	if task.Host != "a" {
+		t.Fatalf("unexpected host %s", task.Host)
+	}
+	if !reflect.DeepEqual(NewBuilderJobFromTask(task), job) {
+		t.Fatalf("expected %v. got %v", job, NewBuilderJobFromTask(task))
+	}
+}
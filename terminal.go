@@ -0,0 +1,46 @@
+package stonesthrow
+
+import (
+	"golang.org/x/term"
+	"os"
+)
+
+const (
+	kDefaultTerminalRows    = 24
+	kDefaultTerminalColumns = 80
+)
+
+// TerminalSizeOrDefault returns |size| unless it's unspecified, in which case
+// the size of a classic 80x24 terminal is returned.
+func TerminalSizeOrDefault(size *WindowSize) *WindowSize {
+	if size.GetRows() > 0 && size.GetColumns() > 0 {
+		return size
+	}
+	return &WindowSize{Rows: kDefaultTerminalRows, Columns: kDefaultTerminalColumns}
+}
+
+// GetTerminalSize returns the size of the terminal attached to |f| or nil if
+// |f| isn't a terminal.
+func GetTerminalSize(f *os.File) *WindowSize {
+	columns, rows, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return nil
+	}
+	return &WindowSize{Rows: int32(rows), Columns: int32(columns)}
+}
+
+// MakeTerminalRaw puts the terminal attached to |f| into raw mode so that all
+// input can be passed through to a remote terminal. Call the returned
+// function to restore the previous mode. Does nothing if |f| isn't a
+// terminal.
+func MakeTerminalRaw(f *os.File) (func(), error) {
+	fd := int(f.Fd())
+	if !term.IsTerminal(fd) {
+		return func() {}, nil
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { term.Restore(fd, state) }, nil
+}
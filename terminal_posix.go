@@ -0,0 +1,51 @@
+//go:build !windows
+// +build !windows
+
+package stonesthrow
+
+import (
+	"github.com/creack/pty"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// startInTerminal starts |cmd| with a new pseudo-terminal of |size| as its
+// controlling terminal. The process becomes the leader of a new session and
+// hence a new process group. Returns the master side of the terminal.
+func startInTerminal(cmd *exec.Cmd, size *WindowSize) (*os.File, error) {
+	return pty.StartWithSize(cmd, &pty.Winsize{
+		Rows: uint16(size.GetRows()),
+		Cols: uint16(size.GetColumns())})
+}
+
+func resizeTerminal(tty *os.File, size *WindowSize) error {
+	return pty.Setsize(tty, &pty.Winsize{
+		Rows: uint16(size.GetRows()),
+		Cols: uint16(size.GetColumns())})
+}
+
+// WatchTerminalSize calls |changed| with the new size of the terminal attached
+// to |f| whenever it is resized. The returned function stops the watch.
+func WatchTerminalSize(f *os.File, changed func(*WindowSize)) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if size := GetTerminalSize(f); size != nil {
+					changed(size)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,41 @@
+package stonesthrow
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestJobEventExecutor_Terminal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("pseudo-terminals are not supported")
+	}
+
+	var events recordingJobEventSender
+	executor := NewJobEventExecutor("host", "", "", "", nil, &events)
+	executor.SetTerminal(&WindowSize{Rows: 30, Columns: 100})
+	err := executor.ExecutePassthrough(context.Background(), "sh", "-c", "test -t 0 && test -t 1 && stty size")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := ""
+	for _, e := range events.events {
+		output += string(e.GetCommandOutputEvent().GetData())
+	}
+	if !strings.Contains(output, "30 100") {
+		t.Fatalf("unexpected output %q", output)
+	}
+}
+
+func TestTerminalSizeOrDefault(t *testing.T) {
+	size := TerminalSizeOrDefault(nil)
+	if size.Rows != kDefaultTerminalRows || size.Columns != kDefaultTerminalColumns {
+		t.Fatalf("unexpected size %v", size)
+	}
+	size = TerminalSizeOrDefault(&WindowSize{Rows: 10, Columns: 20})
+	if size.Rows != 10 || size.Columns != 20 {
+		t.Fatalf("unexpected size %v", size)
+	}
+}
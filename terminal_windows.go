@@ -0,0 +1,19 @@
+package stonesthrow
+
+import (
+	"os"
+	"os/exec"
+)
+
+func startInTerminal(cmd *exec.Cmd, size *WindowSize) (*os.File, error) {
+	return nil, NewNotSupportedError("pseudo-terminals are not supported on Windows")
+}
+
+func resizeTerminal(tty *os.File, size *WindowSize) error {
+	return NewNotSupportedError("pseudo-terminals are not supported on Windows")
+}
+
+// There's no signal for terminal size changes on Windows.
+func WatchTerminalSize(f *os.File, changed func(*WindowSize)) func() {
+	return func() {}
+}
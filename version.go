@@ -0,0 +1,149 @@
+package stonesthrow
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"runtime"
+	"time"
+)
+
+// kProtocolVersion is the version of the protocol spoken by this build. Bump
+// it when a change to the protocol isn't backwards compatible, and update
+// kMinProtocolVersion if older peers can no longer be supported.
+//
+//	0: Servers only implement the BuildHost, RepositoryHost and ServiceHost
+//	   services and don't support Hello. They don't have any capabilities.
+//	1: Adds the Hello RPC.
+//
+// Servers at version 0 are still supported. Clients check the capabilities of
+// the server before using features that these servers lack.
+const (
+	kProtocolVersion    = 1
+	kMinProtocolVersion = 0
+)
+
+// Optional features that a server can support. Clients should check for a
+// capability before relying on the corresponding feature.
+const (
+	CapabilityHostService  = "host-service"
+	CapabilityCommandInput = "command-input"
+	CapabilityTerminal     = "tty"
+	CapabilityEnvironment  = "env"
+	CapabilityTimeout      = "timeout"
+	CapabilityDetach       = "detach"
+	CapabilityJobHistory   = "job-history"
+	CapabilityAuditLog     = "audit-log"
+)
+
+var kCapabilities = []string{
+	CapabilityHostService,
+	CapabilityCommandInput,
+	CapabilityTerminal,
+	CapabilityEnvironment,
+	CapabilityTimeout,
+	CapabilityDetach,
+	CapabilityJobHistory,
+	CapabilityAuditLog,
+}
+
+// BuildVersion and BuildRevision identify the build. BuildRevision can be set
+// at build time using:
+//
+//	go install -ldflags "-X github.com/asankah/stonesthrow.BuildRevision=$(git rev-parse HEAD)" ...
+//
+// If it isn't, servers report the revision of the source at the host's
+// StonesthrowPath instead.
+var (
+	BuildVersion  = "dev"
+	BuildRevision = ""
+)
+
+var processStartTime = time.Now()
+
+// GetBuildRevision returns the revision of this build running on |host|. This
+// may involve running git, so servers only look it up once when they start.
+func GetBuildRevision(ctx context.Context, host *HostConfig) string {
+	revision := BuildRevision
+	if revision == "" && host.StonesthrowPath != "" {
+		revision, _ = RunCommandWithWorkDir(ctx, host.StonesthrowPath, "git", "rev-parse", "HEAD")
+	}
+	return revision
+}
+
+// NewHelloResult describes this build running on |host| at |revision|.
+func NewHelloResult(host *HostConfig, revision string) *HelloResult {
+	return &HelloResult{
+		ProtocolVersion:    kProtocolVersion,
+		MinProtocolVersion: kMinProtocolVersion,
+		Version:            BuildVersion,
+		Revision:           revision,
+		Capabilities:       kCapabilities,
+		Host:               host.Name,
+		Os:                 runtime.GOOS,
+		Arch:               runtime.GOARCH,
+		StartTime:          NewTimestampFromTime(processStartTime)}
+}
+
+// NewHelloOptions describes this build to a server.
+func NewHelloOptions() *HelloOptions {
+	return &HelloOptions{
+		ProtocolVersion:    kProtocolVersion,
+		MinProtocolVersion: kMinProtocolVersion,
+		Version:            BuildVersion,
+		Revision:           BuildRevision}
+}
+
+func (h *HelloResult) HasCapability(capability string) bool {
+	for _, c := range h.GetCapabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckClientCompatibility returns an IncompatibleVersionError if a server
+// running this build can't talk to the client described by |ho|.
+func CheckClientCompatibility(ho *HelloOptions) error {
+	if ho.GetProtocolVersion() < kMinProtocolVersion {
+		return NewIncompatibleVersionError(
+			"the client speaks protocol version %d, but the server needs at least %d. update the client.",
+			ho.GetProtocolVersion(), kMinProtocolVersion)
+	}
+	return nil
+}
+
+// CheckServerCompatibility returns an IncompatibleVersionError with advice on
+// how to fix the problem if a client running this build can't talk to the
+// server |server_name| described by |hr|.
+func CheckServerCompatibility(server_name string, hr *HelloResult) error {
+	if hr.GetProtocolVersion() < kMinProtocolVersion {
+		return NewIncompatibleVersionError(
+			"st_host on %s speaks protocol version %d, but this client needs at least %d. "+
+				"run 'st_client update' to update the server.",
+			server_name, hr.GetProtocolVersion(), kMinProtocolVersion)
+	}
+	if hr.GetMinProtocolVersion() > kProtocolVersion {
+		return NewIncompatibleVersionError(
+			"st_host on %s needs protocol version %d or later, but this client speaks %d. "+
+				"update st_client using 'go get -u github.com/asankah/stonesthrow/...'.",
+			server_name, hr.GetMinProtocolVersion(), kProtocolVersion)
+	}
+	return nil
+}
+
+// Hello exchanges version information with the server |server_name| at the
+// other end of |rpc_connection| and checks whether the client can talk to it.
+// Servers that predate the Hello RPC are described by a HelloResult for
+// protocol version 0 without any capabilities.
+func Hello(ctx context.Context, server_name string, rpc_connection *grpc.ClientConn) (*HelloResult, error) {
+	hello_result, err := NewServiceHostClient(rpc_connection).Hello(ctx, NewHelloOptions())
+	if grpc.Code(err) == codes.Unimplemented {
+		hello_result, err = &HelloResult{Host: server_name, ProtocolVersion: 0}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hello_result, CheckServerCompatibility(server_name, hello_result)
+}
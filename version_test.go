@@ -0,0 +1,93 @@
+package stonesthrow
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckServerCompatibility(t *testing.T) {
+	hr := &HelloResult{ProtocolVersion: kProtocolVersion, MinProtocolVersion: kMinProtocolVersion}
+	if err := CheckServerCompatibility("a", hr); err != nil {
+		t.Fatal(err)
+	}
+
+	hr = &HelloResult{ProtocolVersion: kMinProtocolVersion - 1}
+	if err := CheckServerCompatibility("a", hr); !IsIncompatibleVersionError(err) {
+		t.Fatalf("expected an IncompatibleVersionError for an old server. got %v", err)
+	}
+
+	hr = &HelloResult{ProtocolVersion: kProtocolVersion + 2, MinProtocolVersion: kProtocolVersion + 1}
+	if err := CheckServerCompatibility("a", hr); !IsIncompatibleVersionError(err) {
+		t.Fatalf("expected an IncompatibleVersionError for a new server. got %v", err)
+	}
+}
+
+func TestCheckClientCompatibility(t *testing.T) {
+	if err := CheckClientCompatibility(NewHelloOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckClientCompatibility(&HelloOptions{ProtocolVersion: kMinProtocolVersion - 1}); !IsIncompatibleVersionError(err) {
+		t.Fatalf("expected an IncompatibleVersionError for an old client. got %v", err)
+	}
+}
+
+func TestHelloResult_HasCapability(t *testing.T) {
+	hr := NewHelloResult(&HostConfig{Name: "a"}, "")
+	if hr.Host != "a" || !hr.HasCapability(CapabilityHostService) {
+		t.Fatalf("unexpected hello result %v", hr)
+	}
+	if hr.HasCapability("teleport") {
+		t.Fatal("unexpected capability")
+	}
+}
+
+func TestHello_LegacyServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Servers that predate Hello don't know about the method.
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	host := &HostConfig{Name: "a"}
+	conn, err := connectToLocalEndpoint(context.Background(), Config{Host: host},
+		Endpoint{Network: "tcp", Address: listener.Addr().String()}, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	hr, err := Hello(context.Background(), "a", conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.GetHost() != "a" || len(hr.GetCapabilities()) != 0 {
+		t.Fatalf("unexpected hello result %v", hr)
+	}
+}
+
+func TestCommandCapabilities(t *testing.T) {
+	if capabilities := commandCapabilities(&RunOptions{Command: &ShellCommand{}}, 0); len(capabilities) != 0 {
+		t.Fatalf("unexpected capabilities %v", capabilities)
+	}
+
+	ro := &RunOptions{
+		Detach:  true,
+		Command: &ShellCommand{Env: map[string]string{"GTEST_FILTER": "Foo.*"}}}
+	capabilities := commandCapabilities(ro, time.Minute)
+	expected := []string{CapabilityDetach, CapabilityEnvironment, CapabilityTimeout}
+	if !reflect.DeepEqual(capabilities, expected) {
+		t.Fatalf("unexpected capabilities %v", capabilities)
+	}
+
+	// Servers that predate Hello have none of them.
+	if (&HelloResult{}).HasCapability(CapabilityTerminal) {
+		t.Fatal("unexpected capability")
+	}
+}